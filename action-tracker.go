@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+const (
+	mentionPollInterval = 30 * time.Second // how often new transcript lines are matched against open items
+	mentionMaxInterval  = 10 * time.Minute // longest the interval grows while the model fails
+	mentionBatchLines   = 50               // most transcript lines sent to the model at once
+)
+
+const mentionSystemPrompt = `You track open action items during a meeting. You get a numbered list of open items, labelled [n], and new transcript lines, labelled [Ln].
+Reply with JSON only, no prose, in this shape:
+{"mentions": [{"item": n, "line": n, "resolved": true}]}
+Report an item when a transcript line clearly refers to it. Set resolved to true only when the line says the item was completed or is no longer needed.
+Use an empty array when no item is referred to.`
+
+// itemMentions is the JSON the model is asked to reply with when matching transcript lines
+// against open action items
+type itemMentions struct {
+	Mentions []struct {
+		Item     int  `json:"item"`
+		Line     int  `json:"line"`
+		Resolved bool `json:"resolved"`
+	} `json:"mentions"`
+}
+
+// ListOpenActionItems retrieves the open action items of every workspace that is not archived,
+// the ones due first at the top
+func ListOpenActionItems() ([]ActionItem, error) {
+	var items []ActionItem
+	result := DB.Preload("Workspace").
+		Joins("JOIN workspaces ON workspaces.id = action_items.workspace_id AND workspaces.archived_at IS NULL AND workspaces.deleted_at IS NULL").
+		Where("action_items.status = ?", ActionItemOpen).
+		Order("action_items.due_date IS NULL, action_items.due_date ASC, action_items.created_at ASC").
+		Find(&items)
+	if result.Error != nil {
+		log.Printf("Failed to get open action items: %v", result.Error)
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// GetCarriedOverActionItems retrieves the action items of a workspace left open by the
+// meetings before a session, oldest first
+func GetCarriedOverActionItems(sessionID uint) ([]ActionItem, error) {
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []ActionItem
+	result := DB.Where("workspace_id = ? AND status = ? AND created_at < ?", session.WorkspaceID, ActionItemOpen, session.StartTime).
+		Where("session_id IS NULL OR session_id <> ?", sessionID).
+		Order("created_at ASC").Find(&items)
+	if result.Error != nil {
+		log.Printf("Failed to get carried over action items for session %d: %v", sessionID, result.Error)
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// parseItemMentions reads the model reply, tolerating a code fence or text around the JSON
+func parseItemMentions(reply string) (*itemMentions, error) {
+	text := trimMarkdownFence(reply)
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in model reply")
+	}
+
+	var mentions itemMentions
+	if err := json.Unmarshal([]byte(text[start:end+1]), &mentions); err != nil {
+		return nil, fmt.Errorf("failed to parse model reply: %v", err)
+	}
+	return &mentions, nil
+}
+
+// trackActionItemMentions asks the model whether new transcript lines of a session refer to
+// items carried over from earlier meetings. Referenced items are marked as mentioned, and as
+// done when the line says they were completed. It returns the updated items.
+func trackActionItemMentions(ctx context.Context, sessionID uint, items []ActionItem, records []TranscriptionRecord) ([]ActionItem, error) {
+	if len(records) == 0 || len(items) == 0 {
+		return nil, nil
+	}
+	provider, err := chatProviderForWorkspace(items[0].WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("Open action items:\n")
+	for i, item := range items {
+		prompt.WriteString(fmt.Sprintf("[%d] %s", i+1, item.Text))
+		if item.Owner != "" {
+			prompt.WriteString(" (owner: " + item.Owner + ")")
+		}
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("\nNew transcript lines:\n")
+	for i, record := range records {
+		prompt.WriteString(fmt.Sprintf("[L%d] %s\n", i+1, formatTranscriptLine(record)))
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	reply, _, err := provider.Complete(requestCtx, []ChatMessage{
+		{Role: "system", Content: mentionSystemPrompt},
+		{Role: "user", Content: prompt.String()},
+	})
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to match action items: %v", err)
+	}
+	mentions, err := parseItemMentions(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make(map[uint]*ActionItem)
+	var order []uint
+	now := time.Now()
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		for _, mention := range mentions.Mentions {
+			if mention.Item < 1 || mention.Item > len(items) || mention.Line < 1 || mention.Line > len(records) {
+				continue
+			}
+			item := &items[mention.Item-1]
+			changes := map[string]interface{}{
+				"mentioned_at": now,
+				"mentioned_in": sessionID,
+				"mention_text": formatTranscriptLine(records[mention.Line-1]),
+			}
+			if mention.Resolved {
+				changes["status"] = ActionItemDone
+			}
+			if err := tx.Model(item).Updates(changes).Error; err != nil {
+				return err
+			}
+			if _, seen := updated[item.ID]; !seen {
+				order = append(order, item.ID)
+			}
+			updated[item.ID] = item
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to record action item mentions for session %d: %v", sessionID, err)
+		return nil, err
+	}
+
+	result := make([]ActionItem, 0, len(order))
+	for _, id := range order {
+		item, err := GetActionItemByID(id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *item)
+	}
+	if len(result) > 0 {
+		log.Printf("Transcript of session %d referred to %d open action items", sessionID, len(result))
+	}
+	return result, nil
+}
+
+// announceCarriedOverActionItems reports the items left open by earlier meetings of the
+// workspace when a session starts, through the actionItemsCarriedOver event
+func (a *App) announceCarriedOverActionItems(session *Session) {
+	items, err := GetCarriedOverActionItems(session.ID)
+	if err != nil || len(items) == 0 {
+		return
+	}
+	runtime.EventsEmit(a.ctx, "actionItemsCarriedOver", map[string]interface{}{
+		"sessionId":   session.ID,
+		"workspaceId": session.WorkspaceID,
+		"items":       items,
+	})
+}
+
+// mentionTracker matches the transcript of an open session against the action items carried
+// over from earlier meetings. It runs on its own, whether or not live notes are enabled.
+type mentionTracker struct {
+	app          *App
+	sessionID    uint
+	stop         chan struct{}
+	lastRecordID uint // last transcript record matched
+	interval     time.Duration
+}
+
+// Mention trackers, by session ID
+var mentionTrackers sync.Map
+
+// startMentionTracker starts tracking mentions of carried over action items in a session,
+// unless earlier meetings left nothing open
+func (a *App) startMentionTracker(session *Session) {
+	items, err := GetCarriedOverActionItems(session.ID)
+	if err != nil || len(items) == 0 {
+		return
+	}
+	tracker := &mentionTracker{
+		app:       a,
+		sessionID: session.ID,
+		stop:      make(chan struct{}),
+		interval:  mentionPollInterval,
+	}
+	if _, loaded := mentionTrackers.LoadOrStore(session.ID, tracker); loaded {
+		return
+	}
+	go tracker.run()
+}
+
+// stopMentionTracker stops tracking mentions in a session. Lines not matched yet are matched
+// in a last pass in the background.
+func stopMentionTracker(sessionID uint) {
+	if tracker, ok := mentionTrackers.LoadAndDelete(sessionID); ok {
+		close(tracker.(*mentionTracker).stop)
+	}
+}
+
+// run matches new transcript lines until the tracker is stopped, the session ends or no
+// carried over item is left open
+func (t *mentionTracker) run() {
+	defer mentionTrackers.CompareAndDelete(t.sessionID, t)
+
+	for {
+		select {
+		case <-t.stop:
+			t.matchRemaining()
+			return
+		case <-time.After(t.interval):
+			session, err := GetSessionByID(t.sessionID)
+			if err != nil {
+				return
+			}
+			if session.Status == SessionStatusStopped {
+				t.matchRemaining()
+				return
+			}
+			if session.Status == SessionStatusActive && !t.match(false) {
+				return
+			}
+		}
+	}
+}
+
+// matchRemaining matches every line left once the session is over, a batch at a time. It
+// gives up when a batch fails, there is no later pass to retry in.
+func (t *mentionTracker) matchRemaining() {
+	for {
+		matched := t.lastRecordID
+		if !t.match(true) || t.lastRecordID == matched {
+			return
+		}
+	}
+}
+
+// match sends the transcript lines not matched yet to the model and reports the items they
+// refer to through the actionItemsMentioned event. Unless final is set, lines from the last
+// few seconds are held back while their captions settle. It returns false once no carried
+// over item is left open.
+func (t *mentionTracker) match(final bool) bool {
+	items, err := GetCarriedOverActionItems(t.sessionID)
+	if err != nil {
+		log.Printf("Failed to get carried over action items for session %d: %v", t.sessionID, err)
+		return true
+	}
+	if len(items) == 0 {
+		return false
+	}
+
+	var records []TranscriptionRecord
+	result := DB.Where("session_id = ? AND id > ?", t.sessionID, t.lastRecordID).Order("id ASC").Limit(mentionBatchLines).Find(&records)
+	if result.Error != nil {
+		log.Printf("Failed to get transcript of session %d for action item mentions: %v", t.sessionID, result.Error)
+		return true
+	}
+	if !final {
+		settled := time.Now().Add(-liveNotesSettleTime)
+		for i, record := range records {
+			if record.UpdatedAt.After(settled) {
+				records = records[:i]
+				break
+			}
+		}
+	}
+	if len(records) == 0 {
+		return true
+	}
+
+	mentioned, err := trackActionItemMentions(context.Background(), t.sessionID, items, records)
+	if err != nil {
+		// Try the same lines again later, less often while the model is unavailable
+		t.interval = min(2*t.interval, mentionMaxInterval)
+		log.Printf("Failed to track action item mentions for session %d, retrying in %s: %v", t.sessionID, t.interval, err)
+		return true
+	}
+	t.lastRecordID = records[len(records)-1].ID
+	t.interval = mentionPollInterval
+	if len(mentioned) > 0 {
+		runtime.EventsEmit(t.app.ctx, "actionItemsMentioned", map[string]interface{}{
+			"sessionId": t.sessionID,
+			"items":     mentioned,
+		})
+	}
+	return true
+}
+
+// Action item tracker methods exposed to the frontend
+
+// ListOpenActionItems retrieves the open action items across workspaces
+func (a *App) ListOpenActionItems() ([]ActionItem, error) {
+	return ListOpenActionItems()
+}
+
+// GetCarriedOverActionItems retrieves the action items earlier meetings left open for a session
+func (a *App) GetCarriedOverActionItems(sessionID uint) ([]ActionItem, error) {
+	return GetCarriedOverActionItems(sessionID)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMentionTrackerMatchesRemainingLines(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Launch", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &scriptedProvider{reply: func(string, string) string { return `{"mentions": []}` }}
+	chatProviders["scripted"] = provider
+	t.Cleanup(func() { delete(chatProviders, "scripted") })
+	if err := DB.Model(workspace).Update("chat_backend", "scripted").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	earlier := ActionItem{WorkspaceID: workspace.ID, Text: "Send the deck", Status: ActionItemOpen, CreatedAt: time.Now().Add(-24 * time.Hour)}
+	if err := DB.Create(&earlier).Error; err != nil {
+		t.Fatal(err)
+	}
+	session, err := StartSession(workspace.ID, "", "zoom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := 2*mentionBatchLines + 7
+	for i := 0; i < lines; i++ {
+		addCaption(t, workspace.ID, fmt.Sprintf("m%d", i), "Alice", fmt.Sprintf("line %d", i), time.Now())
+	}
+
+	tracker := &mentionTracker{sessionID: session.ID, interval: mentionPollInterval}
+	tracker.matchRemaining()
+
+	if len(provider.requests) != 3 {
+		t.Fatalf("sent %d requests, want 3 batches for %d lines", len(provider.requests), lines)
+	}
+	if last := provider.requests[2]; !strings.Contains(last, fmt.Sprintf("line %d\n", lines-1)) {
+		t.Errorf("last request does not end with the last line:\n%s", last)
+	}
+	var lastRecord TranscriptionRecord
+	if err := DB.Order("id DESC").First(&lastRecord).Error; err != nil {
+		t.Fatal(err)
+	}
+	if tracker.lastRecordID != lastRecord.ID {
+		t.Errorf("last matched record = %d, want %d", tracker.lastRecordID, lastRecord.ID)
+	}
+}
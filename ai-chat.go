@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,19 +23,36 @@ type ChatMessage struct {
 	Content string `json:"content"`
 }
 
-// ChatRequest represents the request payload for chat WebSocket
-// Removed WorkspaceID, as WebSocket should not use WorkspaceID. Only the method should handle workspace context.
+// ChatRequest represents the request payload for chat WebSocket.
+// The workspace context is already part of the message; WorkspaceID is only echoed back
+// so that responses can be attributed to a workspace.
 type ChatRequest struct {
+	RequestID    string `json:"request_id"`
+	WorkspaceID  uint   `json:"workspace_id,omitempty"`
 	Message      string `json:"message"`
 	SystemPrompt string `json:"system_prompt"`
+	Model        string `json:"model,omitempty"`
 }
 
 // WebSocketResponse represents different types of WebSocket responses
 type WebSocketResponse struct {
-	Type    string `json:"type"`    // "start", "token", "complete", "error", "info"
-	Content string `json:"content"` // For token type
-	Message string `json:"message"` // For other types
-	Done    bool   `json:"done"`    // For token type
+	Type        string `json:"type"`         // "start", "token", "complete", "error", "info", "cancelled"
+	RequestID   string `json:"request_id"`   // Request the response belongs to
+	WorkspaceID uint   `json:"workspace_id"` // Workspace of the request
+	Content     string `json:"content"`      // For token type
+	Message     string `json:"message"`      // For other types
+	Done        bool   `json:"done"`         // For token type
+
+	// Sent with the complete type
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+}
+
+// cancelFrame asks the Python backend to stop generating a reply
+type cancelFrame struct {
+	Type      string `json:"type"` // always "cancel"
+	RequestID string `json:"request_id"`
 }
 
 // WebSocket connection manager
@@ -41,6 +61,39 @@ type WebSocketManager struct {
 	isConnected bool
 	mutex       sync.Mutex
 	app         *App
+
+	// Generations being streamed over the socket, by request ID
+	streams map[string]*bridgeStream
+}
+
+// bridgeStream routes the responses of one chat request back to its caller
+type bridgeStream struct {
+	requestID   string
+	workspaceID uint
+	onToken     func(token string)
+	done        chan error
+	stats       ChatCompletionStats // Filled in from the complete response
+
+	// Held while onToken runs, so Stream never returns in the middle of a token
+	tokenMutex sync.Mutex
+	stopped    bool
+}
+
+// deliver passes token to onToken unless the caller has stopped reading the reply
+func (s *bridgeStream) deliver(token string) {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+	if !s.stopped {
+		s.onToken(token)
+	}
+}
+
+// stop drops every later token. It waits for a token being delivered, so once it returns
+// the caller owns everything onToken wrote to.
+func (s *bridgeStream) stop() {
+	s.tokenMutex.Lock()
+	s.stopped = true
+	s.tokenMutex.Unlock()
 }
 
 var wsManager *WebSocketManager
@@ -141,6 +194,8 @@ func (wm *WebSocketManager) listen() {
 
 		log.Printf("WebSocket connection closed")
 
+		wm.finishAllStreams(fmt.Errorf("WebSocket connection closed"))
+
 		// Emit disconnection status to frontend
 		runtime.EventsEmit(wm.app.ctx, "websocketDisconnected", map[string]interface{}{
 			"connected": false,
@@ -204,30 +259,35 @@ func (wm *WebSocketManager) listen() {
 		// Handle different response types
 		switch wsResponse.Type {
 		case "start":
-			runtime.EventsEmit(wm.app.ctx, "chatStreamStart", map[string]interface{}{
-				"message": wsResponse.Message,
-			})
+			// The start event is emitted by runChatGeneration
 
 		case "token":
-			runtime.EventsEmit(wm.app.ctx, "chatStreamChunk", map[string]interface{}{
-				"token": wsResponse.Content,
-				"done":  wsResponse.Done,
-			})
+			// Tokens of a cancelled request may still arrive after it was finished locally
+			if stream := wm.lookupStream(wsResponse.RequestID); stream != nil && wsResponse.Content != "" {
+				stream.deliver(wsResponse.Content)
+			}
 
 		case "complete":
-			runtime.EventsEmit(wm.app.ctx, "chatStreamDone", map[string]interface{}{
-				"done":    true,
-				"message": wsResponse.Message,
-			})
+			if stream := wm.lookupStream(wsResponse.RequestID); stream != nil {
+				stream.stats = ChatCompletionStats{
+					Model:            wsResponse.Model,
+					PromptTokens:     wsResponse.PromptTokens,
+					CompletionTokens: wsResponse.CompletionTokens,
+				}
+			}
+			wm.finishStream(wsResponse.RequestID, nil)
 
 		case "error":
-			runtime.EventsEmit(wm.app.ctx, "chatStreamError", map[string]interface{}{
-				"error": wsResponse.Message,
-			})
+			wm.finishStream(wsResponse.RequestID, fmt.Errorf("%s", wsResponse.Message))
+
+		case "cancelled":
+			wm.finishStream(wsResponse.RequestID, context.Canceled)
 
 		case "info":
 			runtime.EventsEmit(wm.app.ctx, "chatStreamInfo", map[string]interface{}{
-				"message": wsResponse.Message,
+				"message":     wsResponse.Message,
+				"requestId":   wsResponse.RequestID,
+				"workspaceId": wsResponse.WorkspaceID,
 			})
 
 		default:
@@ -236,35 +296,14 @@ func (wm *WebSocketManager) listen() {
 	}
 }
 
-// SendMessage sends a message through the existing WebSocket connection
-// Now includes transcription, chat_history, and meeting_notes in the payload
-func (wm *WebSocketManager) SendMessage(workspaceID uint, message string, systemPrompt string) error {
-	wm.mutex.Lock()
-	defer wm.mutex.Unlock()
-
+// SendMessage sends a chat request through the existing WebSocket connection.
+// The workspace context is already part of the message, see buildChatPrompt.
+func (wm *WebSocketManager) SendMessage(request ChatRequest) error {
 	if !wm.isConnected || wm.conn == nil {
 		return fmt.Errorf("WebSocket not connected")
 	}
 
-	// Fetch additional context for the workspace
-	transcriptions, _ := GetTranscriptionMessagesByWorkspace(workspaceID)
-	chatHistory, _ := GetAIChatMessagesByWorkspace(workspaceID)
-	meetingNotesList, _ := GetMeetingNotesByWorkspace(workspaceID)
-	var meetingNotes string
-	if len(meetingNotesList) > 0 {
-		meetingNotes = meetingNotesList[0].Text
-	}
-
-	// Prepare the payload
-	payload := map[string]interface{}{
-		"message":       message,
-		"system_prompt": systemPrompt,
-		"transcription": transcriptions,
-		"chat-history":  chatHistory,
-		"meeting-notes": meetingNotes,
-	}
-
-	jsonData, err := json.Marshal(payload)
+	jsonData, err := json.Marshal(request)
 	if err != nil {
 		return fmt.Errorf("failed to marshal chat request: %v", err)
 	}
@@ -279,6 +318,124 @@ func (wm *WebSocketManager) SendMessage(workspaceID uint, message string, system
 	return nil
 }
 
+// sendCancel asks the Python backend to stop generating the reply for requestID
+func (wm *WebSocketManager) sendCancel(requestID string) error {
+	wm.mutex.Lock()
+	defer wm.mutex.Unlock()
+
+	if !wm.isConnected || wm.conn == nil {
+		return fmt.Errorf("WebSocket not connected")
+	}
+
+	jsonData, err := json.Marshal(cancelFrame{Type: "cancel", RequestID: requestID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cancel request: %v", err)
+	}
+	if err := wm.conn.WriteMessage(websocket.TextMessage, jsonData); err != nil {
+		wm.isConnected = false
+		return fmt.Errorf("failed to send WebSocket message: %v", err)
+	}
+	return nil
+}
+
+// Stream sends a chat request and blocks until the Python backend completes the reply.
+// Several requests can be streamed at once; responses are routed by request ID.
+func (wm *WebSocketManager) Stream(ctx context.Context, request ChatRequest, onToken func(token string)) (*ChatCompletionStats, error) {
+	if request.RequestID == "" {
+		request.RequestID = newMessageID("chat")
+	}
+	// Cancelled before it was sent, don't make the backend start generating
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	stream := &bridgeStream{
+		requestID:   request.RequestID,
+		workspaceID: request.WorkspaceID,
+		onToken:     onToken,
+		done:        make(chan error, 1),
+	}
+
+	wm.mutex.Lock()
+	if err := wm.SendMessage(request); err != nil {
+		wm.mutex.Unlock()
+		return nil, err
+	}
+	if wm.streams == nil {
+		wm.streams = make(map[string]*bridgeStream)
+	}
+	wm.streams[request.RequestID] = stream
+	wm.mutex.Unlock()
+	defer stream.stop()
+
+	select {
+	case err := <-stream.done:
+		if err != nil {
+			return nil, err
+		}
+		return &stream.stats, nil
+	case <-ctx.Done():
+		wm.mutex.Lock()
+		if wm.streams[request.RequestID] == stream {
+			delete(wm.streams, request.RequestID)
+		}
+		wm.mutex.Unlock()
+		if err := wm.sendCancel(request.RequestID); err != nil {
+			log.Printf("Failed to send cancel for request %s: %v", request.RequestID, err)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// lookupStream returns the stream a response for requestID belongs to, or nil.
+// Responses without a request ID come from older backends, which only handle one
+// request at a time, so they go to the only stream in progress.
+func (wm *WebSocketManager) lookupStream(requestID string) *bridgeStream {
+	wm.mutex.Lock()
+	defer wm.mutex.Unlock()
+	return wm.lookupStreamLocked(requestID)
+}
+
+// lookupStreamLocked is lookupStream for callers holding wm.mutex
+func (wm *WebSocketManager) lookupStreamLocked(requestID string) *bridgeStream {
+	if requestID != "" {
+		return wm.streams[requestID]
+	}
+	if len(wm.streams) == 1 {
+		for _, stream := range wm.streams {
+			return stream
+		}
+	}
+	return nil
+}
+
+// finishStream completes the generation of requestID with err (nil on success).
+// The listen loop and finishAllStreams can both finish the same request; only the call
+// that removes the stream reports to it, done only has room for one result.
+func (wm *WebSocketManager) finishStream(requestID string, err error) {
+	wm.mutex.Lock()
+	stream := wm.lookupStreamLocked(requestID)
+	if stream != nil {
+		delete(wm.streams, stream.requestID)
+	}
+	wm.mutex.Unlock()
+
+	if stream != nil {
+		stream.done <- err
+	}
+}
+
+// finishAllStreams completes every generation in progress with err
+func (wm *WebSocketManager) finishAllStreams(err error) {
+	wm.mutex.Lock()
+	streams := wm.streams
+	wm.streams = nil
+	wm.mutex.Unlock()
+
+	for _, stream := range streams {
+		stream.done <- err
+	}
+}
+
 // Close closes the WebSocket connection
 func (wm *WebSocketManager) Close() {
 	wm.mutex.Lock()
@@ -301,36 +458,214 @@ func (a *App) DisconnectWebSocket() {
 	}
 }
 
-// SendChatMessage sends a message through the existing WebSocket connection, now with workspaceID
-func (a *App) SendChatMessage(workspaceID uint, message string, systemPrompt string) error {
-	if wsManager == nil {
-		return fmt.Errorf("WebSocket not initialized. Call InitializeWebSocket first")
+// readyChatProvider returns the chat provider of a workspace if it can take requests
+func readyChatProvider(workspaceID uint) (ChatProvider, error) {
+	provider, err := chatProviderForWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if provider.Name() == ChatBackendPython && wsManager == nil {
+		return nil, fmt.Errorf("WebSocket not initialized. Call InitializeWebSocket first")
+	}
+	return provider, nil
+}
+
+// SendChatMessage sends a message to the chat provider selected for the workspace.
+// The reply is streamed back through chatStream* events; the returned request ID
+// can be passed to CancelChatGeneration. An empty systemPrompt uses the workspace's
+// chat prompt template. Messages starting with a command such as "/recap 5m" run
+// that command instead.
+func (a *App) SendChatMessage(workspaceID uint, message string, systemPrompt string) (string, error) {
+	provider, err := readyChatProvider(workspaceID)
+	if err != nil {
+		return "", err
 	}
-	return wsManager.SendMessage(workspaceID, message, systemPrompt)
+
+	if command, args, ok := parseAssistantCommand(message); ok {
+		request, err := commandChatRequest(workspaceID, command, args, message)
+		if err != nil {
+			return "", err
+		}
+		ctx := trackGeneration(request.RequestID)
+		go a.runChatGeneration(ctx, provider, *request)
+		return request.RequestID, nil
+	}
+
+	var chatContext ChatContext
+	if strings.TrimSpace(systemPrompt) == "" {
+		chatContext = buildChatContext(workspaceID, workspaceChatPromptBody(workspaceID), message)
+		systemPrompt = renderChatSystemPrompt(workspaceID, message, &chatContext)
+	} else {
+		chatContext = buildChatContext(workspaceID, systemPrompt, message)
+	}
+	runtime.EventsEmit(a.ctx, "chatContextInfo", chatContext.Info)
+
+	// Store the question so the reply can be linked to it
+	var replyToID *uint
+	if userMessage, err := CreateAIChatMessage(workspaceID, "user", message); err == nil {
+		replyToID = &userMessage.ID
+	}
+
+	request := ChatStreamRequest{
+		RequestID:   newMessageID("chat"),
+		WorkspaceID: workspaceID,
+		Messages: []ChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: buildChatPrompt(chatContext, message)},
+		},
+		ReplyToID: replyToID,
+		Citations: chatContext.Citations(),
+	}
+
+	ctx := trackGeneration(request.RequestID)
+	go a.runChatGeneration(ctx, provider, request)
+	return request.RequestID, nil
 }
 
-// SendSimpleChatMessage is a convenience method for sending a single user message
-func (a *App) SendSimpleChatMessage(workspaceID uint, userMessage string) error {
-	return a.SendChatMessage(workspaceID, userMessage, "You are a helpful AI assistant.")
+// SendSimpleChatMessage sends a single user message with the workspace's chat prompt template
+func (a *App) SendSimpleChatMessage(workspaceID uint, userMessage string) (string, error) {
+	return a.SendChatMessage(workspaceID, userMessage, "")
 }
 
 // SendChatWithSystemPrompt sends a message with a custom system prompt
-func (a *App) SendChatWithSystemPrompt(workspaceID uint, userMessage string, systemPrompt string) error {
+func (a *App) SendChatWithSystemPrompt(workspaceID uint, userMessage string, systemPrompt string) (string, error) {
 	return a.SendChatMessage(workspaceID, userMessage, systemPrompt)
 }
 
+// PythonBridgeProvider is a ChatProvider backed by the Python backend's /ws/chat endpoint
+type PythonBridgeProvider struct{}
+
+func (p *PythonBridgeProvider) Name() string {
+	return ChatBackendPython
+}
+
+func (p *PythonBridgeProvider) StreamChat(ctx context.Context, req ChatStreamRequest, onToken func(token string)) (*ChatCompletionStats, error) {
+	if wsManager == nil {
+		return nil, fmt.Errorf("WebSocket not initialized. Call InitializeWebSocket first")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = GetSetting(SettingOllamaModel)
+	}
+
+	systemPrompt, message := splitSystemPrompt(req.Messages)
+	stats, err := wsManager.Stream(ctx, ChatRequest{
+		RequestID:    req.RequestID,
+		WorkspaceID:  req.WorkspaceID,
+		Message:      message,
+		SystemPrompt: systemPrompt,
+		Model:        model,
+	}, onToken)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Model == "" {
+		stats.Model = model
+	}
+	return stats, nil
+}
+
+// Complete uses the Python backend's non-streaming /chat endpoint
+func (p *PythonBridgeProvider) Complete(ctx context.Context, messages []ChatMessage) (string, *ChatCompletionStats, error) {
+	model := GetSetting(SettingOllamaModel)
+	systemPrompt, message := splitSystemPrompt(messages)
+	payload, err := json.Marshal(ChatRequest{Message: message, SystemPrompt: systemPrompt, Model: model})
+	if err != nil {
+		return "", nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost:8000/chat", bytes.NewReader(payload))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("Python backend not accessible at localhost:8000: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", nil, fmt.Errorf("Python chat API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Response string `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", nil, fmt.Errorf("failed to parse chat response: %v", err)
+	}
+	return result.Response, &ChatCompletionStats{Model: model}, nil
+}
+
+func (p *PythonBridgeProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:8000/chat/models", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Python backend not accessible at localhost:8000: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse model list: %v", err)
+	}
+
+	models := make([]string, 0, len(result.Models))
+	for _, model := range result.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+func (p *PythonBridgeProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:    true,
+		Cancellation: true,
+		ModelListing: true,
+	}
+}
+
+// splitSystemPrompt separates the system prompt from the rest of the conversation,
+// flattening the remaining messages into a single user message
+func splitSystemPrompt(messages []ChatMessage) (string, string) {
+	var systemPrompt string
+	var parts []string
+	for _, message := range messages {
+		if message.Role == "system" {
+			systemPrompt = message.Content
+			continue
+		}
+		parts = append(parts, message.Content)
+	}
+	return systemPrompt, strings.Join(parts, "\n\n")
+}
+
 // MarkdownAgentRequest represents the request payload for markdown agent WebSocket
 type MarkdownAgentRequest struct {
-	Message string `json:"message"`
+	RequestID string `json:"request_id"`
+	Message   string `json:"message"`
 }
 
 // MarkdownAgentResponse represents the markdown agent WebSocket response
 type MarkdownAgentResponse struct {
-	Type    string `json:"type"`    // "start", "token", "complete", "error", "info"
-	Content string `json:"content"` // For token type
-	Message string `json:"message"` // For start, complete, error, info types
-	Model   string `json:"model"`   // Model used (for token type)
-	Done    bool   `json:"done"`    // For token type to indicate completion
+	Type      string `json:"type"`       // "start", "token", "complete", "error", "info", "cancelled"
+	RequestID string `json:"request_id"` // Request the response belongs to
+	Content   string `json:"content"`    // For token type
+	Message   string `json:"message"`    // For start, complete, error, info types
+	Model     string `json:"model"`      // Model used (for token type)
+	Done      bool   `json:"done"`       // For token type to indicate completion
 }
 
 // Markdown Agent WebSocket connection manager
@@ -339,6 +674,13 @@ type MarkdownAgentWebSocketManager struct {
 	isConnected bool
 	mutex       sync.Mutex
 	app         *App
+
+	// Requests sent to the agent that have not completed yet
+	pending   map[string]bool
+	cancelled map[string]bool
+
+	// Meeting notes generations in progress, by request ID
+	notesRequests map[string]*notesAgentRequest
 }
 
 var markdownWsManager *MarkdownAgentWebSocketManager
@@ -400,6 +742,13 @@ func (mwm *MarkdownAgentWebSocketManager) listen() {
 		if mwm.conn != nil {
 			mwm.conn.Close()
 		}
+		// Requests in flight are lost with the connection
+		mwm.pending = nil
+		mwm.cancelled = nil
+		for _, notesRequest := range mwm.notesRequests {
+			notesRequest.finish()
+		}
+		mwm.notesRequests = nil
 		mwm.mutex.Unlock()
 
 		log.Printf("Markdown Agent WebSocket connection closed")
@@ -464,36 +813,59 @@ func (mwm *MarkdownAgentWebSocketManager) listen() {
 		// Reset read timeout on successful message
 		mwm.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 
+		// Drop whatever a cancelled request still sends before the backend notices the cancel
+		if mwm.isCancelled(agentResponse.RequestID) && agentResponse.Type != "cancelled" {
+			if agentResponse.Type == "complete" || agentResponse.Type == "error" {
+				mwm.finishRequest(agentResponse.RequestID).finish()
+			}
+			continue
+		}
+
 		// Handle different response types for streaming markdown agent
 		switch agentResponse.Type {
 		case "start":
 			runtime.EventsEmit(mwm.app.ctx, "markdownAgentStreamStart", map[string]interface{}{
-				"message": agentResponse.Message,
+				"message":   agentResponse.Message,
+				"requestId": agentResponse.RequestID,
 			})
 
 		case "token":
+			mwm.appendNotesOutput(agentResponse.RequestID, agentResponse.Content)
 			runtime.EventsEmit(mwm.app.ctx, "markdownAgentStreamChunk", map[string]interface{}{
-				"token": agentResponse.Content,
-				"done":  agentResponse.Done,
-				"model": agentResponse.Model,
+				"token":     agentResponse.Content,
+				"done":      agentResponse.Done,
+				"model":     agentResponse.Model,
+				"requestId": agentResponse.RequestID,
 			})
 
 		case "complete":
+			notesRequest := mwm.finishRequest(agentResponse.RequestID)
 			runtime.EventsEmit(mwm.app.ctx, "markdownAgentStreamDone", map[string]interface{}{
-				"done":    true,
-				"message": agentResponse.Message,
+				"done":      true,
+				"message":   agentResponse.Message,
+				"requestId": agentResponse.RequestID,
 			})
+			if notesRequest != nil {
+				mwm.app.proposeNotesPatch(agentResponse.RequestID, notesRequest)
+			}
 
 		case "info":
 			runtime.EventsEmit(mwm.app.ctx, "markdownAgentStreamInfo", map[string]interface{}{
-				"message": agentResponse.Message,
+				"message":   agentResponse.Message,
+				"requestId": agentResponse.RequestID,
 			})
 
 		case "error":
+			mwm.finishRequest(agentResponse.RequestID).finish()
 			runtime.EventsEmit(mwm.app.ctx, "markdownAgentError", map[string]interface{}{
-				"error": agentResponse.Message,
+				"error":     agentResponse.Message,
+				"requestId": agentResponse.RequestID,
 			})
 
+		case "cancelled":
+			// Already reported to the frontend by Cancel
+			mwm.finishRequest(agentResponse.RequestID).finish()
+
 		default:
 			log.Printf("Unknown Markdown Agent WebSocket response type: %s", agentResponse.Type)
 		}
@@ -501,7 +873,7 @@ func (mwm *MarkdownAgentWebSocketManager) listen() {
 }
 
 // SendMessage sends a message through the markdown agent WebSocket connection
-func (mwm *MarkdownAgentWebSocketManager) SendMessage(message string) error {
+func (mwm *MarkdownAgentWebSocketManager) SendMessage(requestID, message string) error {
 	mwm.mutex.Lock()
 	defer mwm.mutex.Unlock()
 
@@ -510,7 +882,8 @@ func (mwm *MarkdownAgentWebSocketManager) SendMessage(message string) error {
 	}
 
 	agentRequest := MarkdownAgentRequest{
-		Message: message,
+		RequestID: requestID,
+		Message:   message,
 	}
 
 	jsonData, err := json.Marshal(agentRequest)
@@ -524,10 +897,91 @@ func (mwm *MarkdownAgentWebSocketManager) SendMessage(message string) error {
 		return fmt.Errorf("failed to send Markdown Agent WebSocket message: %v", err)
 	}
 
+	if mwm.pending == nil {
+		mwm.pending = make(map[string]bool)
+	}
+	mwm.pending[requestID] = true
+
 	log.Printf("Message sent to Markdown Agent WebSocket: %s", string(jsonData))
 	return nil
 }
 
+// Cancel asks the markdown agent to stop generating the notes for requestID
+func (mwm *MarkdownAgentWebSocketManager) Cancel(requestID string) error {
+	mwm.mutex.Lock()
+	defer mwm.mutex.Unlock()
+
+	if !mwm.pending[requestID] || mwm.cancelled[requestID] {
+		return fmt.Errorf("no markdown generation in progress for request %s", requestID)
+	}
+	if !mwm.isConnected || mwm.conn == nil {
+		return fmt.Errorf("Markdown Agent WebSocket not connected")
+	}
+
+	jsonData, err := json.Marshal(cancelFrame{Type: "cancel", RequestID: requestID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cancel request: %v", err)
+	}
+	if err := mwm.conn.WriteMessage(websocket.TextMessage, jsonData); err != nil {
+		mwm.isConnected = false
+		return fmt.Errorf("failed to send Markdown Agent WebSocket message: %v", err)
+	}
+
+	if mwm.cancelled == nil {
+		mwm.cancelled = make(map[string]bool)
+	}
+	mwm.cancelled[requestID] = true
+	return nil
+}
+
+// isCancelled reports whether requestID was cancelled and is still winding down
+func (mwm *MarkdownAgentWebSocketManager) isCancelled(requestID string) bool {
+	mwm.mutex.Lock()
+	defer mwm.mutex.Unlock()
+	return mwm.cancelled[requestID]
+}
+
+// finishRequest forgets a request once the agent has stopped working on it. It returns the
+// meeting notes generation the request was for, if any.
+func (mwm *MarkdownAgentWebSocketManager) finishRequest(requestID string) *notesAgentRequest {
+	mwm.mutex.Lock()
+	defer mwm.mutex.Unlock()
+	notesRequest := mwm.notesRequests[requestID]
+	delete(mwm.pending, requestID)
+	delete(mwm.cancelled, requestID)
+	delete(mwm.notesRequests, requestID)
+	return notesRequest
+}
+
+// trackNotesRequest remembers that requestID generates meeting notes
+func (mwm *MarkdownAgentWebSocketManager) trackNotesRequest(requestID string, request *notesAgentRequest) {
+	mwm.mutex.Lock()
+	defer mwm.mutex.Unlock()
+	if mwm.notesRequests == nil {
+		mwm.notesRequests = make(map[string]*notesAgentRequest)
+	}
+	mwm.notesRequests[requestID] = request
+}
+
+// forgetNotesRequest makes the output of requestID be dropped instead of saved. It returns
+// false if the generation already completed and its output is being saved.
+func (mwm *MarkdownAgentWebSocketManager) forgetNotesRequest(requestID string) bool {
+	mwm.mutex.Lock()
+	defer mwm.mutex.Unlock()
+	_, ok := mwm.notesRequests[requestID]
+	delete(mwm.notesRequests, requestID)
+	return ok
+}
+
+// appendNotesOutput collects the output of a meeting notes generation
+func (mwm *MarkdownAgentWebSocketManager) appendNotesOutput(requestID, content string) {
+	mwm.mutex.Lock()
+	defer mwm.mutex.Unlock()
+	if request := mwm.notesRequests[requestID]; request != nil {
+		request.Output.WriteString(content)
+	}
+}
+
 // Close closes the markdown agent WebSocket connection
 func (mwm *MarkdownAgentWebSocketManager) Close() {
 	mwm.mutex.Lock()
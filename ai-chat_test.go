@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBridgeStreamStopWaitsForToken(t *testing.T) {
+	var reply strings.Builder
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	stream := &bridgeStream{onToken: func(token string) {
+		if reply.Len() == 0 {
+			close(entered)
+			<-release
+		}
+		reply.WriteString(token)
+	}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		stream.deliver("Hello")
+	}()
+	<-entered
+
+	stopped := make(chan struct{})
+	go func() {
+		stream.stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("stop returned while a token was being written")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-stopped
+	wg.Wait()
+
+	stream.deliver(" world")
+	if reply.String() != "Hello" {
+		t.Errorf("reply = %q, want the token delivered before stop only", reply.String())
+	}
+}
@@ -34,6 +34,9 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+
+	go a.registerGlobalHotkeys()
+	go a.indexKnowledgeBaseWhenReady()
 }
 
 // Greet returns a greeting for the given name
@@ -60,6 +63,7 @@ func (a *App) StartOllamaServer() error {
 	return cmd.Start()
 }
 
+// CheckGraniteInstallation checks that the configured Ollama model (granite3.3:8b by default) is installed
 func (a *App) CheckGraniteInstallation() bool {
 	cmd := exec.Command("ollama", "list")
 	output, err := cmd.Output()
@@ -67,11 +71,13 @@ func (a *App) CheckGraniteInstallation() bool {
 		return false
 	}
 	log.Println("output:", string(output))
-	return strings.Contains(string(output), "granite3.3:8b")
+	return strings.Contains(string(output), GetSetting(SettingOllamaModel))
 }
 
+// DownloadGraniteModel pulls the configured Ollama model (granite3.3:8b by default)
 func (a *App) DownloadGraniteModel() error {
-	cmd := exec.Command("ollama", "pull", "granite3.3:8b")
+	model := GetSetting(SettingOllamaModel)
+	cmd := exec.Command("ollama", "pull", model)
 
 	// Create temporary files for stdout and stderr
 	stdoutFile, err := os.CreateTemp("", "ollama_stdout_*.log")
@@ -130,9 +136,9 @@ func (a *App) DownloadGraniteModel() error {
 
 	err = cmd.Wait()
 	if err != nil {
-		return fmt.Errorf("failed to download granite model: %v", err)
+		return fmt.Errorf("failed to download model %s: %v", model, err)
 	}
-	runtime.EventsEmit(a.ctx, "graniteDownloadProgress", map[string]interface{}{"done": true, "message": "Granite model downloaded successfully."})
+	runtime.EventsEmit(a.ctx, "graniteDownloadProgress", map[string]interface{}{"done": true, "message": fmt.Sprintf("Model %s downloaded successfully.", model)})
 	return nil
 }
 
@@ -211,8 +217,31 @@ func (a *App) UpdateWorkspaceLastOpen(id uint) error {
 	return UpdateWorkspaceLastOpen(id)
 }
 
+func (a *App) UpdateWorkspaceChatBackend(id uint, backend string) (*Workspace, error) {
+	return UpdateWorkspaceChatBackend(id, backend)
+}
+
+// DeleteWorkspace moves a workspace to the trash. Its open session is stopped and live
+// captions are no longer written to it.
 func (a *App) DeleteWorkspace(id uint) error {
-	return DeleteWorkspace(id)
+	open, err := GetOpenSession(id)
+	if err != nil {
+		return err
+	}
+	if err := DeleteWorkspace(id); err != nil {
+		return err
+	}
+
+	if open != nil {
+		stopLiveNotes(open.ID)
+		stopMentionTracker(open.ID)
+		now := time.Now()
+		open.Status = SessionStatusStopped
+		open.EndTime = &now
+		runtime.EventsEmit(a.ctx, "sessionStopped", open)
+	}
+	clearActiveWorkspace(id)
+	return nil
 }
 
 // Transcription database methods
@@ -290,7 +319,7 @@ func (a *App) OpenMultipleFilesDialog() ([]string, error) {
 
 // MoveFilesToYumesession copies a list of files to the yumesession directory and returns the filenames
 func (a *App) MoveFilesToYumesession(filePaths []string) ([]string, error) {
-	destDir := "yumesession/knowledge_base"
+	destDir := knowledgeBaseDir
 	if err := os.MkdirAll(destDir, 0755); err != nil {
 		log.Printf("Error creating directory %s: %v", destDir, err)
 		return nil, fmt.Errorf("failed to create destination directory: %w", err)
@@ -351,7 +380,12 @@ func (a *App) OpenAndGetPDFData(pdfFilePath string) ([]byte, error) {
 
 // Knowledge Base methods
 func (a *App) CreateKnowledgeBaseItem(uniqueFileName, itemType, oneLineSummary, fullSummary string) (*KnowledgeBase, error) {
-	return CreateKnowledgeBaseItem(uniqueFileName, itemType, oneLineSummary, fullSummary)
+	item, err := CreateKnowledgeBaseItem(uniqueFileName, itemType, oneLineSummary, fullSummary)
+	if err != nil {
+		return nil, err
+	}
+	a.indexKnowledgeBaseItemAsync(item.ID)
+	return item, nil
 }
 
 func (a *App) GetAllKnowledgeBaseItems() ([]KnowledgeBase, error) {
@@ -371,7 +405,12 @@ func (a *App) GetKnowledgeBaseItemByUniqueFileName(uniqueFileName string) (*Know
 }
 
 func (a *App) UpdateKnowledgeBaseItem(id uint, uniqueFileName, itemType, oneLineSummary, fullSummary string) (*KnowledgeBase, error) {
-	return UpdateKnowledgeBaseItem(id, uniqueFileName, itemType, oneLineSummary, fullSummary)
+	item, err := UpdateKnowledgeBaseItem(id, uniqueFileName, itemType, oneLineSummary, fullSummary)
+	if err != nil {
+		return nil, err
+	}
+	a.indexKnowledgeBaseItemAsync(item.ID)
+	return item, nil
 }
 
 func (a *App) DeleteKnowledgeBaseItem(id uint) error {
@@ -411,6 +450,11 @@ func (a *App) SearchMeetingNotes(workspaceID uint, searchTerm string) ([]Meeting
 	return SearchMeetingNotes(workspaceID, searchTerm)
 }
 
+// GlobalSearch searches transcripts, meeting notes, chat and the knowledge base, best matches first
+func (a *App) GlobalSearch(query string, filters SearchFilters) ([]SearchResult, error) {
+	return GlobalSearch(query, filters)
+}
+
 // InitializeMarkdownAgentWebSocket initializes the markdown agent WebSocket connection
 func (a *App) InitializeMarkdownAgentWebSocket() error {
 	if markdownWsManager != nil {
@@ -424,22 +468,65 @@ func (a *App) InitializeMarkdownAgentWebSocket() error {
 	return markdownWsManager.Connect()
 }
 
-// SendMarkdownAgentMessage sends a message via the markdown agent WebSocket connection
-func (a *App) SendMarkdownAgentMessage(message string) error {
+// SendMarkdownAgentMessage sends a message via the markdown agent WebSocket connection.
+// It returns the request ID, which can be passed to CancelMarkdownAgent.
+func (a *App) SendMarkdownAgentMessage(message string) (string, error) {
 	if markdownWsManager == nil {
-		return fmt.Errorf("Markdown Agent WebSocket not initialized. Call InitializeMarkdownAgentWebSocket first")
+		return "", fmt.Errorf("Markdown Agent WebSocket not initialized. Call InitializeMarkdownAgentWebSocket first")
 	}
 
-	return markdownWsManager.SendMessage(message)
+	requestID := newMessageID("notes")
+	if err := markdownWsManager.SendMessage(requestID, message); err != nil {
+		return "", err
+	}
+	return requestID, nil
 }
 
-// SendMeetingNotesRequest sends a specific meeting notes request with transcription and current markdown
-func (a *App) SendMeetingNotesRequest(transcriptionList []string, currentMarkdown string) error {
-	// Join transcription list into a single string
-	transcriptionContent := strings.Join(transcriptionList, "\n")
+// CancelMarkdownAgent stops an in-flight markdown agent generation
+func (a *App) CancelMarkdownAgent(requestID string) error {
+	if markdownWsManager == nil {
+		return fmt.Errorf("Markdown Agent WebSocket not initialized")
+	}
+	if err := markdownWsManager.Cancel(requestID); err != nil {
+		return err
+	}
+
+	runtime.EventsEmit(a.ctx, "markdownAgentStreamCancelled", map[string]interface{}{
+		"requestId": requestID,
+	})
+	return nil
+}
 
-	message := fmt.Sprintf("[transcriptions]\n\n%s\n\n[current markdown notes]\n\n%s", transcriptionContent, currentMarkdown)
-	return a.SendMarkdownAgentMessage(message)
+// SendMeetingNotesRequest sends a specific meeting notes request with transcription and current markdown.
+// When the agent completes, its output is compared with the notes and proposed as a patch through the
+// markdownAgentProposedPatch event.
+func (a *App) SendMeetingNotesRequest(notesID uint, transcriptionList []string, currentMarkdown string) (string, error) {
+	return sendMeetingNotesRequest(transcriptionList, &notesAgentRequest{NotesID: notesID, Base: currentMarkdown})
+}
+
+// sendMeetingNotesRequest asks the markdown agent to update request.Base with new transcription lines
+func sendMeetingNotesRequest(transcriptionList []string, request *notesAgentRequest) (string, error) {
+	if markdownWsManager == nil {
+		return "", fmt.Errorf("Markdown Agent WebSocket not initialized. Call InitializeMarkdownAgentWebSocket first")
+	}
+
+	var workspaceID uint
+	if notes, err := GetMeetingNotesByID(request.NotesID); err == nil {
+		workspaceID = notes.WorkspaceID
+	}
+	message := renderWorkspacePrompt(workspaceID, PromptKindMeetingNotes, &promptData{
+		transcript: transcriptionList,
+		notes:      &request.Base,
+	})
+
+	// Tracked before sending, the first tokens can arrive before SendMessage returns
+	requestID := newMessageID("notes")
+	markdownWsManager.trackNotesRequest(requestID, request)
+	if err := markdownWsManager.SendMessage(requestID, message); err != nil {
+		markdownWsManager.finishRequest(requestID)
+		return "", err
+	}
+	return requestID, nil
 }
 
 // IsMarkdownAgentWebSocketConnected checks if the markdown agent WebSocket is connected
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wailsapp/wails/v2/pkg/menu"
+	"github.com/wailsapp/wails/v2/pkg/menu/keys"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Transcript windows the commands look at when none is given
+const (
+	defaultRecapWindow     = 5 * time.Minute
+	maxRecapWindow         = 2 * time.Hour
+	defineTranscriptWindow = 30 * time.Minute
+	replyTranscriptWindow  = 3 * time.Minute
+	translateWindow        = 2 * time.Minute
+)
+
+const recapSystemPrompt = `You recap live meetings for someone who missed part of it.
+Summarise the transcript excerpt in a few short bullet points, saying who said what. Only use what is in the transcript.`
+
+const defineSystemPrompt = `You explain terms that come up in meetings.
+Define the term in two or three sentences for someone new to the topic, then say how it was used in this meeting if the transcript shows it.
+Prefer the knowledge base excerpts over general knowledge when they define the term.`
+
+const replySystemPrompt = `You help the user take part in a live meeting.
+Suggest two or three short replies they could say next, in their own voice, each on its own line starting with "- ".
+Base them on the latest part of the transcript and keep them under two sentences.`
+
+const translateSystemPrompt = `You translate meeting transcripts.
+Translate the transcript lines into %s, keeping one line per turn in the form "Speaker: text". Reply with the translation only.`
+
+// assistantCommand is a quick action typed in the chat as "/name args". Instead of the general
+// chat context, it builds its own context and prompt. It can also be run with its global
+// hotkey while another app has focus, or from the Assistant menu.
+type assistantCommand struct {
+	Name          string
+	Usage         string
+	Description   string
+	HotkeySetting string // setting holding the command's hotkey, e.g. "cmdorctrl+optionoralt+shift+r"
+
+	// build returns the messages sent to the chat provider, args is what follows the name
+	build func(workspaceID uint, args string) ([]ChatMessage, error)
+	// hotkeyArgs returns the arguments used when the command is run by its hotkey or the menu
+	hotkeyArgs func(a *App) string
+}
+
+// assistantCommands is the command registry, in the order commands are listed
+var assistantCommands = []*assistantCommand{
+	{
+		Name:          "recap",
+		Usage:         "/recap [duration] [topic]",
+		Description:   "Recap the last few minutes of the meeting (5m by default), optionally only what was said about a topic",
+		HotkeySetting: SettingHotkeyRecap,
+		build:         buildRecapCommand,
+	},
+	{
+		Name:          "define",
+		Usage:         "/define <term>",
+		Description:   "Explain a term and how it was used in the meeting",
+		HotkeySetting: SettingHotkeyDefine,
+		build:         buildDefineCommand,
+		hotkeyArgs: func(a *App) string {
+			// Run by its hotkey, it defines whatever was copied last
+			text, _ := runtime.ClipboardGetText(a.ctx)
+			return strings.TrimSpace(text)
+		},
+	},
+	{
+		Name:          "reply",
+		Usage:         "/reply [guidance]",
+		Description:   "Suggest what to say next, optionally following guidance such as \"decline politely\"",
+		HotkeySetting: SettingHotkeyReply,
+		build:         buildReplyCommand,
+	},
+	{
+		Name:          "translate",
+		Usage:         "/translate [language]",
+		Description:   "Translate the last two minutes of the meeting (into the configured language by default)",
+		HotkeySetting: SettingHotkeyTranslate,
+		build:         buildTranslateCommand,
+	},
+}
+
+// findAssistantCommand returns the command registered under name, or nil
+func findAssistantCommand(name string) *assistantCommand {
+	for _, command := range assistantCommands {
+		if command.Name == name {
+			return command
+		}
+	}
+	return nil
+}
+
+// parseAssistantCommand splits a chat message such as "/recap 10m pricing" into its command
+// and arguments. Messages that don't start with a registered command are regular chat messages.
+func parseAssistantCommand(message string) (*assistantCommand, string, bool) {
+	message = strings.TrimSpace(message)
+	if !strings.HasPrefix(message, "/") {
+		return nil, "", false
+	}
+	name, args := message[1:], ""
+	if end := strings.IndexFunc(name, unicode.IsSpace); end >= 0 {
+		name, args = name[:end], name[end:]
+	}
+	command := findAssistantCommand(strings.ToLower(name))
+	if command == nil {
+		return nil, "", false
+	}
+	return command, strings.TrimSpace(args), true
+}
+
+// recentTranscript returns the transcript lines of a workspace from the last window, measured
+// back from the newest line so it also works for meetings that ended, keeping the newest lines
+// that fit in maxTokens
+func recentTranscript(workspaceID uint, window time.Duration, maxTokens int) ([]TranscriptionRecord, error) {
+	var latest TranscriptionRecord
+	result := DB.Where("workspace_id = ?", workspaceID).Order("timestamp DESC").Limit(1).Find(&latest)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+
+	var records []TranscriptionRecord
+	result = DB.Where("workspace_id = ? AND timestamp >= ?", workspaceID, latest.Timestamp.Add(-window)).
+		Order("timestamp ASC").Find(&records)
+	if result.Error != nil {
+		log.Printf("Failed to get recent transcript for workspace %d: %v", workspaceID, result.Error)
+		return nil, result.Error
+	}
+
+	start := len(records)
+	for start > 0 {
+		cost := estimateTokens(formatTranscriptLine(records[start-1]))
+		if cost > maxTokens {
+			break
+		}
+		maxTokens -= cost
+		start--
+	}
+	return records[start:], nil
+}
+
+// formatTranscriptLines joins records into "Speaker: text" lines
+func formatTranscriptLines(records []TranscriptionRecord) string {
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, formatTranscriptLine(record))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatWindow writes a transcript window the way users type it, e.g. "5m" or "1h30m"
+func formatWindow(window time.Duration) string {
+	text := window.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// parseRecapArgs reads the optional window and topic of /recap. A bare number is minutes.
+func parseRecapArgs(args string) (time.Duration, string) {
+	first, rest, _ := strings.Cut(args, " ")
+	window, err := time.ParseDuration(first)
+	if err != nil {
+		minutes, err := strconv.Atoi(first)
+		if err != nil {
+			return defaultRecapWindow, args
+		}
+		window = time.Duration(minutes) * time.Minute
+	}
+	if window <= 0 {
+		window = defaultRecapWindow
+	}
+	return min(window, maxRecapWindow), strings.TrimSpace(rest)
+}
+
+func buildRecapCommand(workspaceID uint, args string) ([]ChatMessage, error) {
+	window, topic := parseRecapArgs(args)
+	records, err := recentTranscript(workspaceID, window, contextTokenBudget()*3/4)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("nothing was transcribed in the last %s", formatWindow(window))
+	}
+
+	prompt := fmt.Sprintf("Transcript of the last %s (oldest first):\n%s", formatWindow(window), formatTranscriptLines(records))
+	if topic != "" {
+		prompt += "\n\nOnly recap what was said about: " + topic
+	}
+	return []ChatMessage{
+		{Role: "system", Content: recapSystemPrompt},
+		{Role: "user", Content: prompt},
+	}, nil
+}
+
+func buildDefineCommand(workspaceID uint, args string) ([]ChatMessage, error) {
+	term := strings.TrimSpace(args)
+	if term == "" {
+		return nil, fmt.Errorf("usage: /define <term>")
+	}
+	budget := contextTokenBudget() * 3 / 4
+
+	var prompt strings.Builder
+	if workspace, err := GetWorkspaceByID(workspaceID); err == nil && workspace.Description != "" {
+		prompt.WriteString("About this meeting: " + workspace.Description + "\n\n")
+	}
+
+	// Only the lines that mention the term, from the last half hour
+	records, _ := recentTranscript(workspaceID, defineTranscriptWindow, budget/2)
+	var mentions []TranscriptionRecord
+	for _, record := range records {
+		if strings.Contains(strings.ToLower(record.Text), strings.ToLower(term)) {
+			mentions = append(mentions, record)
+		}
+	}
+	if len(mentions) > 0 {
+		prompt.WriteString("Transcript lines mentioning it (oldest first):\n" + formatTranscriptLines(mentions) + "\n\n")
+	}
+
+	knowledgeBudget := budget / 2
+	retrieved := retrieveKnowledge(term, retrievalTopK())
+	for i, chunk := range retrieved {
+		cost := estimateTokens(chunk.Chunk.Text) + 10
+		if cost > knowledgeBudget {
+			break
+		}
+		knowledgeBudget -= cost
+		if i == 0 {
+			prompt.WriteString("Knowledge Base Excerpts:\n")
+		}
+		prompt.WriteString(fmt.Sprintf("[%d] %s:\n%s\n\n", i+1, knowledgeSourceName(chunk.Item), chunk.Chunk.Text))
+	}
+
+	prompt.WriteString("Term: " + term)
+	return []ChatMessage{
+		{Role: "system", Content: defineSystemPrompt},
+		{Role: "user", Content: prompt.String()},
+	}, nil
+}
+
+func buildReplyCommand(workspaceID uint, args string) ([]ChatMessage, error) {
+	budget := contextTokenBudget() * 3 / 4
+	records, err := recentTranscript(workspaceID, replyTranscriptWindow, budget*3/4)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("there is no transcript to reply to yet")
+	}
+
+	var prompt strings.Builder
+	if notes, err := GetMeetingNotesByWorkspace(workspaceID); err == nil && len(notes) > 0 && notes[0].Text != "" {
+		prompt.WriteString("Meeting Notes:\n" + truncateToTokens(notes[0].Text, budget/4) + "\n\n")
+	}
+	prompt.WriteString("Latest transcript (oldest first):\n" + formatTranscriptLines(records))
+	if args != "" {
+		prompt.WriteString("\n\nThe reply should: " + args)
+	}
+	return []ChatMessage{
+		{Role: "system", Content: replySystemPrompt},
+		{Role: "user", Content: prompt.String()},
+	}, nil
+}
+
+func buildTranslateCommand(workspaceID uint, args string) ([]ChatMessage, error) {
+	language := args
+	if language == "" {
+		language = GetSetting(SettingTranslateLanguage)
+	}
+	records, err := recentTranscript(workspaceID, translateWindow, contextTokenBudget()/3)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("nothing was transcribed in the last %s", formatWindow(translateWindow))
+	}
+
+	return []ChatMessage{
+		{Role: "system", Content: fmt.Sprintf(translateSystemPrompt, language)},
+		{Role: "user", Content: formatTranscriptLines(records)},
+	}, nil
+}
+
+// commandChatRequest prepares the chat request running a command typed in the chat of a
+// workspace. The command is stored as the user's message so the reply can be linked to it.
+func commandChatRequest(workspaceID uint, command *assistantCommand, args, message string) (*ChatStreamRequest, error) {
+	messages, err := command.build(workspaceID, args)
+	if err != nil {
+		return nil, fmt.Errorf("/%s: %v", command.Name, err)
+	}
+
+	var replyToID *uint
+	if userMessage, err := CreateAIChatMessage(workspaceID, "user", message); err == nil {
+		replyToID = &userMessage.ID
+	}
+	return &ChatStreamRequest{
+		RequestID:   newMessageID("chat"),
+		WorkspaceID: workspaceID,
+		Messages:    messages,
+		ReplyToID:   replyToID,
+	}, nil
+}
+
+// liveWorkspaceID returns the workspace of the most recently started active session, the
+// meeting hotkeys apply to
+func liveWorkspaceID() (uint, error) {
+	var session Session
+	result := DB.Where("status = ?", SessionStatusActive).Order("start_time DESC").Limit(1).Find(&session)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("no meeting is being recorded")
+	}
+	return session.WorkspaceID, nil
+}
+
+// runAssistantHotkey runs a command in the live meeting's chat when its hotkey is pressed or
+// it is chosen in the Assistant menu. The frontend is told about the request through
+// assistantCommandStarted before the reply streams in.
+func (a *App) runAssistantHotkey(command *assistantCommand) {
+	err := func() error {
+		workspaceID, err := liveWorkspaceID()
+		if err != nil {
+			return err
+		}
+		provider, err := readyChatProvider(workspaceID)
+		if err != nil {
+			return err
+		}
+
+		message := "/" + command.Name
+		args := ""
+		if command.hotkeyArgs != nil {
+			args = command.hotkeyArgs(a)
+			if args != "" {
+				message += " " + args
+			}
+		}
+		request, err := commandChatRequest(workspaceID, command, args, message)
+		if err != nil {
+			return err
+		}
+
+		runtime.EventsEmit(a.ctx, "assistantCommandStarted", map[string]interface{}{
+			"workspaceId": workspaceID,
+			"requestId":   request.RequestID,
+			"message":     message,
+		})
+		ctx := trackGeneration(request.RequestID)
+		go a.runChatGeneration(ctx, provider, *request)
+		return nil
+	}()
+	if err != nil {
+		log.Printf("Failed to run /%s from its hotkey: %v", command.Name, err)
+		runtime.EventsEmit(a.ctx, "assistantCommandFailed", map[string]interface{}{
+			"command": command.Name,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// applicationMenu builds the application menu, with an Assistant menu whose items run the
+// commands. Their accelerators are the commands' hotkeys, which the operating system hands to
+// the global registration first, so they only fire here when it failed.
+func (a *App) applicationMenu() *menu.Menu {
+	appMenu := menu.NewMenu()
+	if goruntime.GOOS == "darwin" {
+		// Replacing the default menu would otherwise drop copy and paste
+		appMenu.Append(menu.AppMenu())
+		appMenu.Append(menu.EditMenu())
+	}
+
+	assistantMenu := appMenu.AddSubmenu("Assistant")
+	for _, command := range assistantCommands {
+		var accelerator *keys.Accelerator
+		if hotkey := strings.TrimSpace(GetSetting(command.HotkeySetting)); hotkey != "" {
+			parsed, err := keys.Parse(hotkey)
+			if err != nil {
+				log.Printf("Ignoring invalid hotkey %q for /%s: %v", hotkey, command.Name, err)
+			} else {
+				accelerator = parsed
+			}
+		}
+		assistantMenu.AddText(command.Usage, accelerator, func(*menu.CallbackData) {
+			a.runAssistantHotkey(command)
+		})
+	}
+	return appMenu
+}
+
+// validateHotkeySetting checks the hotkey saved in a command's hotkey setting, other settings
+// are left alone
+func validateHotkeySetting(key, value string) error {
+	for _, command := range assistantCommands {
+		if command.HotkeySetting != key || strings.TrimSpace(value) == "" {
+			continue
+		}
+		if _, err := parseGlobalHotkey(value); err != nil {
+			return fmt.Errorf("invalid hotkey for /%s: %v", command.Name, err)
+		}
+	}
+	return nil
+}
+
+// refreshApplicationMenu rebuilds the application menu after its shortcuts changed
+func (a *App) refreshApplicationMenu() {
+	runtime.MenuSetApplicationMenu(a.ctx, a.applicationMenu())
+	runtime.MenuUpdateApplicationMenu(a.ctx)
+}
+
+// AssistantCommandInfo describes a chat command for the frontend
+type AssistantCommandInfo struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+	Hotkey      string `json:"hotkey"`                // global hotkey running the command, empty if none
+	Setting     string `json:"hotkeySetting"`         // setting to change the hotkey with
+	HotkeyError string `json:"hotkeyError,omitempty"` // why the hotkey isn't registered, e.g. another app holds it
+}
+
+// ListAssistantCommands returns the commands that can be typed in the chat
+func (a *App) ListAssistantCommands() []AssistantCommandInfo {
+	commands := make([]AssistantCommandInfo, 0, len(assistantCommands))
+	for _, command := range assistantCommands {
+		commands = append(commands, AssistantCommandInfo{
+			Name:        command.Name,
+			Usage:       command.Usage,
+			Description: command.Description,
+			Hotkey:      GetSetting(command.HotkeySetting),
+			Setting:     command.HotkeySetting,
+			HotkeyError: globalHotkeyError(command.HotkeySetting),
+		})
+	}
+	return commands
+}
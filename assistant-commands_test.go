@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAssistantCommand(t *testing.T) {
+	cases := []struct {
+		message string
+		command string // empty when the message is a regular chat message
+		args    string
+	}{
+		{"/recap 10m pricing", "recap", "10m pricing"},
+		{"  /Define  burn rate ", "define", "burn rate"},
+		{"/reply", "reply", ""},
+		{"/translate\tJapanese", "translate", "Japanese"},
+		{"/recap\n5m", "recap", "5m"},
+		{"/unknown thing", "", ""},
+		{"/", "", ""},
+		{"what does /recap do?", "", ""},
+		{"/recapitulate", "", ""},
+	}
+	for _, c := range cases {
+		command, args, ok := parseAssistantCommand(c.message)
+		if c.command == "" {
+			if ok {
+				t.Errorf("parseAssistantCommand(%q) = /%s, want a chat message", c.message, command.Name)
+			}
+			continue
+		}
+		if !ok || command.Name != c.command || args != c.args {
+			t.Errorf("parseAssistantCommand(%q) = %v, %q, %v, want /%s %q", c.message, command, args, ok, c.command, c.args)
+		}
+	}
+}
+
+func TestParseRecapArgs(t *testing.T) {
+	cases := []struct {
+		args   string
+		window time.Duration
+		topic  string
+	}{
+		{"", defaultRecapWindow, ""},
+		{"10m", 10 * time.Minute, ""},
+		{"90s budget", 90 * time.Second, "budget"},
+		{"15 the  launch date", 15 * time.Minute, "the  launch date"},
+		{"1h30m", 90 * time.Minute, ""},
+		{"pricing", defaultRecapWindow, "pricing"},
+		{"pricing and discounts", defaultRecapWindow, "pricing and discounts"},
+		{"0", defaultRecapWindow, ""},
+		{"-5m hiring", defaultRecapWindow, "hiring"},
+		{"8h", maxRecapWindow, ""},
+	}
+	for _, c := range cases {
+		window, topic := parseRecapArgs(c.args)
+		if window != c.window || topic != c.topic {
+			t.Errorf("parseRecapArgs(%q) = %s, %q, want %s, %q", c.args, window, topic, c.window, c.topic)
+		}
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	cases := map[time.Duration]string{
+		5 * time.Minute:  "5m",
+		90 * time.Minute: "1h30m",
+		2 * time.Hour:    "2h",
+		90 * time.Second: "1m30s",
+	}
+	for window, want := range cases {
+		if got := formatWindow(window); got != want {
+			t.Errorf("formatWindow(%s) = %q, want %q", window, got, want)
+		}
+	}
+}
+
+func TestValidateHotkeySetting(t *testing.T) {
+	if err := validateHotkeySetting(SettingHotkeyRecap, "cmdorctrl+optionoralt+shift+r"); err != nil {
+		t.Errorf("valid accelerator rejected: %v", err)
+	}
+	if err := validateHotkeySetting(SettingHotkeyRecap, "shift+r"); err == nil {
+		t.Error("expected a hotkey that swallows typed text to be rejected")
+	}
+	if err := validateHotkeySetting(SettingHotkeyRecap, ""); err != nil {
+		t.Errorf("empty accelerator rejected: %v", err)
+	}
+	if err := validateHotkeySetting(SettingHotkeyRecap, "shift+nosuchkey+"); err == nil {
+		t.Error("expected an invalid accelerator to be rejected")
+	}
+	if err := validateHotkeySetting(SettingTranslateLanguage, "French"); err != nil {
+		t.Errorf("other settings are not accelerators: %v", err)
+	}
+}
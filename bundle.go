@@ -0,0 +1,686 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+// Version of the workspace bundle layout, bumped when an older app could no longer read it
+const bundleFormatVersion = 1
+
+// Files inside a workspace bundle
+const (
+	bundleManifestFile    = "manifest.json"
+	bundleSessionsFile    = "sessions.json"
+	bundleTranscriptsFile = "transcripts.json"
+	bundleNotesFile       = "notes.md"
+	bundleChatFile        = "chat.json"
+	bundleKnowledgeDir    = "knowledge/"
+)
+
+// WorkspaceBundleManifest describes the contents of a workspace bundle
+type WorkspaceBundleManifest struct {
+	FormatVersion int                   `json:"formatVersion"`
+	SchemaVersion int                   `json:"schemaVersion"` // schema version of the exporting app
+	ExportedAt    time.Time             `json:"exportedAt"`
+	Workspace     bundleWorkspace       `json:"workspace"`
+	Counts        map[string]int        `json:"counts"`
+	Knowledge     []bundleKnowledgeItem `json:"knowledge"`
+}
+
+type bundleWorkspace struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ChatBackend string    `json:"chatBackend"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type bundleKnowledgeItem struct {
+	ID             uint   `json:"id"`
+	Type           string `json:"type"`
+	UniqueFileName string `json:"uniqueFileName"`
+	OneLineSummary string `json:"oneLineSummary"`
+	FullSummary    string `json:"fullSummary"`
+	SHA256         string `json:"sha256"` // of the file, or of FullSummary for website links
+	File           string `json:"file,omitempty"`
+}
+
+type bundleSession struct {
+	ID           uint          `json:"id"`
+	Title        string        `json:"title"`
+	Source       string        `json:"source"`
+	Status       string        `json:"status"`
+	StartTime    time.Time     `json:"startTime"`
+	EndTime      *time.Time    `json:"endTime"`
+	Participants []string      `json:"participants"`
+	Pauses       []bundlePause `json:"pauses"`
+}
+
+type bundlePause struct {
+	PausedAt  time.Time  `json:"pausedAt"`
+	ResumedAt *time.Time `json:"resumedAt"`
+}
+
+type bundleTranscript struct {
+	MessageID   string    `json:"messageId"`
+	SessionID   *uint     `json:"sessionId"`
+	Text        string    `json:"text"`
+	Speaker     string    `json:"speaker"`
+	Timestamp   time.Time `json:"timestamp"`
+	Source      string    `json:"source"`
+	MessageType string    `json:"messageType"`
+}
+
+type bundleChatMessage struct {
+	ID               uint       `json:"id"`
+	SessionID        *uint      `json:"sessionId"`
+	By               string     `json:"by"`
+	Text             string     `json:"text"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	Model            string     `json:"model,omitempty"`
+	LatencyMs        int64      `json:"latencyMs,omitempty"`
+	PromptTokens     int        `json:"promptTokens,omitempty"`
+	CompletionTokens int        `json:"completionTokens,omitempty"`
+	ReplyToID        *uint      `json:"replyToId"`
+	Citations        []Citation `json:"citations,omitempty"`
+}
+
+// ImportReport describes what ImportWorkspace created and anything it had to change
+type ImportReport struct {
+	Workspace             *Workspace `json:"workspace"`
+	Sessions              int        `json:"sessions"`
+	Transcripts           int        `json:"transcripts"`
+	ChatMessages          int        `json:"chatMessages"`
+	HasNotes              bool       `json:"hasNotes"`
+	KnowledgeImported     int        `json:"knowledgeImported"`
+	KnowledgeDeduplicated int        `json:"knowledgeDeduplicated"` // already present, matched by hash
+	Conflicts             []string   `json:"conflicts"`
+}
+
+// sha256Hex returns the hex SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// knowledgeItemContent returns the bytes identifying a knowledge base item: its file, or
+// the summary of a website link
+func knowledgeItemContent(item KnowledgeBase) ([]byte, error) {
+	if item.UniqueFileName == "" {
+		return []byte(item.FullSummary), nil
+	}
+	return os.ReadFile(filepath.Join(knowledgeBaseDir, item.UniqueFileName))
+}
+
+// writeBundleJSON adds a JSON file to the bundle
+func writeBundleJSON(archive *zip.Writer, name string, value interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// ExportWorkspace writes a workspace with its sessions, transcripts, notes, chat history
+// and the knowledge base items cited in the chat to a zip file at dest
+func ExportWorkspace(id uint, dest string) (err error) {
+	workspace, err := GetWorkspaceByID(id)
+	if err != nil {
+		return err
+	}
+
+	var sessions []Session
+	if err := DB.Preload("Pauses").Where("workspace_id = ?", id).Order("start_time ASC").Find(&sessions).Error; err != nil {
+		return fmt.Errorf("failed to load sessions: %v", err)
+	}
+	records, err := GetTranscriptionMessagesByWorkspace(id)
+	if err != nil {
+		return err
+	}
+	var chat []AIChatMessage
+	if err := DB.Where("workspace_id = ?", id).Order("created_at ASC").Find(&chat).Error; err != nil {
+		return fmt.Errorf("failed to load chat history: %v", err)
+	}
+	notes, err := GetMeetingNotesByWorkspace(id)
+	if err != nil {
+		return err
+	}
+
+	manifest := WorkspaceBundleManifest{
+		FormatVersion: bundleFormatVersion,
+		SchemaVersion: latestSchemaVersion(),
+		ExportedAt:    time.Now(),
+		Workspace: bundleWorkspace{
+			Title:       workspace.Title,
+			Description: workspace.Description,
+			ChatBackend: workspace.ChatBackend,
+			CreatedAt:   workspace.CreatedAt,
+		},
+		Knowledge: []bundleKnowledgeItem{},
+	}
+
+	bundleSessions := make([]bundleSession, 0, len(sessions))
+	for _, session := range sessions {
+		pauses := make([]bundlePause, 0, len(session.Pauses))
+		for _, pause := range session.Pauses {
+			pauses = append(pauses, bundlePause{PausedAt: pause.PausedAt, ResumedAt: pause.ResumedAt})
+		}
+		bundleSessions = append(bundleSessions, bundleSession{
+			ID:           session.ID,
+			Title:        session.Title,
+			Source:       session.Source,
+			Status:       session.Status,
+			StartTime:    session.StartTime,
+			EndTime:      session.EndTime,
+			Participants: session.Participants,
+			Pauses:       pauses,
+		})
+	}
+
+	transcripts := make([]bundleTranscript, 0, len(records))
+	for _, record := range records {
+		transcripts = append(transcripts, bundleTranscript{
+			MessageID:   record.MessageID,
+			SessionID:   record.SessionID,
+			Text:        record.Text,
+			Speaker:     record.Speaker,
+			Timestamp:   record.Timestamp,
+			Source:      record.Source,
+			MessageType: record.MessageType,
+		})
+	}
+
+	cited := map[uint]bool{}
+	messages := make([]bundleChatMessage, 0, len(chat))
+	for _, message := range chat {
+		for _, citation := range message.Citations {
+			cited[citation.KnowledgeBaseID] = true
+		}
+		messages = append(messages, bundleChatMessage{
+			ID:               message.ID,
+			SessionID:        message.SessionID,
+			By:               message.By,
+			Text:             message.Text,
+			CreatedAt:        message.CreatedAt,
+			Model:            message.Model,
+			LatencyMs:        message.LatencyMs,
+			PromptTokens:     message.PromptTokens,
+			CompletionTokens: message.CompletionTokens,
+			ReplyToID:        message.ReplyToID,
+			Citations:        message.Citations,
+		})
+	}
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", dest, err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(dest) // don't leave a truncated bundle behind
+		}
+	}()
+	archive := zip.NewWriter(file)
+
+	for knowledgeBaseID := range cited {
+		item, err := GetKnowledgeBaseItemByID(knowledgeBaseID)
+		if err != nil {
+			continue // deleted since it was cited
+		}
+		content, err := knowledgeItemContent(*item)
+		if err != nil {
+			log.Printf("Skipping knowledge base file %s: %v", item.UniqueFileName, err)
+			continue
+		}
+
+		entry := bundleKnowledgeItem{
+			ID:             item.ID,
+			Type:           item.Type,
+			UniqueFileName: item.UniqueFileName,
+			OneLineSummary: item.OneLineSummary,
+			FullSummary:    item.FullSummary,
+			SHA256:         sha256Hex(content),
+		}
+		if item.UniqueFileName != "" {
+			entry.File = bundleKnowledgeDir + entry.SHA256 + strings.ToLower(filepath.Ext(item.UniqueFileName))
+			w, err := archive.Create(entry.File)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(content); err != nil {
+				return err
+			}
+		}
+		manifest.Knowledge = append(manifest.Knowledge, entry)
+	}
+
+	notesText := ""
+	if len(notes) > 0 {
+		notesText = notes[0].Text
+	}
+	manifest.Counts = map[string]int{
+		"sessions":     len(bundleSessions),
+		"transcripts":  len(transcripts),
+		"chatMessages": len(messages),
+		"knowledge":    len(manifest.Knowledge),
+	}
+
+	if err := writeBundleJSON(archive, bundleSessionsFile, bundleSessions); err != nil {
+		return err
+	}
+	if err := writeBundleJSON(archive, bundleTranscriptsFile, transcripts); err != nil {
+		return err
+	}
+	if err := writeBundleJSON(archive, bundleChatFile, messages); err != nil {
+		return err
+	}
+	if len(notes) > 0 {
+		w, err := archive.Create(bundleNotesFile)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, notesText); err != nil {
+			return err
+		}
+	}
+	if err := writeBundleJSON(archive, bundleManifestFile, manifest); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	log.Printf("Exported workspace %d to %s", id, dest)
+	return nil
+}
+
+// readBundleFile reads a file of the bundle, or returns nil when it is absent
+func readBundleFile(files map[string]*zip.File, name string) ([]byte, error) {
+	file, ok := files[name]
+	if !ok {
+		return nil, nil
+	}
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// readBundleJSON decodes a JSON file of the bundle into value, leaving it untouched when absent
+func readBundleJSON(files map[string]*zip.File, name string, value interface{}) error {
+	data, err := readBundleFile(files, name)
+	if err != nil || data == nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return nil
+}
+
+// availableKnowledgeFileName returns name, or name with a numeric suffix if a file by that name exists
+func availableKnowledgeFileName(name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(knowledgeBaseDir, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
+
+// pendingKnowledgeItem is a knowledge base item of a bundle that is not present yet
+type pendingKnowledgeItem struct {
+	item   *KnowledgeBase
+	oldIDs []uint // IDs of the item in the bundle, several if it was exported twice
+}
+
+// importWorkspace creates a new workspace from a bundle written by ExportWorkspace.
+// Knowledge base items already present are reused instead of being copied again.
+func (a *App) importWorkspace(src string) (*ImportReport, error) {
+	archive, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %v", err)
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var manifest WorkspaceBundleManifest
+	if _, ok := files[bundleManifestFile]; !ok {
+		return nil, fmt.Errorf("%s is not a workspace bundle", filepath.Base(src))
+	}
+	if err := readBundleJSON(files, bundleManifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion > bundleFormatVersion {
+		return nil, fmt.Errorf("the bundle was exported by a newer version of YumeSession, please update to import it")
+	}
+
+	var sessions []bundleSession
+	var transcripts []bundleTranscript
+	var messages []bundleChatMessage
+	if err := readBundleJSON(files, bundleSessionsFile, &sessions); err != nil {
+		return nil, err
+	}
+	if err := readBundleJSON(files, bundleTranscriptsFile, &transcripts); err != nil {
+		return nil, err
+	}
+	if err := readBundleJSON(files, bundleChatFile, &messages); err != nil {
+		return nil, err
+	}
+	notes, err := readBundleFile(files, bundleNotesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Conflicts: []string{}}
+
+	// Knowledge base files are matched by content against what is already there
+	existing, err := GetAllKnowledgeBaseItems()
+	if err != nil {
+		return nil, err
+	}
+	byHash := make(map[string]uint)
+	for _, item := range existing {
+		if content, err := knowledgeItemContent(item); err == nil {
+			byHash[sha256Hex(content)] = item.ID
+		}
+	}
+
+	knowledgeIDs := make(map[uint]uint)
+	pendingByHash := make(map[string]*pendingKnowledgeItem)
+	var pending []*pendingKnowledgeItem
+	var copiedFiles []string
+	cleanup := func() {
+		for _, name := range copiedFiles {
+			os.Remove(filepath.Join(knowledgeBaseDir, name))
+		}
+	}
+
+	for _, entry := range manifest.Knowledge {
+		if id, ok := byHash[entry.SHA256]; ok {
+			knowledgeIDs[entry.ID] = id
+			report.KnowledgeDeduplicated++
+			continue
+		}
+		if p, ok := pendingByHash[entry.SHA256]; ok {
+			p.oldIDs = append(p.oldIDs, entry.ID)
+			continue
+		}
+
+		fileName := ""
+		if entry.UniqueFileName != "" {
+			content, err := readBundleFile(files, entry.File)
+			if err != nil || content == nil || !strings.HasPrefix(entry.File, bundleKnowledgeDir) {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("Knowledge base file %q is missing from the bundle and was skipped", entry.UniqueFileName))
+				continue
+			}
+			if sha256Hex(content) != entry.SHA256 {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("Knowledge base file %q is corrupted in the bundle and was skipped", entry.UniqueFileName))
+				continue
+			}
+
+			// Only the base name is used so a crafted bundle can't write outside the knowledge base
+			baseName := filepath.Base(filepath.FromSlash(strings.ReplaceAll(entry.UniqueFileName, "\\", "/")))
+			if baseName == "." || baseName == ".." || baseName == string(filepath.Separator) {
+				baseName = entry.SHA256 + filepath.Ext(entry.File)
+			}
+			fileName = availableKnowledgeFileName(baseName)
+			if fileName != entry.UniqueFileName {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("A different knowledge base file named %q already exists, imported as %q", entry.UniqueFileName, fileName))
+			}
+			if err := os.MkdirAll(knowledgeBaseDir, 0755); err != nil {
+				cleanup()
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(knowledgeBaseDir, fileName), content, 0644); err != nil {
+				cleanup()
+				return nil, fmt.Errorf("failed to copy knowledge base file %s: %v", fileName, err)
+			}
+			copiedFiles = append(copiedFiles, fileName)
+		}
+
+		p := &pendingKnowledgeItem{
+			item: &KnowledgeBase{
+				UniqueFileName: fileName,
+				Type:           entry.Type,
+				OneLineSummary: entry.OneLineSummary,
+				FullSummary:    entry.FullSummary,
+			},
+			oldIDs: []uint{entry.ID},
+		}
+		pendingByHash[entry.SHA256] = p
+		pending = append(pending, p)
+	}
+
+	title := manifest.Workspace.Title
+	for i := 1; ; i++ {
+		var sameTitle int64
+		DB.Model(&Workspace{}).Where("title = ?", title).Count(&sameTitle)
+		if sameTitle == 0 {
+			break
+		}
+		title = fmt.Sprintf("%s (imported %d)", manifest.Workspace.Title, i)
+	}
+	if title != manifest.Workspace.Title {
+		report.Conflicts = append(report.Conflicts, fmt.Sprintf("A workspace named %q already exists, imported as %q", manifest.Workspace.Title, title))
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		for _, p := range pending {
+			if err := tx.Create(p.item).Error; err != nil {
+				return fmt.Errorf("failed to create knowledge base item: %v", err)
+			}
+			for _, oldID := range p.oldIDs {
+				knowledgeIDs[oldID] = p.item.ID
+			}
+			report.KnowledgeImported++
+		}
+
+		workspace := &Workspace{
+			Title:        title,
+			Description:  manifest.Workspace.Description,
+			ChatBackend:  manifest.Workspace.ChatBackend,
+			LastOpenTime: time.Now(),
+			CreatedAt:    manifest.Workspace.CreatedAt,
+		}
+		if workspace.ChatBackend == "" {
+			workspace.ChatBackend = GetSetting(SettingChatProvider)
+		}
+		if err := tx.Create(workspace).Error; err != nil {
+			return fmt.Errorf("failed to create workspace: %v", err)
+		}
+		report.Workspace = workspace
+
+		sessionIDs := make(map[uint]uint)
+		for _, s := range sessions {
+			session := &Session{
+				WorkspaceID:  workspace.ID,
+				Title:        s.Title,
+				Source:       s.Source,
+				Status:       s.Status,
+				StartTime:    s.StartTime,
+				EndTime:      s.EndTime,
+				Participants: s.Participants,
+			}
+			// An imported session can't still be recording on this machine
+			if session.Status != SessionStatusStopped {
+				session.Status = SessionStatusStopped
+				if session.EndTime == nil {
+					end := session.StartTime
+					session.EndTime = &end
+				}
+			}
+			for _, p := range s.Pauses {
+				session.Pauses = append(session.Pauses, SessionPause{PausedAt: p.PausedAt, ResumedAt: p.ResumedAt})
+			}
+			if err := tx.Create(session).Error; err != nil {
+				return fmt.Errorf("failed to create session: %v", err)
+			}
+			sessionIDs[s.ID] = session.ID
+		}
+		remapSession := func(id *uint) *uint {
+			if id == nil {
+				return nil
+			}
+			if newID, ok := sessionIDs[*id]; ok {
+				return &newID
+			}
+			return nil
+		}
+
+		renamed := 0
+		for _, t := range transcripts {
+			messageID := t.MessageID
+			var taken int64
+			tx.Model(&TranscriptionRecord{}).Unscoped().Where("message_id = ?", messageID).Count(&taken)
+			if taken > 0 || messageID == "" {
+				messageID = newMessageID("import")
+				renamed++
+			}
+			record := &TranscriptionRecord{
+				MessageID:   messageID,
+				WorkspaceID: workspace.ID,
+				SessionID:   remapSession(t.SessionID),
+				Text:        t.Text,
+				Speaker:     t.Speaker,
+				Timestamp:   t.Timestamp,
+				Source:      t.Source,
+				MessageType: t.MessageType,
+			}
+			if err := tx.Create(record).Error; err != nil {
+				return fmt.Errorf("failed to create transcript message: %v", err)
+			}
+			report.Transcripts++
+		}
+		if renamed > 0 {
+			report.Conflicts = append(report.Conflicts, fmt.Sprintf("%d transcript messages already existed (was this bundle imported before?) and were given new IDs", renamed))
+		}
+
+		chatIDs := make(map[uint]uint)
+		for _, m := range messages {
+			var replyTo *uint
+			if m.ReplyToID != nil {
+				if newID, ok := chatIDs[*m.ReplyToID]; ok {
+					replyTo = &newID
+				}
+			}
+			citations := make([]Citation, 0, len(m.Citations))
+			for _, citation := range m.Citations {
+				if newID, ok := knowledgeIDs[citation.KnowledgeBaseID]; ok {
+					citation.KnowledgeBaseID = newID
+					citation.ChunkID = 0 // chunks are rebuilt when the item is indexed
+					citations = append(citations, citation)
+				}
+			}
+
+			message := &AIChatMessage{
+				WorkspaceID:      workspace.ID,
+				SessionID:        remapSession(m.SessionID),
+				By:               m.By,
+				Text:             m.Text,
+				CreatedAt:        m.CreatedAt,
+				Model:            m.Model,
+				LatencyMs:        m.LatencyMs,
+				PromptTokens:     m.PromptTokens,
+				CompletionTokens: m.CompletionTokens,
+				ReplyToID:        replyTo,
+				Citations:        citations,
+			}
+			if err := tx.Create(message).Error; err != nil {
+				return fmt.Errorf("failed to create chat message: %v", err)
+			}
+			chatIDs[m.ID] = message.ID
+			report.ChatMessages++
+		}
+
+		if notes != nil {
+			meetingNotes := &MeetingNotes{WorkspaceID: workspace.ID, Text: string(notes)}
+			if err := tx.Create(meetingNotes).Error; err != nil {
+				return fmt.Errorf("failed to create meeting notes: %v", err)
+			}
+			if err := recordNotesRevision(tx, meetingNotes, NotesAuthorUser, false); err != nil {
+				return fmt.Errorf("failed to record meeting notes revision: %v", err)
+			}
+			report.HasNotes = true
+		}
+
+		report.Sessions = len(sessionIDs)
+		return nil
+	})
+	if err != nil {
+		cleanup()
+		log.Printf("Failed to import workspace from %s: %v", src, err)
+		return nil, err
+	}
+
+	for _, p := range pending {
+		a.indexKnowledgeBaseItemAsync(p.item.ID)
+	}
+	log.Printf("Imported workspace %q from %s with %d conflicts", report.Workspace.Title, src, len(report.Conflicts))
+	return report, nil
+}
+
+// ExportWorkspace saves a workspace bundle to path, asking where to save it when path is empty.
+// It returns the path written, or an empty string if the dialog was cancelled.
+func (a *App) ExportWorkspace(id uint, path string) (string, error) {
+	if path == "" {
+		workspace, err := GetWorkspaceByID(id)
+		if err != nil {
+			return "", err
+		}
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Export Workspace",
+			DefaultFilename: workspace.Title + ".yumesession.zip",
+			Filters:         []runtime.FileFilter{{DisplayName: "Workspace Bundles", Pattern: "*.zip"}},
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	if err := ExportWorkspace(id, path); err != nil {
+		log.Printf("Failed to export workspace %d: %v", id, err)
+		return "", err
+	}
+	return path, nil
+}
+
+// ImportWorkspace creates a workspace from a bundle at path, asking for the file when path is empty.
+// It returns nil without an error if the dialog was cancelled.
+func (a *App) ImportWorkspace(path string) (*ImportReport, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "Import Workspace",
+			Filters: []runtime.FileFilter{{DisplayName: "Workspace Bundles", Pattern: "*.zip"}},
+		})
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+	return a.importWorkspace(path)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useTestDatabase points DB at a new, migrated database in a temporary directory. The
+// directory is also made the working directory, so knowledge base files stay out of the tree.
+func useTestDatabase(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	db, err := gorm.Open(sqlite.Open(filepath.Base(databasePath)+"?_foreign_keys=on"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	previous := DB
+	DB = db
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		DB = previous
+	})
+
+	if err := runMigrations(filepath.Base(databasePath)); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+}
+
+// writeKnowledgeFile stores a knowledge base file and its item, as MoveFilesToYumesession does
+func writeKnowledgeFile(t *testing.T, name, content string) *KnowledgeBase {
+	t.Helper()
+	if err := os.MkdirAll(knowledgeBaseDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(knowledgeBaseDir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	item, err := CreateKnowledgeBaseItem(name, "Local File", "Budget figures", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func TestWorkspaceBundleRoundTrip(t *testing.T) {
+	useTestDatabase(t)
+	bundlePath := filepath.Join(t.TempDir(), "planning.yumesession.zip")
+
+	workspace, err := CreateWorkspace("Planning", "Quarterly planning")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := writeKnowledgeFile(t, "budget.txt", "Marketing gets 40% of the budget.")
+
+	session, err := StartSession(workspace.ID, "Kickoff", "zoom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddSessionParticipant(session.ID, "Alice"); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i, text := range []string{"Let's look at the budget.", "Marketing wants more."} {
+		if _, err := CreateTranscriptionMessage("caption-"+string(rune('a'+i)), workspace.ID, text, "Alice", "zoom", "caption", start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	question, err := CreateAIChatMessage(workspace.ID, "user", "How much goes to marketing?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	citations := []Citation{{Index: 1, KnowledgeBaseID: item.ID, FileName: item.UniqueFileName, ChunkID: 7, Snippet: "Marketing gets 40%"}}
+	if _, err := CreateAssistantReply(workspace.ID, &question.ID, "40% [1]", &ChatCompletionStats{Model: "llama3"}, time.Second, citations); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StopSession(session.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateMeetingNotes(workspace.ID, "# Kickoff\n\n- Marketing: 40%\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExportWorkspace(workspace.ID, bundlePath); err != nil {
+		t.Fatalf("ExportWorkspace failed: %v", err)
+	}
+
+	// Import on another machine that already has the knowledge base file under another ID
+	useTestDatabase(t)
+	if _, err := CreateKnowledgeBaseItem("", "Website Link", "Unrelated", "unrelated page"); err != nil {
+		t.Fatal(err)
+	}
+	existing := writeKnowledgeFile(t, "budget.txt", "Marketing gets 40% of the budget.")
+
+	report, err := (&App{}).importWorkspace(bundlePath)
+	if err != nil {
+		t.Fatalf("importWorkspace failed: %v", err)
+	}
+	if report.Sessions != 1 || report.Transcripts != 2 || report.ChatMessages != 2 || !report.HasNotes {
+		t.Errorf("report = %+v", report)
+	}
+	if report.KnowledgeDeduplicated != 1 || report.KnowledgeImported != 0 || len(report.Conflicts) != 0 {
+		t.Errorf("knowledge = %d deduplicated, %d imported, conflicts %q", report.KnowledgeDeduplicated, report.KnowledgeImported, report.Conflicts)
+	}
+
+	imported := report.Workspace
+	if imported.Title != "Planning" || imported.Description != "Quarterly planning" {
+		t.Errorf("workspace = %+v", imported)
+	}
+
+	sessions, err := GetSessionsByWorkspace(imported.ID)
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("sessions = %v, %v", sessions, err)
+	}
+	if sessions[0].Title != "Kickoff" || sessions[0].Status != SessionStatusStopped || strings.Join(sessions[0].Participants, ",") != "Alice" {
+		t.Errorf("session = %+v", sessions[0])
+	}
+
+	records, err := GetTranscriptionMessagesByWorkspace(imported.ID)
+	if err != nil || len(records) != 2 {
+		t.Fatalf("transcripts = %v, %v", records, err)
+	}
+	for _, record := range records {
+		if record.SessionID == nil || *record.SessionID != sessions[0].ID {
+			t.Errorf("transcript %q is not linked to the imported session", record.Text)
+		}
+	}
+	if !records[0].Timestamp.Equal(start) || records[0].Text != "Let's look at the budget." {
+		t.Errorf("first transcript = %+v", records[0])
+	}
+
+	chat, err := GetAIChatMessagesByWorkspace(imported.ID)
+	if err != nil || len(chat) != 2 {
+		t.Fatalf("chat = %v, %v", chat, err)
+	}
+	var reply AIChatMessage
+	for _, message := range chat {
+		if message.By == "assistant" {
+			reply = message
+		}
+	}
+	if reply.ReplyToID == nil {
+		t.Fatalf("reply = %+v", reply)
+	}
+	var repliedTo AIChatMessage
+	if err := DB.First(&repliedTo, *reply.ReplyToID).Error; err != nil || repliedTo.Text != "How much goes to marketing?" || repliedTo.WorkspaceID != imported.ID {
+		t.Errorf("reply points at %+v, %v", repliedTo, err)
+	}
+	if reply.Model != "llama3" || len(reply.Citations) != 1 {
+		t.Fatalf("reply = %+v", reply)
+	}
+	if citation := reply.Citations[0]; citation.KnowledgeBaseID != existing.ID || citation.ChunkID != 0 || citation.Snippet != "Marketing gets 40%" {
+		t.Errorf("citation = %+v, want it remapped to item %d", citation, existing.ID)
+	}
+
+	notes, err := GetMeetingNotesByWorkspace(imported.ID)
+	if err != nil || len(notes) != 1 || notes[0].Text != "# Kickoff\n\n- Marketing: 40%\n" {
+		t.Errorf("notes = %v, %v", notes, err)
+	}
+}
+
+func TestImportWorkspaceRejectsNewerFormat(t *testing.T) {
+	useTestDatabase(t)
+	bundlePath := filepath.Join(t.TempDir(), "future.zip")
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	w, err := archive.Create(bundleManifestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewEncoder(w).Encode(WorkspaceBundleManifest{FormatVersion: bundleFormatVersion + 1})
+	archive.Close()
+	file.Close()
+
+	if _, err := (&App{}).importWorkspace(bundlePath); err == nil || !strings.Contains(err.Error(), "newer version") {
+		t.Errorf("err = %v, want the bundle rejected", err)
+	}
+	var workspaces int64
+	DB.Model(&Workspace{}).Count(&workspaces)
+	if workspaces != 0 {
+		t.Errorf("%d workspaces created from a rejected bundle", workspaces)
+	}
+}
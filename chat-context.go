@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// transcriptSegmentSize is the number of transcript lines summarised together.
+// Segments are aligned from the start of the transcript so their summaries can be reused.
+const transcriptSegmentSize = 40
+
+// Default token budget for the context of a chat request
+const defaultContextTokens = 8192
+
+// TranscriptSummary represents a cached summary of one segment of a workspace's transcript
+type TranscriptSummary struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	WorkspaceID   uint      `gorm:"not null;uniqueIndex:idx_transcript_summary_segment" json:"workspaceId"`
+	FirstRecordID uint      `gorm:"not null;uniqueIndex:idx_transcript_summary_segment" json:"firstRecordId"`
+	LastRecordID  uint      `gorm:"not null;uniqueIndex:idx_transcript_summary_segment" json:"lastRecordId"`
+	StartTime     time.Time `json:"startTime"`
+	EndTime       time.Time `json:"endTime"`
+	Text          string    `gorm:"type:text" json:"text"`
+	Model         string    `json:"model"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// ChatContext holds the workspace context sent along with a chat message
+type ChatContext struct {
+	Transcription []string         // summaries of older segments followed by recent "Speaker: text" lines
+	ChatHistory   []AIChatMessage  // previous chat messages, oldest first
+	MeetingNotes  string           // current meeting notes (Markdown), possibly truncated
+	Knowledge     []RetrievedChunk // knowledge base chunks relevant to the message, most relevant first
+	Info          ChatContextInfo
+
+	// Sections the system prompt template already includes, left out of the user prompt
+	transcriptInSystem, notesInSystem, knowledgeInSystem bool
+}
+
+// Citations describes the knowledge base chunks included in the context, labelled as in the prompt
+func (c ChatContext) Citations() []Citation {
+	citations := make([]Citation, 0, len(c.Knowledge))
+	for i, retrieved := range c.Knowledge {
+		citations = append(citations, citationFor(i+1, retrieved))
+	}
+	return citations
+}
+
+// ChatContextInfo reports what was included in a chat request (chatContextInfo event)
+type ChatContextInfo struct {
+	WorkspaceID          uint `json:"workspaceId"`
+	TokenBudget          int  `json:"tokenBudget"`
+	EstimatedTokens      int  `json:"estimatedTokens"`
+	TranscriptLinesTotal int  `json:"transcriptLinesTotal"`
+	TranscriptVerbatim   int  `json:"transcriptVerbatim"` // recent lines included as-is
+	SummarizedSegments   int  `json:"summarizedSegments"` // older segments included as summaries
+	PendingSummaries     int  `json:"pendingSummaries"`   // segments shortened because no summary exists yet
+	OmittedSegments      int  `json:"omittedSegments"`    // oldest segments that did not fit at all
+	ChatMessagesTotal    int  `json:"chatMessagesTotal"`
+	ChatMessagesIncluded int  `json:"chatMessagesIncluded"`
+	NotesIncluded        bool `json:"notesIncluded"`
+	NotesTruncated       bool `json:"notesTruncated"`
+	KnowledgeRetrieved   int  `json:"knowledgeRetrieved"` // knowledge base chunks matching the message
+	KnowledgeIncluded    int  `json:"knowledgeIncluded"`
+}
+
+// transcriptSegment is a run of consecutive transcript records
+type transcriptSegment struct {
+	records []TranscriptionRecord
+}
+
+func (s transcriptSegment) first() TranscriptionRecord { return s.records[0] }
+func (s transcriptSegment) last() TranscriptionRecord  { return s.records[len(s.records)-1] }
+
+// estimateTokens roughly estimates the number of tokens in text (about 4 characters per token)
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// truncateToTokens shortens text to roughly maxTokens, cutting at a word boundary
+func truncateToTokens(text string, maxTokens int) string {
+	if estimateTokens(text) <= maxTokens {
+		return text
+	}
+	runes := []rune(text)
+	limit := maxTokens * 4
+	if limit <= 0 {
+		return ""
+	}
+	if limit > len(runes) {
+		limit = len(runes)
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndexAny(cut, " \n"); i > limit/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + " …"
+}
+
+// formatTranscriptLine formats a transcript record the way it is shown to the model
+func formatTranscriptLine(record TranscriptionRecord) string {
+	return fmt.Sprintf("%s: %s", record.Speaker, record.Text)
+}
+
+// contextTokenBudget returns the configured context budget for chat requests
+func contextTokenBudget() int {
+	budget, err := strconv.Atoi(GetSetting(SettingContextTokens))
+	if err != nil || budget <= 0 {
+		return defaultContextTokens
+	}
+	return budget
+}
+
+// buildChatContext assembles the context for a chat request within the token budget:
+// meeting notes, knowledge base excerpts and recent chat history get a bounded share,
+// the most recent transcript lines are kept verbatim and older transcript segments are
+// replaced by summaries.
+func buildChatContext(workspaceID uint, systemPrompt, message string) ChatContext {
+	budget := contextTokenBudget()
+	chatContext := ChatContext{Info: ChatContextInfo{WorkspaceID: workspaceID, TokenBudget: budget}}
+
+	// Leave a quarter of the window for the answer
+	remaining := budget*3/4 - estimateTokens(systemPrompt) - estimateTokens(message)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	// Meeting notes: up to a quarter of what is left
+	meetingNotesList, _ := GetMeetingNotesByWorkspace(workspaceID)
+	if len(meetingNotesList) > 0 && meetingNotesList[0].Text != "" {
+		notes := truncateToTokens(meetingNotesList[0].Text, remaining/4)
+		chatContext.MeetingNotes = notes
+		chatContext.Info.NotesIncluded = notes != ""
+		chatContext.Info.NotesTruncated = notes != meetingNotesList[0].Text
+		remaining -= estimateTokens(notes)
+	}
+
+	// Knowledge base: the chunks most relevant to the message, up to a quarter of what is left
+	retrieved := retrieveKnowledge(message, retrievalTopK())
+	chatContext.Info.KnowledgeRetrieved = len(retrieved)
+	knowledgeBudget := remaining / 4
+	for _, chunk := range retrieved {
+		cost := estimateTokens(chunk.Chunk.Text) + 10
+		if cost > knowledgeBudget {
+			break
+		}
+		knowledgeBudget -= cost
+		remaining -= cost
+		chatContext.Knowledge = append(chatContext.Knowledge, chunk)
+	}
+	chatContext.Info.KnowledgeIncluded = len(chatContext.Knowledge)
+
+	// Chat history: newest messages first, up to a fifth of what is left
+	chatHistory, _ := GetAIChatMessagesByWorkspace(workspaceID)
+	// Callers may store the message being sent before the context is built
+	if n := len(chatHistory); n > 0 && chatHistory[n-1].By == "user" && chatHistory[n-1].Text == message {
+		chatHistory = chatHistory[:n-1]
+	}
+	chatContext.Info.ChatMessagesTotal = len(chatHistory)
+	historyBudget := remaining / 5
+	start := len(chatHistory)
+	for start > 0 {
+		cost := estimateTokens(chatHistory[start-1].Text) + 2
+		if cost > historyBudget {
+			break
+		}
+		historyBudget -= cost
+		remaining -= cost
+		start--
+	}
+	chatContext.ChatHistory = chatHistory[start:]
+	chatContext.Info.ChatMessagesIncluded = len(chatContext.ChatHistory)
+
+	// Transcript: everything that is left
+	transcriptions, _ := GetTranscriptionMessagesByWorkspace(workspaceID)
+	chatContext.Info.TranscriptLinesTotal = len(transcriptions)
+	chatContext.Transcription = fitTranscript(workspaceID, transcriptions, remaining, &chatContext.Info)
+
+	chatContext.Info.EstimatedTokens = estimateTokens(systemPrompt) + estimateTokens(buildChatPrompt(chatContext, message))
+	return chatContext
+}
+
+// fitTranscript returns the transcript lines to include within budget tokens
+func fitTranscript(workspaceID uint, records []TranscriptionRecord, budget int, info *ChatContextInfo) []string {
+	// Short transcripts are included verbatim in full
+	total := 0
+	for _, record := range records {
+		total += estimateTokens(formatTranscriptLine(record)) + 1
+	}
+	if total <= budget {
+		lines := make([]string, 0, len(records))
+		for _, record := range records {
+			lines = append(lines, formatTranscriptLine(record))
+		}
+		info.TranscriptVerbatim = len(records)
+		return lines
+	}
+
+	// Otherwise the most recent lines are kept verbatim within half of the budget
+	verbatimBudget := budget / 2
+	verbatimStart := len(records)
+	used := 0
+	for verbatimStart > 0 {
+		cost := estimateTokens(formatTranscriptLine(records[verbatimStart-1])) + 1
+		if used+cost > verbatimBudget {
+			break
+		}
+		used += cost
+		verbatimStart--
+	}
+
+	// Everything older is represented per segment. Align the boundary to a segment
+	// so the segments match the cached summaries, keeping the partial segment verbatim.
+	segmentEnd := (verbatimStart / transcriptSegmentSize) * transcriptSegmentSize
+	for _, record := range records[segmentEnd:verbatimStart] {
+		used += estimateTokens(formatTranscriptLine(record)) + 1
+	}
+	verbatimStart = segmentEnd
+
+	var segments []transcriptSegment
+	for i := 0; i < segmentEnd; i += transcriptSegmentSize {
+		segments = append(segments, transcriptSegment{records: records[i : i+transcriptSegmentSize]})
+	}
+
+	summaries := loadTranscriptSummaries(workspaceID)
+
+	// Walk back from the newest older segment, until the budget runs out
+	var older []string
+	var missing []transcriptSegment
+	remaining := budget - used
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := segments[i]
+		key := summaryKey(segment.first().ID, segment.last().ID)
+
+		summary, summarized := summaries[key]
+		var line string
+		if summarized {
+			line = fmt.Sprintf("[Summary %s–%s] %s", segment.first().Timestamp.Format("15:04"), segment.last().Timestamp.Format("15:04"), summary.Text)
+		} else {
+			line = fmt.Sprintf("[Excerpt %s–%s] %s", segment.first().Timestamp.Format("15:04"), segment.last().Timestamp.Format("15:04"), excerptSegment(segment, 120))
+			missing = append(missing, segment)
+		}
+
+		cost := estimateTokens(line) + 1
+		if cost > remaining {
+			info.OmittedSegments = i + 1
+			break
+		}
+		remaining -= cost
+		older = append([]string{line}, older...)
+		if summarized {
+			info.SummarizedSegments++
+		} else {
+			info.PendingSummaries++
+		}
+	}
+
+	if len(missing) > 0 {
+		go summarizeTranscriptSegments(workspaceID, missing)
+	}
+
+	lines := older
+	for _, record := range records[verbatimStart:] {
+		lines = append(lines, formatTranscriptLine(record))
+	}
+	info.TranscriptVerbatim = len(records) - verbatimStart
+	return lines
+}
+
+// excerptSegment is a cheap stand-in for a summary: the start of every speaker turn
+func excerptSegment(segment transcriptSegment, maxTokens int) string {
+	var turns []string
+	lastSpeaker := ""
+	for _, record := range segment.records {
+		if record.Speaker == lastSpeaker {
+			continue
+		}
+		lastSpeaker = record.Speaker
+		turns = append(turns, fmt.Sprintf("%s: %s", record.Speaker, truncateToTokens(record.Text, 12)))
+	}
+	return truncateToTokens(strings.Join(turns, " / "), maxTokens)
+}
+
+func summaryKey(firstRecordID, lastRecordID uint) string {
+	return fmt.Sprintf("%d-%d", firstRecordID, lastRecordID)
+}
+
+// loadTranscriptSummaries returns the cached segment summaries of a workspace by segment key
+func loadTranscriptSummaries(workspaceID uint) map[string]TranscriptSummary {
+	var summaries []TranscriptSummary
+	if err := DB.Where("workspace_id = ?", workspaceID).Find(&summaries).Error; err != nil {
+		log.Printf("Failed to get transcript summaries for workspace %d: %v", workspaceID, err)
+	}
+
+	byKey := make(map[string]TranscriptSummary, len(summaries))
+	for _, summary := range summaries {
+		byKey[summaryKey(summary.FirstRecordID, summary.LastRecordID)] = summary
+	}
+	return byKey
+}
+
+// Workspaces whose transcript segments are currently being summarised
+var summarizingWorkspaces sync.Map
+
+// summarizeTranscriptSegments summarises segments in the background and caches the results,
+// so that later chat requests can use them instead of excerpts
+func summarizeTranscriptSegments(workspaceID uint, segments []transcriptSegment) {
+	if _, busy := summarizingWorkspaces.LoadOrStore(workspaceID, true); busy {
+		return
+	}
+	defer summarizingWorkspaces.Delete(workspaceID)
+
+	provider, err := chatProviderForWorkspace(workspaceID)
+	if err != nil {
+		log.Printf("Cannot summarise transcript for workspace %d: %v", workspaceID, err)
+		return
+	}
+
+	for _, segment := range segments {
+		lines := make([]string, 0, len(segment.records))
+		for _, record := range segment.records {
+			lines = append(lines, formatTranscriptLine(record))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		text, stats, err := provider.Complete(ctx, []ChatMessage{
+			{Role: "system", Content: "You summarise meeting transcripts. Reply with a short factual summary (at most 80 words) of what was said, naming speakers, decisions and action items. No preamble."},
+			{Role: "user", Content: strings.Join(lines, "\n")},
+		})
+		cancel()
+		if err != nil {
+			log.Printf("Failed to summarise transcript segment %d-%d: %v", segment.first().ID, segment.last().ID, err)
+			return
+		}
+
+		summary := &TranscriptSummary{
+			WorkspaceID:   workspaceID,
+			FirstRecordID: segment.first().ID,
+			LastRecordID:  segment.last().ID,
+			StartTime:     segment.first().Timestamp,
+			EndTime:       segment.last().Timestamp,
+			Text:          strings.TrimSpace(text),
+			Model:         stats.Model,
+		}
+		if err := DB.Create(summary).Error; err != nil {
+			log.Printf("Failed to save transcript summary: %v", err)
+			return
+		}
+	}
+	log.Printf("Summarised %d transcript segments for workspace %d", len(segments), workspaceID)
+}
+
+// formatKnowledgeExcerpts lists knowledge base chunks labelled [1], [2]... as citations refer to them
+func formatKnowledgeExcerpts(chunks []RetrievedChunk) string {
+	var excerpts strings.Builder
+	for i, retrieved := range chunks {
+		excerpts.WriteString(fmt.Sprintf("[%d] %s, offset %d:\n%s\n\n", i+1, knowledgeSourceName(retrieved.Item), retrieved.Chunk.Offset, retrieved.Chunk.Text))
+	}
+	return strings.TrimSpace(excerpts.String())
+}
+
+// buildChatPrompt combines the workspace context and the user message into a single prompt
+func buildChatPrompt(chatContext ChatContext, message string) string {
+	var prompt strings.Builder
+	if len(chatContext.Transcription) > 0 && !chatContext.transcriptInSystem {
+		prompt.WriteString("Transcription (oldest first):\n" + strings.Join(chatContext.Transcription, "\n") + "\n\n")
+	}
+	if len(chatContext.ChatHistory) > 0 {
+		lines := make([]string, 0, len(chatContext.ChatHistory))
+		for _, entry := range chatContext.ChatHistory {
+			lines = append(lines, fmt.Sprintf("%s: %s", entry.By, entry.Text))
+		}
+		prompt.WriteString("Chat History (oldest first):\n" + strings.Join(lines, "\n") + "\n\n")
+	}
+	if chatContext.MeetingNotes != "" && !chatContext.notesInSystem {
+		prompt.WriteString("Meeting Notes:\n" + chatContext.MeetingNotes + "\n\n")
+	}
+	if len(chatContext.Knowledge) > 0 && !chatContext.knowledgeInSystem {
+		prompt.WriteString("Knowledge Base Excerpts (cite the ones you use as [n]):\n" + formatKnowledgeExcerpts(chatContext.Knowledge) + "\n\n")
+	}
+	if message != "" {
+		prompt.WriteString("User Message:\n" + message + "\n")
+	}
+	return strings.TrimSpace(prompt.String())
+}
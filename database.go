@@ -10,14 +10,25 @@ import (
 
 var DB *gorm.DB
 
+// Chat backends (chat providers) a workspace can use
+const (
+	ChatBackendPython = "python" // Python bridge at ws://localhost:8000/ws/chat
+	ChatBackendOllama = "ollama" // Ollama /api/chat, called directly from Go
+	ChatBackendOpenAI = "openai" // any OpenAI-compatible chat completions server
+)
+
 // Workspace represents a workspace in the database
 type Workspace struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
 	Title        string    `gorm:"not null" json:"title"`
 	Description  string    `json:"description"`
+	ChatBackend  string    `gorm:"default:python" json:"chatBackend"` // chat provider name: "python", "ollama" or "openai"
 	LastOpenTime time.Time `json:"lastOpenTime"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
+
+	ArchivedAt *time.Time     `gorm:"index" json:"archivedAt"` // hidden from the workspace list while set
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deletedAt"`  // set while the workspace is in the trash
 }
 
 // TranscriptionRecord represents a transcription message in the database
@@ -25,6 +36,7 @@ type TranscriptionRecord struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	MessageID   string    `gorm:"uniqueIndex;not null" json:"messageId"`
 	WorkspaceID uint      `gorm:"not null" json:"workspaceId"`
+	SessionID   *uint     `gorm:"index" json:"sessionId"` // Session that produced the message, if any
 	Text        string    `gorm:"not null" json:"text"`
 	Speaker     string    `gorm:"not null" json:"speaker"`
 	Timestamp   time.Time `gorm:"not null" json:"timestamp"`
@@ -33,19 +45,22 @@ type TranscriptionRecord struct {
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
 	// Foreign key relationship
 	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 }
 
 // KnowledgeBase represents a knowledge base item in the database
 type KnowledgeBase struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	UniqueFileName string    `json:"uniqueFileName"`       // Can be empty for website links
-	Type           string    `gorm:"not null" json:"type"` // "Local File" or "Website Link"
-	OneLineSummary string    `gorm:"not null" json:"oneLineSummary"`
-	FullSummary    string    `gorm:"type:text" json:"fullSummary"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UniqueFileName string     `json:"uniqueFileName"`       // Can be empty for website links
+	Type           string     `gorm:"not null" json:"type"` // "Local File" or "Website Link"
+	OneLineSummary string     `gorm:"not null" json:"oneLineSummary"`
+	FullSummary    string     `gorm:"type:text" json:"fullSummary"`
+	IndexedAt      *time.Time `json:"indexedAt"` // last time the text was chunked for retrieval
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
 // MeetingNotes represents meeting notes in the database
@@ -56,6 +71,8 @@ type MeetingNotes struct {
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
 	// Foreign key relationship
 	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 }
@@ -65,33 +82,52 @@ type MeetingNotes struct {
 type AIChatMessage struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	WorkspaceID uint      `gorm:"not null" json:"workspaceId"`
-	By          string    `gorm:"not null" json:"by"` // "Assistant" or "User"
+	SessionID   *uint     `gorm:"index" json:"sessionId"` // Session the message was sent in, if any
+	By          string    `gorm:"not null" json:"by"`     // "Assistant" or "User"
 	Text        string    `gorm:"type:text" json:"text"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
+	// Generation details, only set on assistant replies
+	Model            string     `json:"model,omitempty"`
+	LatencyMs        int64      `json:"latencyMs,omitempty"`
+	PromptTokens     int        `json:"promptTokens,omitempty"`
+	CompletionTokens int        `json:"completionTokens,omitempty"`
+	ReplyToID        *uint      `gorm:"index" json:"replyToId"` // User message the reply answers
+	Citations        []Citation `gorm:"serializer:json" json:"citations,omitempty"`
+
 	// Foreign key relationship
 	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 }
 
+// Location of the SQLite database file
+const databasePath = "yumesession/yumesession.db"
+
 // InitDatabase initializes the database connection and creates tables
 func InitDatabase() error {
 	var err error
 
 	// Open SQLite database (creates file if it doesn't exist)
-	DB, err = gorm.Open(sqlite.Open("yumesession/yumesession.db"), &gorm.Config{})
+	// SQLite only enforces foreign keys when asked to, on every connection
+	DB, err = gorm.Open(sqlite.Open(databasePath+"?_foreign_keys=on"), &gorm.Config{})
 	if err != nil {
 		log.Printf("Failed to connect to database: %v", err)
 		return err
 	}
 
-	// Auto-migrate the schema (creates tables if they don't exist)
-	err = DB.AutoMigrate(&Workspace{}, &TranscriptionRecord{}, &KnowledgeBase{}, &MeetingNotes{}, &AIChatMessage{})
-	if err != nil {
+	// Bring the schema up to date (creates tables if they don't exist)
+	if err := runMigrations(databasePath); err != nil {
 		log.Printf("Failed to migrate database: %v", err)
 		return err
 	}
 
+	// Search still works through LIKE matching without the full-text indexes
+	if err := setupSearchIndex(); err != nil {
+		log.Printf("Failed to set up search index: %v", err)
+	}
+
 	log.Println("Database initialized successfully")
 	return nil
 }
@@ -101,6 +137,7 @@ func CreateWorkspace(title, description string) (*Workspace, error) {
 	workspace := &Workspace{
 		Title:        title,
 		Description:  description,
+		ChatBackend:  GetSetting(SettingChatProvider),
 		LastOpenTime: time.Now(),
 	}
 
@@ -114,10 +151,10 @@ func CreateWorkspace(title, description string) (*Workspace, error) {
 	return workspace, nil
 }
 
-// GetAllWorkspaces retrieves all workspaces
+// GetAllWorkspaces retrieves all workspaces that are not archived
 func GetAllWorkspaces() ([]Workspace, error) {
 	var workspaces []Workspace
-	result := DB.Find(&workspaces)
+	result := DB.Where("archived_at IS NULL").Find(&workspaces)
 	if result.Error != nil {
 		log.Printf("Failed to get workspaces: %v", result.Error)
 		return nil, result.Error
@@ -166,12 +203,37 @@ func UpdateWorkspace(id uint, title, description string) (*Workspace, error) {
 	return &workspace, nil
 }
 
-// DeleteWorkspace deletes a workspace
-func DeleteWorkspace(id uint) error {
-	result := DB.Delete(&Workspace{}, id)
+// UpdateWorkspaceChatBackend selects the chat backend used by a workspace
+func UpdateWorkspaceChatBackend(id uint, backend string) (*Workspace, error) {
+	if _, err := getChatProvider(backend); err != nil {
+		return nil, err
+	}
+
+	var workspace Workspace
+	result := DB.First(&workspace, id)
 	if result.Error != nil {
-		log.Printf("Failed to delete workspace: %v", result.Error)
-		return result.Error
+		return nil, result.Error
+	}
+
+	workspace.ChatBackend = backend
+
+	result = DB.Save(&workspace)
+	if result.Error != nil {
+		log.Printf("Failed to update workspace chat backend: %v", result.Error)
+		return nil, result.Error
+	}
+
+	return &workspace, nil
+}
+
+// DeleteWorkspace moves a workspace along with its transcripts, notes, chat and sessions to the trash
+func DeleteWorkspace(id uint) error {
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		return trashWorkspaceRows(tx, id)
+	})
+	if err != nil {
+		log.Printf("Failed to delete workspace: %v", err)
+		return err
 	}
 	return nil
 }
@@ -180,9 +242,14 @@ func DeleteWorkspace(id uint) error {
 
 // CreateTranscriptionMessage creates a new transcription message
 func CreateTranscriptionMessage(messageID string, workspaceID uint, text, speaker, source, messageType string, timestamp time.Time) (*TranscriptionRecord, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
 	transcriptionMsg := &TranscriptionRecord{
 		MessageID:   messageID,
 		WorkspaceID: workspaceID,
+		SessionID:   openSessionID(workspaceID),
 		Text:        text,
 		Speaker:     speaker,
 		Timestamp:   timestamp,
@@ -211,6 +278,17 @@ func GetTranscriptionMessagesByWorkspace(workspaceID uint) ([]TranscriptionRecor
 	return messages, nil
 }
 
+// GetTranscriptionMessagesBySession retrieves all transcription messages recorded during a session
+func GetTranscriptionMessagesBySession(sessionID uint) ([]TranscriptionRecord, error) {
+	var messages []TranscriptionRecord
+	result := DB.Where("session_id = ?", sessionID).Order("timestamp ASC").Find(&messages)
+	if result.Error != nil {
+		log.Printf("Failed to get transcription messages for session %d: %v", sessionID, result.Error)
+		return nil, result.Error
+	}
+	return messages, nil
+}
+
 // GetTranscriptionMessageByID retrieves a transcription message by database ID
 func GetTranscriptionMessageByID(id uint) (*TranscriptionRecord, error) {
 	var message TranscriptionRecord
@@ -257,7 +335,7 @@ func UpdateTranscriptionMessage(messageID string, text, speaker string, timestam
 
 // DeleteTranscriptionMessage deletes a transcription message
 func DeleteTranscriptionMessage(id uint) error {
-	result := DB.Delete(&TranscriptionRecord{}, id)
+	result := DB.Unscoped().Delete(&TranscriptionRecord{}, id)
 	if result.Error != nil {
 		log.Printf("Failed to delete transcription message: %v", result.Error)
 		return result.Error
@@ -267,7 +345,7 @@ func DeleteTranscriptionMessage(id uint) error {
 
 // DeleteTranscriptionMessagesByWorkspace deletes all transcription messages for a workspace
 func DeleteTranscriptionMessagesByWorkspace(workspaceID uint) error {
-	result := DB.Where("workspace_id = ?", workspaceID).Delete(&TranscriptionRecord{})
+	result := DB.Unscoped().Where("workspace_id = ?", workspaceID).Delete(&TranscriptionRecord{})
 	if result.Error != nil {
 		log.Printf("Failed to delete transcription messages for workspace %d: %v", workspaceID, result.Error)
 		return result.Error
@@ -382,7 +460,7 @@ func DeleteKnowledgeBaseItem(id uint) error {
 		log.Printf("Failed to delete knowledge base item: %v", result.Error)
 		return result.Error
 	}
-	return nil
+	return DeleteKnowledgeChunks(id)
 }
 
 // SearchKnowledgeBaseItems searches knowledge base items by summary content
@@ -402,15 +480,27 @@ func SearchKnowledgeBaseItems(searchTerm string) ([]KnowledgeBase, error) {
 
 // CreateMeetingNotes creates new meeting notes
 func CreateMeetingNotes(workspaceID uint, text string) (*MeetingNotes, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
 	meetingNotes := &MeetingNotes{
 		WorkspaceID: workspaceID,
 		Text:        text,
 	}
 
-	result := DB.Create(meetingNotes)
-	if result.Error != nil {
-		log.Printf("Failed to create meeting notes: %v", result.Error)
-		return nil, result.Error
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(meetingNotes).Error; err != nil {
+			return err
+		}
+		if text == "" {
+			return nil
+		}
+		return recordNotesRevision(tx, meetingNotes, NotesAuthorUser, false)
+	})
+	if err != nil {
+		log.Printf("Failed to create meeting notes: %v", err)
+		return nil, err
 	}
 
 	log.Printf("Created meeting notes for workspace %d", workspaceID)
@@ -439,42 +529,37 @@ func GetMeetingNotesByID(id uint) (*MeetingNotes, error) {
 	return &notes, nil
 }
 
-// UpdateMeetingNotes updates meeting notes
+// UpdateMeetingNotes updates meeting notes edited by the user, recording the revision
 func UpdateMeetingNotes(id uint, text string) (*MeetingNotes, error) {
-	var notes MeetingNotes
-	result := DB.First(&notes, id)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	notes.Text = text
-
-	result = DB.Save(&notes)
-	if result.Error != nil {
-		log.Printf("Failed to update meeting notes: %v", result.Error)
-		return nil, result.Error
-	}
-
-	log.Printf("Updated meeting notes ID %d", id)
-	return &notes, nil
+	return saveMeetingNotes(id, text, NotesAuthorUser, true)
 }
 
 // DeleteMeetingNotes deletes meeting notes
 func DeleteMeetingNotes(id uint) error {
-	result := DB.Delete(&MeetingNotes{}, id)
-	if result.Error != nil {
-		log.Printf("Failed to delete meeting notes: %v", result.Error)
-		return result.Error
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("notes_id = ?", id).Delete(&MeetingNotesRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&MeetingNotes{}, id).Error
+	})
+	if err != nil {
+		log.Printf("Failed to delete meeting notes: %v", err)
+		return err
 	}
 	return nil
 }
 
 // DeleteMeetingNotesByWorkspace deletes all meeting notes for a workspace
 func DeleteMeetingNotesByWorkspace(workspaceID uint) error {
-	result := DB.Where("workspace_id = ?", workspaceID).Delete(&MeetingNotes{})
-	if result.Error != nil {
-		log.Printf("Failed to delete meeting notes for workspace %d: %v", workspaceID, result.Error)
-		return result.Error
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ?", workspaceID).Delete(&MeetingNotesRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("workspace_id = ?", workspaceID).Delete(&MeetingNotes{}).Error
+	})
+	if err != nil {
+		log.Printf("Failed to delete meeting notes for workspace %d: %v", workspaceID, err)
+		return err
 	}
 	return nil
 }
@@ -496,8 +581,13 @@ func SearchMeetingNotes(workspaceID uint, searchTerm string) ([]MeetingNotes, er
 
 // CreateAIChatMessage creates a new AI chat message
 func CreateAIChatMessage(workspaceID uint, by, text string) (*AIChatMessage, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
 	msg := &AIChatMessage{
 		WorkspaceID: workspaceID,
+		SessionID:   openSessionID(workspaceID),
 		By:          by,
 		Text:        text,
 	}
@@ -509,6 +599,35 @@ func CreateAIChatMessage(workspaceID uint, by, text string) (*AIChatMessage, err
 	return msg, nil
 }
 
+// CreateAssistantReply stores a generated reply together with how it was generated
+func CreateAssistantReply(workspaceID uint, replyToID *uint, text string, stats *ChatCompletionStats, latency time.Duration, citations []Citation) (*AIChatMessage, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	msg := &AIChatMessage{
+		WorkspaceID: workspaceID,
+		SessionID:   openSessionID(workspaceID),
+		By:          "assistant",
+		Text:        text,
+		LatencyMs:   latency.Milliseconds(),
+		ReplyToID:   replyToID,
+		Citations:   citations,
+	}
+	if stats != nil {
+		msg.Model = stats.Model
+		msg.PromptTokens = stats.PromptTokens
+		msg.CompletionTokens = stats.CompletionTokens
+	}
+
+	result := DB.Create(msg)
+	if result.Error != nil {
+		log.Printf("Failed to create assistant reply: %v", result.Error)
+		return nil, result.Error
+	}
+	return msg, nil
+}
+
 // GetAIChatMessageByID retrieves an AI chat message by ID
 func GetAIChatMessageByID(id uint) (*AIChatMessage, error) {
 	var msg AIChatMessage
@@ -542,6 +661,17 @@ func GetAIChatMessagesByWorkspace(workspaceID uint) ([]AIChatMessage, error) {
 	return messages, nil
 }
 
+// GetAIChatMessagesBySession retrieves all AI chat messages sent during a session
+func GetAIChatMessagesBySession(sessionID uint) ([]AIChatMessage, error) {
+	var messages []AIChatMessage
+	result := DB.Where("session_id = ?", sessionID).Order("created_at ASC").Find(&messages)
+	if result.Error != nil {
+		log.Printf("Failed to get AI chat messages for session %d: %v", sessionID, result.Error)
+		return nil, result.Error
+	}
+	return messages, nil
+}
+
 // UpdateAIChatMessage updates the workspace, text, or by field of an AI chat message
 func UpdateAIChatMessage(id uint, workspaceID uint, by, text string) (*AIChatMessage, error) {
 	var msg AIChatMessage
@@ -562,7 +692,7 @@ func UpdateAIChatMessage(id uint, workspaceID uint, by, text string) (*AIChatMes
 
 // DeleteAIChatMessage deletes an AI chat message by ID
 func DeleteAIChatMessage(id uint) error {
-	result := DB.Delete(&AIChatMessage{}, id)
+	result := DB.Unscoped().Delete(&AIChatMessage{}, id)
 	if result.Error != nil {
 		log.Printf("Failed to delete AI chat message: %v", result.Error)
 		return result.Error
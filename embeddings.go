@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Default Ollama model used to embed knowledge base chunks
+const defaultEmbeddingModel = "nomic-embed-text"
+
+// Number of chunks sent to the embeddings endpoint per request
+const embeddingBatchSize = 32
+
+// Chunks less similar than this to a chat question are not included in its context
+const minSemanticScore = 0.3
+
+// KnowledgeSearchResult is a knowledge base chunk matching a search query
+type KnowledgeSearchResult struct {
+	ChunkID         uint    `json:"chunkId"`
+	KnowledgeBaseID uint    `json:"knowledgeBaseId"`
+	FileName        string  `json:"fileName"`
+	Ordinal         int     `json:"ordinal"`
+	Offset          int     `json:"offset"`
+	Text            string  `json:"text"`
+	Score           float64 `json:"score"` // cosine similarity
+}
+
+// Embed returns one embedding per input using Ollama's /api/embed endpoint
+func (c *OllamaClient) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"model": model,
+		"input": inputs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embeddings request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/api/embed", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama not accessible at %s: %v", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama embeddings API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings: %v", err)
+	}
+	if len(result.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("Ollama returned %d embeddings for %d inputs", len(result.Embeddings), len(inputs))
+	}
+	return result.Embeddings, nil
+}
+
+// encodeEmbedding stores a vector as little-endian float32s
+func encodeEmbedding(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(value))
+	}
+	return buf
+}
+
+// decodeEmbedding reverses encodeEmbedding
+func decodeEmbedding(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector
+}
+
+// normalize scales vector to unit length so that cosine similarity is a dot product
+func normalize(vector []float32) []float32 {
+	var sum float64
+	for _, value := range vector {
+		sum += float64(value) * float64(value)
+	}
+	if sum == 0 {
+		return vector
+	}
+	norm := float32(1 / math.Sqrt(sum))
+	normalized := make([]float32, len(vector))
+	for i, value := range vector {
+		normalized[i] = value * norm
+	}
+	return normalized
+}
+
+// embeddingIndex keeps the normalized vectors of all chunks embedded with one model in memory.
+// Searching is a brute-force scan, which stays in the low milliseconds for tens of thousands of chunks.
+type embeddingIndex struct {
+	mutex    sync.RWMutex
+	loaded   bool
+	model    string
+	chunkIDs []uint
+	vectors  [][]float32
+}
+
+var knowledgeEmbeddings = &embeddingIndex{}
+
+// invalidate drops the in-memory vectors; they are reloaded on the next search
+func (idx *embeddingIndex) invalidate() {
+	idx.mutex.Lock()
+	idx.loaded = false
+	idx.chunkIDs = nil
+	idx.vectors = nil
+	idx.mutex.Unlock()
+}
+
+// load reads the vectors embedded with model from the database, unless already loaded
+func (idx *embeddingIndex) load(model string) error {
+	idx.mutex.RLock()
+	ready := idx.loaded && idx.model == model
+	idx.mutex.RUnlock()
+	if ready {
+		return nil
+	}
+
+	var chunks []KnowledgeChunk
+	result := DB.Select("id", "embedding").
+		Where("embedding_model = ? AND embedding IS NOT NULL", model).
+		Find(&chunks)
+	if result.Error != nil {
+		log.Printf("Failed to load knowledge chunk embeddings: %v", result.Error)
+		return result.Error
+	}
+
+	chunkIDs := make([]uint, 0, len(chunks))
+	vectors := make([][]float32, 0, len(chunks))
+	for _, chunk := range chunks {
+		chunkIDs = append(chunkIDs, chunk.ID)
+		vectors = append(vectors, normalize(decodeEmbedding(chunk.Embedding)))
+	}
+
+	idx.mutex.Lock()
+	idx.loaded = true
+	idx.model = model
+	idx.chunkIDs = chunkIDs
+	idx.vectors = vectors
+	idx.mutex.Unlock()
+	return nil
+}
+
+// scoredChunk is a search hit; scoredChunks is a min-heap used to keep the best k
+type scoredChunk struct {
+	chunkID uint
+	score   float64
+}
+
+type scoredChunks []scoredChunk
+
+func (h scoredChunks) Len() int            { return len(h) }
+func (h scoredChunks) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h scoredChunks) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *scoredChunks) Push(x interface{}) { *h = append(*h, x.(scoredChunk)) }
+func (h *scoredChunks) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// size returns the number of vectors loaded
+func (idx *embeddingIndex) size() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return len(idx.vectors)
+}
+
+// search returns the k chunks whose vectors are most similar to query, best first
+func (idx *embeddingIndex) search(query []float32, k int) []scoredChunk {
+	query = normalize(query)
+
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	best := make(scoredChunks, 0, k+1)
+	for i, vector := range idx.vectors {
+		if len(vector) != len(query) {
+			continue
+		}
+		var dot float32
+		for j := range vector {
+			dot += vector[j] * query[j]
+		}
+		score := float64(dot)
+		if len(best) < k {
+			heap.Push(&best, scoredChunk{chunkID: idx.chunkIDs[i], score: score})
+		} else if score > best[0].score {
+			best[0] = scoredChunk{chunkID: idx.chunkIDs[i], score: score}
+			heap.Fix(&best, 0)
+		}
+	}
+
+	results := make([]scoredChunk, len(best))
+	for i := len(best) - 1; i >= 0; i-- {
+		results[i] = heap.Pop(&best).(scoredChunk)
+	}
+	return results
+}
+
+// embeddingMutex makes sure only one embedding pass runs at a time
+var embeddingMutex sync.Mutex
+
+// EmbedKnowledgeChunks embeds every chunk that has no embedding for the configured model,
+// which also re-embeds everything after the model setting changes
+func EmbedKnowledgeChunks(ctx context.Context) (int, error) {
+	embeddingMutex.Lock()
+	defer embeddingMutex.Unlock()
+
+	model := GetSetting(SettingEmbeddingModel)
+	client := NewOllamaClient(GetSetting(SettingOllamaURL))
+	embedded := 0
+	defer func() {
+		if embedded > 0 {
+			knowledgeEmbeddings.invalidate()
+		}
+	}()
+
+	for {
+		var chunks []KnowledgeChunk
+		result := DB.Select("id", "text").
+			Where("embedding IS NULL OR embedding_model <> ?", model).
+			Order("id ASC").Limit(embeddingBatchSize).
+			Find(&chunks)
+		if result.Error != nil {
+			log.Printf("Failed to get chunks to embed: %v", result.Error)
+			return embedded, result.Error
+		}
+		if len(chunks) == 0 {
+			return embedded, nil
+		}
+
+		inputs := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			inputs[i] = chunk.Text
+		}
+		vectors, err := client.Embed(ctx, model, inputs)
+		if err != nil {
+			return embedded, err
+		}
+
+		for i, chunk := range chunks {
+			err := DB.Model(&KnowledgeChunk{}).Where("id = ?", chunk.ID).Updates(map[string]interface{}{
+				"embedding":       encodeEmbedding(vectors[i]),
+				"embedding_model": model,
+			}).Error
+			if err != nil {
+				log.Printf("Failed to store embedding of chunk %d: %v", chunk.ID, err)
+				return embedded, err
+			}
+		}
+		embedded += len(chunks)
+	}
+}
+
+// embedKnowledgeChunksAsync embeds pending chunks in the background and reports progress to the frontend
+func (a *App) embedKnowledgeChunksAsync() {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		embedded, err := EmbedKnowledgeChunks(ctx)
+		if err != nil {
+			log.Printf("Failed to embed knowledge base chunks: %v", err)
+		}
+		if embedded > 0 {
+			log.Printf("Embedded %d knowledge base chunks", embedded)
+		}
+		if a.ctx != nil {
+			event := map[string]interface{}{
+				"embedded": embedded,
+				"model":    GetSetting(SettingEmbeddingModel),
+			}
+			if err != nil {
+				event["error"] = err.Error()
+			}
+			runtime.EventsEmit(a.ctx, "knowledgeBaseEmbedded", event)
+		}
+	}()
+}
+
+// semanticRetrieve returns the k chunks closest in meaning to query, best first.
+// It returns nothing when no chunk has been embedded with the configured model.
+func semanticRetrieve(ctx context.Context, query string, k int) ([]RetrievedChunk, error) {
+	if strings.TrimSpace(query) == "" || k <= 0 {
+		return nil, nil
+	}
+
+	model := GetSetting(SettingEmbeddingModel)
+	if err := knowledgeEmbeddings.load(model); err != nil {
+		return nil, err
+	}
+	if knowledgeEmbeddings.size() == 0 {
+		return nil, nil
+	}
+
+	vectors, err := NewOllamaClient(GetSetting(SettingOllamaURL)).Embed(ctx, model, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := knowledgeEmbeddings.search(vectors[0], k)
+	items := make(map[uint]KnowledgeBase)
+	retrieved := make([]RetrievedChunk, 0, len(hits))
+	for _, hit := range hits {
+		var chunk KnowledgeChunk
+		result := DB.Omit("embedding").Where("id = ?", hit.chunkID).Limit(1).Find(&chunk)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue // re-indexed since the vectors were loaded
+		}
+		item, ok := items[chunk.KnowledgeBaseID]
+		if !ok {
+			found, err := GetKnowledgeBaseItemByID(chunk.KnowledgeBaseID)
+			if err != nil {
+				continue
+			}
+			item = *found
+			items[item.ID] = item
+		}
+		retrieved = append(retrieved, RetrievedChunk{Chunk: chunk, Item: item, Score: hit.score})
+	}
+	return retrieved, nil
+}
+
+// SemanticSearch returns the k chunks closest in meaning to query
+func SemanticSearch(ctx context.Context, query string, k int) ([]KnowledgeSearchResult, error) {
+	retrieved, err := semanticRetrieve(ctx, query, k)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]KnowledgeSearchResult, 0, len(retrieved))
+	for _, hit := range retrieved {
+		results = append(results, KnowledgeSearchResult{
+			ChunkID:         hit.Chunk.ID,
+			KnowledgeBaseID: hit.Item.ID,
+			FileName:        knowledgeSourceName(hit.Item),
+			Ordinal:         hit.Chunk.Ordinal,
+			Offset:          hit.Chunk.Offset,
+			Text:            hit.Chunk.Text,
+			Score:           hit.Score,
+		})
+	}
+	return results, nil
+}
+
+// SemanticSearchKnowledgeBase finds the k knowledge base chunks closest in meaning to query
+func (a *App) SemanticSearchKnowledgeBase(query string, k int) ([]KnowledgeSearchResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return SemanticSearch(ctx, query, k)
+}
+
+// EmbedKnowledgeBase embeds the chunks that have no embedding for the configured model yet
+func (a *App) EmbedKnowledgeBase() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	return EmbedKnowledgeChunks(ctx)
+}
@@ -1,6 +1,7 @@
 // Modal components
 export { default as DocumentPreviewModal } from './modals/DocumentPreviewModal.jsx';
 export { default as SystemCheckModal } from './modals/SystemCheckModal.jsx';
+export { default as ActionItemsModal } from './modals/ActionItemsModal.jsx';
 
 // Page components
 export { default as HomePage } from './pages/HomePage.jsx';
@@ -18,3 +19,4 @@ export { default as HeaderBar } from './shared/HeaderBar.jsx';
 export { default as NoVNC } from './shared/NoVNC.jsx';
 export { default as TranscriptScreen } from './shared/TranscriptScreen.jsx';
 export { default as PDFViewer } from './shared/PDFViewer.jsx';
+export { default as ActionItemsPanel } from './shared/ActionItemsPanel.jsx';
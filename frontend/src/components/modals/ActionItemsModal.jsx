@@ -0,0 +1,124 @@
+import React, { useState, useEffect } from 'react';
+import {
+    Button,
+    Dialog,
+    DialogTitle,
+    DialogContent,
+    DialogActions,
+    Typography
+} from '@mui/material';
+import { EventsOn } from '../../../wailsjs/runtime/runtime';
+import { ListOpenActionItems, UpdateActionItem } from '../../../wailsjs/go/main/App';
+
+const rowStyle = {
+    display: 'flex',
+    alignItems: 'center',
+    justifyContent: 'space-between',
+    padding: '8px 12px',
+    borderRadius: 6,
+    background: 'rgba(255,255,255,0.04)',
+    marginBottom: 6
+};
+
+function ActionItemsModal({ open, onClose, onOpenWorkspace }) {
+    const [items, setItems] = useState([]);
+
+    const load = async () => {
+        try {
+            const data = await ListOpenActionItems();
+            setItems(data || []);
+        } catch (error) {
+            console.error('Error loading open action items:', error);
+        }
+    };
+
+    useEffect(() => {
+        if (!open) return;
+        load();
+
+        // Meetings still running may add or resolve items while the list is open
+        const unsubscribeMentioned = EventsOn("actionItemsMentioned", load);
+        const unsubscribeExtracted = EventsOn("meetingItemsExtracted", load);
+        return () => {
+            unsubscribeMentioned();
+            unsubscribeExtracted();
+        };
+    }, [open]);
+
+    const handleDone = async (item) => {
+        try {
+            await UpdateActionItem(item.id, item.text, item.owner, item.dueDate, 'done');
+            await load();
+        } catch (error) {
+            console.error('Error updating action item:', error);
+        }
+    };
+
+    return (
+        <Dialog
+            open={open}
+            onClose={onClose}
+            maxWidth="md"
+            fullWidth
+            PaperProps={{
+                sx: {
+                    background: 'linear-gradient(135deg, #23232f 0%, #2a2a3a 100%)',
+                    color: '#fff',
+                    border: '1px solid #444'
+                }
+            }}
+        >
+            <DialogTitle sx={{ color: '#ffd700', fontWeight: 600 }}>
+                Open Action Items
+            </DialogTitle>
+            <DialogContent>
+                {items.length === 0 ? (
+                    <Typography sx={{ color: '#888', fontSize: 14 }}>No open action items.</Typography>
+                ) : (
+                    items.map(item => (
+                        <div key={item.id} style={rowStyle}>
+                            <div>
+                                <div style={{ color: '#fff', fontWeight: 600 }}>{item.text}</div>
+                                <div style={{ color: '#888', fontSize: '0.8rem' }}>
+                                    {item.workspace?.title}
+                                    {item.owner && ` · ${item.owner}`}
+                                    {item.dueDate && ` · due ${new Date(item.dueDate).toLocaleDateString()}`}
+                                    {item.mentionedAt && ` · mentioned ${new Date(item.mentionedAt).toLocaleString()}`}
+                                </div>
+                            </div>
+                            <div style={{ display: 'flex', gap: 4 }}>
+                                <Button
+                                    size="small"
+                                    onClick={() => onOpenWorkspace(item.workspaceId)}
+                                    sx={{ color: '#ccc', textTransform: 'none' }}
+                                >
+                                    Open
+                                </Button>
+                                <Button
+                                    size="small"
+                                    onClick={() => handleDone(item)}
+                                    sx={{ color: '#ffd700', textTransform: 'none' }}
+                                >
+                                    Done
+                                </Button>
+                            </div>
+                        </div>
+                    ))
+                )}
+            </DialogContent>
+            <DialogActions sx={{ p: 3 }}>
+                <Button
+                    onClick={onClose}
+                    sx={{
+                        color: '#ccc',
+                        '&:hover': { backgroundColor: 'rgba(255,255,255,0.1)' }
+                    }}
+                >
+                    Close
+                </Button>
+            </DialogActions>
+        </Dialog>
+    );
+}
+
+export default ActionItemsModal;
@@ -0,0 +1,169 @@
+import React, { useState, useEffect } from 'react';
+import {
+    Button,
+    Dialog,
+    DialogTitle,
+    DialogContent,
+    DialogActions,
+    Typography
+} from '@mui/material';
+import {
+    ListNotesRevisions,
+    DiffNotesRevisions,
+    RestoreNotesRevision
+} from '../../../wailsjs/go/main/App';
+
+const diffColors = {
+    insert: { background: 'rgba(76, 175, 80, 0.15)', color: '#a5d6a7', sign: '+' },
+    delete: { background: 'rgba(244, 67, 54, 0.15)', color: '#ef9a9a', sign: '-' },
+    equal: { background: 'transparent', color: '#aaa', sign: ' ' }
+};
+
+function NotesRevisionsModal({ open, onClose, notesId, onRestored }) {
+    const [revisions, setRevisions] = useState([]);
+    const [selected, setSelected] = useState(null);
+    const [diff, setDiff] = useState(null);
+
+    useEffect(() => {
+        if (!open || !notesId) return;
+        (async () => {
+            try {
+                const data = await ListNotesRevisions(notesId);
+                setRevisions(data || []);
+                setSelected(data && data.length > 0 ? 0 : null);
+            } catch (error) {
+                console.error('Error loading notes revisions:', error);
+            }
+        })();
+    }, [open, notesId]);
+
+    // Show the selected revision against the one before it
+    useEffect(() => {
+        setDiff(null);
+        if (selected === null || !revisions[selected]) return;
+        const previous = revisions[selected + 1];
+        if (!previous) return;
+        (async () => {
+            try {
+                setDiff(await DiffNotesRevisions(previous.id, revisions[selected].id));
+            } catch (error) {
+                console.error('Error diffing notes revisions:', error);
+            }
+        })();
+    }, [selected, revisions]);
+
+    const handleRestore = async () => {
+        const revision = revisions[selected];
+        if (!revision) return;
+        try {
+            const notes = await RestoreNotesRevision(revision.id);
+            onRestored(notes);
+            onClose();
+        } catch (error) {
+            console.error('Error restoring notes revision:', error);
+        }
+    };
+
+    const current = selected !== null ? revisions[selected] : null;
+
+    return (
+        <Dialog
+            open={open}
+            onClose={onClose}
+            maxWidth="md"
+            fullWidth
+            PaperProps={{
+                sx: {
+                    background: 'linear-gradient(135deg, #23232f 0%, #2a2a3a 100%)',
+                    color: '#fff',
+                    border: '1px solid #444'
+                }
+            }}
+        >
+            <DialogTitle sx={{ color: '#ffd700', fontWeight: 600 }}>
+                Notes History
+            </DialogTitle>
+            <DialogContent sx={{ display: 'flex', gap: 2, minHeight: 360 }}>
+                <div style={{ width: 220, flexShrink: 0, overflowY: 'auto', maxHeight: 480 }}>
+                    {revisions.length === 0 && (
+                        <Typography sx={{ color: '#888', fontSize: 14 }}>No revisions yet.</Typography>
+                    )}
+                    {revisions.map((revision, index) => (
+                        <div
+                            key={revision.id}
+                            onClick={() => setSelected(index)}
+                            style={{
+                                padding: '8px 10px',
+                                borderRadius: 6,
+                                marginBottom: 6,
+                                cursor: 'pointer',
+                                background: index === selected ? 'rgba(255, 215, 0, 0.12)' : 'rgba(255,255,255,0.04)',
+                                border: index === selected ? '1px solid rgba(255, 215, 0, 0.4)' : '1px solid transparent'
+                            }}
+                        >
+                            <div style={{ color: '#fff', fontSize: '0.85rem', fontWeight: 600 }}>
+                                {new Date(revision.updatedAt).toLocaleString()}
+                            </div>
+                            <div style={{ color: revision.author === 'agent' ? '#4caf50' : '#888', fontSize: '0.75rem' }}>
+                                {revision.author === 'agent' ? 'Markdown agent' : 'You'}
+                                {index === 0 ? ' · current' : ''}
+                            </div>
+                        </div>
+                    ))}
+                </div>
+                <div style={{
+                    flex: 1,
+                    overflow: 'auto',
+                    maxHeight: 480,
+                    background: '#1a1a1a',
+                    borderRadius: 6,
+                    padding: 8,
+                    fontFamily: 'Consolas, Menlo, monospace',
+                    fontSize: 12
+                }}>
+                    {current && !diff && (
+                        <pre style={{ margin: 0, color: '#ccc', whiteSpace: 'pre-wrap' }}>{current.text}</pre>
+                    )}
+                    {diff && (
+                        <>
+                            <div style={{ color: '#888', marginBottom: 8 }}>
+                                +{diff.added} / -{diff.removed} lines since the previous revision
+                            </div>
+                            {diff.lines.map((line, index) => {
+                                const style = diffColors[line.op] || diffColors.equal;
+                                return (
+                                    <div
+                                        key={index}
+                                        style={{ background: style.background, color: style.color, whiteSpace: 'pre-wrap' }}
+                                    >
+                                        {style.sign} {line.text}
+                                    </div>
+                                );
+                            })}
+                        </>
+                    )}
+                </div>
+            </DialogContent>
+            <DialogActions sx={{ p: 3 }}>
+                <Button
+                    onClick={handleRestore}
+                    disabled={!current || selected === 0}
+                    sx={{ color: '#ffd700', mr: 'auto' }}
+                >
+                    Restore This Version
+                </Button>
+                <Button
+                    onClick={onClose}
+                    sx={{
+                        color: '#ccc',
+                        '&:hover': { backgroundColor: 'rgba(255,255,255,0.1)' }
+                    }}
+                >
+                    Close
+                </Button>
+            </DialogActions>
+        </Dialog>
+    );
+}
+
+export default NotesRevisionsModal;
@@ -0,0 +1,154 @@
+import React, { useState, useEffect } from 'react';
+import {
+    Button,
+    Dialog,
+    DialogTitle,
+    DialogContent,
+    DialogActions,
+    Typography
+} from '@mui/material';
+import {
+    ListTrash,
+    RestoreWorkspace,
+    PurgeTrash,
+    GetArchivedWorkspaces,
+    UnarchiveWorkspace
+} from '../../../wailsjs/go/main/App';
+
+const rowStyle = {
+    display: 'flex',
+    alignItems: 'center',
+    justifyContent: 'space-between',
+    padding: '8px 12px',
+    borderRadius: 6,
+    background: 'rgba(255,255,255,0.04)',
+    marginBottom: 6
+};
+
+function WorkspaceTrashModal({ open, onClose, onWorkspacesChanged }) {
+    const [trash, setTrash] = useState([]);
+    const [archived, setArchived] = useState([]);
+    const [confirmPurge, setConfirmPurge] = useState(false);
+
+    const load = async () => {
+        try {
+            const [trashData, archivedData] = await Promise.all([ListTrash(), GetArchivedWorkspaces()]);
+            setTrash(trashData || []);
+            setArchived(archivedData || []);
+        } catch (error) {
+            console.error('Error loading trash and archive:', error);
+        }
+    };
+
+    useEffect(() => {
+        if (open) {
+            setConfirmPurge(false);
+            load();
+        }
+    }, [open]);
+
+    const handleRestore = async (id) => {
+        try {
+            await RestoreWorkspace(id);
+            await load();
+            onWorkspacesChanged();
+        } catch (error) {
+            console.error('Error restoring workspace:', error);
+        }
+    };
+
+    const handleUnarchive = async (id) => {
+        try {
+            await UnarchiveWorkspace(id);
+            await load();
+            onWorkspacesChanged();
+        } catch (error) {
+            console.error('Error unarchiving workspace:', error);
+        }
+    };
+
+    const handlePurge = async () => {
+        try {
+            await PurgeTrash(0);
+            await load();
+        } catch (error) {
+            console.error('Error emptying trash:', error);
+        } finally {
+            setConfirmPurge(false);
+        }
+    };
+
+    const renderList = (items, emptyText, actionLabel, onAction, dateOf) => (
+        items.length === 0 ? (
+            <Typography sx={{ color: '#888', fontSize: 14, mb: 2 }}>{emptyText}</Typography>
+        ) : (
+            <div style={{ marginBottom: 16 }}>
+                {items.map(workspace => (
+                    <div key={workspace.id} style={rowStyle}>
+                        <div>
+                            <div style={{ color: '#fff', fontWeight: 600 }}>{workspace.title}</div>
+                            <div style={{ color: '#888', fontSize: '0.8rem' }}>
+                                {new Date(dateOf(workspace)).toLocaleString()}
+                            </div>
+                        </div>
+                        <Button
+                            size="small"
+                            onClick={() => onAction(workspace.id)}
+                            sx={{ color: '#ffd700', textTransform: 'none' }}
+                        >
+                            {actionLabel}
+                        </Button>
+                    </div>
+                ))}
+            </div>
+        )
+    );
+
+    return (
+        <Dialog
+            open={open}
+            onClose={onClose}
+            maxWidth="sm"
+            fullWidth
+            PaperProps={{
+                sx: {
+                    background: 'linear-gradient(135deg, #23232f 0%, #2a2a3a 100%)',
+                    color: '#fff',
+                    border: '1px solid #444'
+                }
+            }}
+        >
+            <DialogTitle sx={{ color: '#ffd700', fontWeight: 600 }}>
+                Trash & Archive
+            </DialogTitle>
+            <DialogContent>
+                <Typography sx={{ color: '#ccc', fontWeight: 600, mb: 1 }}>Archived</Typography>
+                {renderList(archived, 'No archived workspaces.', 'Unarchive', handleUnarchive, w => w.archivedAt)}
+
+                <Typography sx={{ color: '#ccc', fontWeight: 600, mb: 1 }}>Trash</Typography>
+                {renderList(trash, 'The trash is empty.', 'Restore', handleRestore, w => w.deletedAt)}
+            </DialogContent>
+            <DialogActions sx={{ p: 3 }}>
+                {trash.length > 0 && (
+                    <Button
+                        onClick={confirmPurge ? handlePurge : () => setConfirmPurge(true)}
+                        sx={{ color: '#ff5252', mr: 'auto' }}
+                    >
+                        {confirmPurge ? 'Permanently delete everything in the trash?' : 'Empty Trash'}
+                    </Button>
+                )}
+                <Button
+                    onClick={onClose}
+                    sx={{
+                        color: '#ccc',
+                        '&:hover': { backgroundColor: 'rgba(255,255,255,0.1)' }
+                    }}
+                >
+                    Close
+                </Button>
+            </DialogActions>
+        </Dialog>
+    );
+}
+
+export default WorkspaceTrashModal;
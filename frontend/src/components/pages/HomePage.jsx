@@ -11,10 +11,12 @@ import {
     Menu,
     MenuItem
 } from '@mui/material';
-import { Add as AddIcon, Delete as DeleteIcon } from '@mui/icons-material';
+import { Add as AddIcon, Delete as DeleteIcon, Archive as ArchiveIcon, RestoreFromTrash as RestoreFromTrashIcon, FileDownload as FileDownloadIcon, FileUpload as FileUploadIcon, Checklist as ChecklistIcon } from '@mui/icons-material';
 import logo from '../../assets/images/logo-universal.png';
 import SystemCheckModal from '../modals/SystemCheckModal';
 import DocumentPreviewModal from '../modals/DocumentPreviewModal';
+import WorkspaceTrashModal from '../modals/WorkspaceTrashModal';
+import ActionItemsModal from '../modals/ActionItemsModal';
 import KnowledgeBaseSection from '../sections/KnowledgeBaseSection';
 import WorkspacesSection from '../sections/WorkspacesSection';
 import QuickStatsSection from '../sections/QuickStatsSection';
@@ -24,6 +26,9 @@ import {
     GetAllWorkspaces, 
     UpdateWorkspaceLastOpen, 
     DeleteWorkspace,
+    ArchiveWorkspace,
+    ExportWorkspace,
+    ImportWorkspace,
     CreateKnowledgeBaseItem,
     GetAllKnowledgeBaseItems,
     GetKnowledgeBaseItemByID,
@@ -43,6 +48,9 @@ function HomePage() {
     const [workspaceToDelete, setWorkspaceToDelete] = useState(null);
     const [menuAnchor, setMenuAnchor] = useState(null);
     const [selectedWorkspace, setSelectedWorkspace] = useState(null);
+    const [showTrashModal, setShowTrashModal] = useState(false);
+    const [showActionItems, setShowActionItems] = useState(false);
+    const [importReport, setImportReport] = useState(null);
     
     // Form states
     const [newWorkspaceTitle, setNewWorkspaceTitle] = useState('');
@@ -190,6 +198,44 @@ function HomePage() {
         handleMenuClose();
     };
 
+    const handleArchiveClick = async () => {
+        const workspace = selectedWorkspace;
+        handleMenuClose();
+        if (!workspace) return;
+
+        try {
+            await ArchiveWorkspace(workspace.id);
+            await loadWorkspaces();
+        } catch (error) {
+            console.error('Error archiving workspace:', error);
+        }
+    };
+
+    const handleExportClick = async () => {
+        const workspace = selectedWorkspace;
+        handleMenuClose();
+        if (!workspace) return;
+
+        try {
+            const savedTo = await ExportWorkspace(workspace.id, '');
+            if (savedTo) console.log('📦 Workspace exported to', savedTo);
+        } catch (error) {
+            console.error('Error exporting workspace:', error);
+        }
+    };
+
+    const handleImportWorkspace = async () => {
+        try {
+            const report = await ImportWorkspace('');
+            if (!report) return; // dialog cancelled
+            await Promise.all([loadWorkspaces(), loadKnowledgeBase()]);
+            setImportReport(report);
+        } catch (error) {
+            console.error('Error importing workspace:', error);
+            setImportReport({ error: String(error) });
+        }
+    };
+
     const handleDeleteConfirm = async () => {
         if (!workspaceToDelete) return;
         
@@ -293,6 +339,39 @@ function HomePage() {
                     </div>
                 </div>
                 <div style={{ display: 'flex', alignItems: 'center', gap: 12 }}>
+                    <Button
+                        startIcon={<FileUploadIcon />}
+                        onClick={handleImportWorkspace}
+                        sx={{
+                            color: '#ccc',
+                            textTransform: 'none',
+                            '&:hover': { backgroundColor: 'rgba(255,255,255,0.1)' }
+                        }}
+                    >
+                        Import
+                    </Button>
+                    <Button
+                        startIcon={<ChecklistIcon />}
+                        onClick={() => setShowActionItems(true)}
+                        sx={{
+                            color: '#ccc',
+                            textTransform: 'none',
+                            '&:hover': { backgroundColor: 'rgba(255,255,255,0.1)' }
+                        }}
+                    >
+                        Action Items
+                    </Button>
+                    <Button
+                        startIcon={<RestoreFromTrashIcon />}
+                        onClick={() => setShowTrashModal(true)}
+                        sx={{
+                            color: '#ccc',
+                            textTransform: 'none',
+                            '&:hover': { backgroundColor: 'rgba(255,255,255,0.1)' }
+                        }}
+                    >
+                        Trash & Archive
+                    </Button>
                     <Button
                         variant="contained"
                         startIcon={<AddIcon />}
@@ -338,6 +417,8 @@ function HomePage() {
                     onMenuOpen={handleMenuOpen}
                     onMenuClose={handleMenuClose}
                     onDeleteClick={handleDeleteClick}
+                    onArchiveClick={handleArchiveClick}
+                    onExportClick={handleExportClick}
                 />
 
                 {/* Knowledge Base Section */}
@@ -463,12 +544,12 @@ function HomePage() {
                 }}
             >
                 <DialogTitle sx={{ color: '#ff5252', fontWeight: 600 }}>
-                    Delete Workspace
+                    Move to Trash
                 </DialogTitle>
                 <DialogContent>
                     <Typography>
-                        Are you sure you want to delete "{workspaceToDelete?.title}"? 
-                        This action cannot be undone.
+                        Move "{workspaceToDelete?.title}" to the trash? 
+                        You can restore it from Trash & Archive until the trash is emptied.
                     </Typography>
                 </DialogContent>
                 <DialogActions sx={{ p: 3 }}>
@@ -493,7 +574,7 @@ function HomePage() {
                             }
                         }}
                     >
-                        Delete
+                        Move to Trash
                     </Button>
                 </DialogActions>
             </Dialog>
@@ -505,6 +586,63 @@ function HomePage() {
                 onDocumentSaved={handleDocumentSaved}
             />
 
+            {/* Import Result Dialog */}
+            <Dialog
+                open={Boolean(importReport)}
+                onClose={() => setImportReport(null)}
+                PaperProps={{
+                    sx: {
+                        background: 'linear-gradient(135deg, #23232f 0%, #2a2a3a 100%)',
+                        color: '#fff',
+                        border: '1px solid #444'
+                    }
+                }}
+            >
+                <DialogTitle sx={{ color: importReport?.error ? '#ff5252' : '#ffd700', fontWeight: 600 }}>
+                    {importReport?.error ? 'Import Failed' : 'Workspace Imported'}
+                </DialogTitle>
+                <DialogContent>
+                    {importReport?.error ? (
+                        <Typography>{importReport.error}</Typography>
+                    ) : importReport && (
+                        <>
+                            <Typography sx={{ mb: 1 }}>
+                                "{importReport.workspace?.title}": {importReport.transcripts} transcript lines,{' '}
+                                {importReport.chatMessages} chat messages, {importReport.sessions} sessions
+                                {importReport.hasNotes ? ', meeting notes' : ''}.
+                            </Typography>
+                            <Typography sx={{ mb: 1, color: '#ccc' }}>
+                                Knowledge base: {importReport.knowledgeImported} added, {importReport.knowledgeDeduplicated} already present.
+                            </Typography>
+                            {importReport.conflicts?.map((conflict, index) => (
+                                <Typography key={index} sx={{ color: '#ffb74d', fontSize: 14 }}>
+                                    ⚠ {conflict}
+                                </Typography>
+                            ))}
+                        </>
+                    )}
+                </DialogContent>
+                <DialogActions sx={{ p: 3 }}>
+                    <Button onClick={() => setImportReport(null)} sx={{ color: '#ccc' }}>
+                        Close
+                    </Button>
+                </DialogActions>
+            </Dialog>
+
+            {/* Trash & Archive Modal */}
+            <WorkspaceTrashModal
+                open={showTrashModal}
+                onClose={() => setShowTrashModal(false)}
+                onWorkspacesChanged={loadWorkspaces}
+            />
+
+            {/* Open Action Items Modal */}
+            <ActionItemsModal
+                open={showActionItems}
+                onClose={() => setShowActionItems(false)}
+                onOpenWorkspace={handleWorkspaceClick}
+            />
+
             {/* Workspace Menu */}
             <Menu
                 anchorEl={menuAnchor}
@@ -523,9 +661,17 @@ function HomePage() {
                     }
                 }}
             >
+                <MenuItem onClick={handleExportClick}>
+                    <FileDownloadIcon fontSize="small" sx={{ mr: 1, color: '#ccc' }} />
+                    Export Workspace
+                </MenuItem>
+                <MenuItem onClick={handleArchiveClick}>
+                    <ArchiveIcon fontSize="small" sx={{ mr: 1, color: '#ccc' }} />
+                    Archive Workspace
+                </MenuItem>
                 <MenuItem onClick={handleDeleteClick}>
                     <DeleteIcon fontSize="small" sx={{ mr: 1, color: '#ff5252' }} />
-                    Delete Workspace
+                    Move to Trash
                 </MenuItem>
             </Menu>
         </div>
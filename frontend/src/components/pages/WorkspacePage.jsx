@@ -7,6 +7,7 @@ import HeaderBar from '../shared/HeaderBar';
 import TranscriptScreen from '../shared/TranscriptScreen';
 import PromptSection from '../sections/PromptSection';
 import NotesSection from '../sections/NotesSection';
+import ActionItemsPanel from '../shared/ActionItemsPanel';
 import NoVNC from '../shared/NoVNC';
 import PDFViewer from '../shared/PDFViewer';
 
@@ -342,6 +343,7 @@ function WorkspacePage() {
                             overflow: 'hidden',
                             borderLeft: '2px solid #333'
                         }}>
+                            <ActionItemsPanel workspaceId={parseInt(workspaceId)} />
                             <NotesSection isRecording={isRecording} />
                         </div>
 
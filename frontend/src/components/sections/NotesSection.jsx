@@ -3,9 +3,11 @@ import { marked } from 'marked';
 import EditIcon from '@mui/icons-material/Edit';
 import VisibilityIcon from '@mui/icons-material/Visibility';
 import AutorenewIcon from '@mui/icons-material/Autorenew';
-import { InitializeMarkdownAgentWebSocket, SendMeetingNotesRequest, CloseMarkdownAgentWebSocket, IsMarkdownAgentWebSocketConnected, GetTranscriptionMessagesByDateRange, CreateMeetingNotes, GetMeetingNotesByID, GetMeetingNotesByWorkspace, UpdateMeetingNotes, DeleteMeetingNotes, DeleteMeetingNotesByWorkspace, SearchMeetingNotes } from '../../../wailsjs/go/main/App';
+import { InitializeMarkdownAgentWebSocket, SendMeetingNotesRequest, CancelMarkdownAgent, CloseMarkdownAgentWebSocket, IsMarkdownAgentWebSocketConnected, GetTranscriptionMessagesByDateRange, CreateMeetingNotes, GetMeetingNotesByID, GetMeetingNotesByWorkspace, UpdateMeetingNotes, DeleteMeetingNotes, DeleteMeetingNotesByWorkspace, SearchMeetingNotes, ExportMeetingNotes, GetPendingNotesPatch, AcceptNotesPatch, RejectNotesPatch } from '../../../wailsjs/go/main/App';
 import { EventsOn } from '../../../wailsjs/runtime/runtime';
 import { useParams } from 'react-router-dom';
+import NotesRevisionsModal from '../modals/NotesRevisionsModal';
+import NotesPatchReview from '../shared/NotesPatchReview';
 
 function NotesSection({ isRecording }) {
     const { workspaceId } = useParams();
@@ -16,11 +18,16 @@ function NotesSection({ isRecording }) {
     const [isUpdatingNotes, setIsUpdatingNotes] = useState(false);
     const [lastUpdateTime, setLastUpdateTime] = useState(null);
     const [nextUpdateCountdown, setNextUpdateCountdown] = useState(null);
+    const [exportFormat, setExportFormat] = useState('pdf');
+    const [showHistory, setShowHistory] = useState(false);
+    const [pendingPatch, setPendingPatch] = useState(null); // Changes proposed by the markdown agent
     const intervalRef = useRef(null);
     const countdownRef = useRef(null);
     const streamContentRef = useRef("");
+    const requestIdRef = useRef(null); // Request ID of the notes generation in progress
     const sentTranscriptIdsRef = useRef(new Set());
     const sentTranscriptMapRef = useRef(new Map()); // id -> last_modified
+    const meetingNoteIdRef = useRef(null); // Current meetingNoteId for the streaming event handlers
     const [streamingRef, setStreamingRef] = useState(false);
 
     // Load or create meeting note on mount/workspace change
@@ -54,20 +61,69 @@ function NotesSection({ isRecording }) {
         return () => { isMounted = false; };
     }, [workspaceId]);
 
-    // Update meeting note in DB after AI generation
+    // Save edits to the DB, also while the agent is generating so its changes merge against them
     useEffect(() => {
         if (!meetingNoteId) return;
         if (!note) return;
-        // Only update if not currently updating (avoid race with streaming)
-        if (isUpdatingNotes) return;
         (async () => {
             try {
-                await UpdateMeetingNotes(parseInt(workspaceId), note );
+                await UpdateMeetingNotes(meetingNoteId, note);
             } catch (err) {
                 console.error("Failed to update meeting note:", err);
             }
         })();
-    }, [note, meetingNoteId, isUpdatingNotes]);
+    }, [note, meetingNoteId]);
+
+    useEffect(() => {
+        meetingNoteIdRef.current = meetingNoteId;
+        if (!meetingNoteId) return;
+        GetPendingNotesPatch(meetingNoteId)
+            .then(patch => setPendingPatch(patch && patch.hunks.length > 0 ? patch : null))
+            .catch(err => console.error("Failed to load pending notes changes:", err));
+    }, [meetingNoteId]);
+
+    // The agent's output arrives as a patch to review, also after recording stopped
+    useEffect(() => {
+        const unsubscribeProposedPatch = EventsOn("markdownAgentProposedPatch", (data) => {
+            if (data.notesId !== meetingNoteIdRef.current) return;
+            console.log("📝 Markdown agent proposed", data.patch.hunks.length, "changes");
+            setPendingPatch(data.patch.hunks.length > 0 ? data.patch : null);
+        });
+        // The live note-taker of a session saves its changes itself
+        const unsubscribeLiveNotes = EventsOn("liveNotesUpdated", (data) => {
+            if (data.notesId !== meetingNoteIdRef.current || data.applied === 0) return;
+            console.log("📝 Live notes applied", data.applied, "changes");
+            setNote(data.notes.text || "");
+            setLastUpdateTime(new Date());
+        });
+        return () => {
+            unsubscribeProposedPatch();
+            unsubscribeLiveNotes();
+        };
+    }, []);
+
+    const handleAcceptPatch = async (hunkIndexes) => {
+        if (!pendingPatch) return;
+        try {
+            const updated = await AcceptNotesPatch(pendingPatch.id, hunkIndexes);
+            setNote(updated.text || "");
+        } catch (error) {
+            console.error("Failed to apply notes changes:", error);
+        } finally {
+            setPendingPatch(null);
+        }
+    };
+
+    const handleRejectPatch = async () => {
+        if (!pendingPatch) return;
+        try {
+            await RejectNotesPatch(pendingPatch.id);
+        } catch (error) {
+            console.error("Failed to dismiss notes changes:", error);
+        } finally {
+            setPendingPatch(null);
+        }
+    };
 
     // Live notes update logic
     const updateLiveNotes = async () => {
@@ -117,7 +173,7 @@ function NotesSection({ isRecording }) {
                     await InitializeMarkdownAgentWebSocket();
                     console.log("✅ Markdown agent WebSocket initialized successfully");
                 }
-                await SendMeetingNotesRequest(transcriptionList, note);
+                requestIdRef.current = await SendMeetingNotesRequest(meetingNoteIdRef.current, transcriptionList, note);
                 console.log("✅ Meeting notes request sent successfully");
             } catch (markdownAgentError) {
                 console.error("❌ Markdown agent WebSocket failed:", markdownAgentError);
@@ -158,21 +214,12 @@ function NotesSection({ isRecording }) {
             console.log("📝 Streaming chunk received:", responseText);
             console.log("📝 Current content length:", streamContentRef.current.length);
             
-            // Update notes with streaming content
-            setNote(streamContentRef.current);
-            
             // Check if this is the final chunk
             if (data.done) {
                 console.log("✅ Markdown agent streaming completed (done flag)");
                 setIsUpdatingNotes(false);
                 setStreamingRef(false);
                 setLastUpdateTime(new Date());
-                // Save to DB
-                if (meetingNoteId) {
-                    UpdateMeetingNotes(parseInt(workspaceId), streamContentRef.current ).catch(err => {
-                        console.error("Failed to update meeting note after streaming:", err);
-                    });
-                }
                 // Restart countdown after generation is complete
                 startCountdownAfterGeneration();
             }
@@ -183,16 +230,7 @@ function NotesSection({ isRecording }) {
             setIsUpdatingNotes(false);
             setStreamingRef(false);
             setLastUpdateTime(new Date());
-            // Ensure final content is set
-            if (streamContentRef.current.trim()) {
-                setNote(streamContentRef.current);
-            }
-            // Save to DB
-            if (meetingNoteId) {
-                UpdateMeetingNotes(parseInt(workspaceId), streamContentRef.current).catch(err => {
-                    console.error("Failed to update meeting note after streaming:", err);
-                });
-            }
+            // The output is proposed as a patch through markdownAgentProposedPatch
             // Restart countdown after generation is complete
             startCountdownAfterGeneration();
         });
@@ -208,6 +246,15 @@ function NotesSection({ isRecording }) {
             setStreamingRef(false);
         });
 
+        const unsubscribeMarkdownCancelled = EventsOn("markdownAgentStreamCancelled", (data) => {
+            console.log("⏹️ Markdown agent generation cancelled:", data.requestId);
+            requestIdRef.current = null;
+            streamContentRef.current = "";
+            setIsUpdatingNotes(false);
+            setStreamingRef(false);
+            startCountdownAfterGeneration();
+        });
+
         const unsubscribeMarkdownDisconnected = EventsOn("markdownAgentWebSocketDisconnected", (data) => {
             console.warn("Markdown agent WebSocket disconnected:", data.message);
             // The system will automatically try to reconnect
@@ -220,6 +267,7 @@ function NotesSection({ isRecording }) {
             unsubscribeMarkdownDone();
             unsubscribeMarkdownInfo();
             unsubscribeMarkdownError();
+            unsubscribeMarkdownCancelled();
             unsubscribeMarkdownDisconnected();
         };
     }, [isRecording]);
@@ -298,13 +346,34 @@ function NotesSection({ isRecording }) {
         };
     }, []);
 
-    const handleNoteChange = (e) => {
-        // Only allow manual editing when not recording or not currently updating
-        if (!isRecording || !isUpdatingNotes) {
-            setNote(e.target.value);
+    const handleCancelUpdate = async () => {
+        if (!requestIdRef.current) return;
+        try {
+            await CancelMarkdownAgent(requestIdRef.current);
+        } catch (error) {
+            console.error("Failed to cancel notes update:", error);
         }
     };
 
+    const handleExportNotes = async () => {
+        if (!meetingNoteId) return;
+        try {
+            const savedTo = await ExportMeetingNotes(meetingNoteId, exportFormat, {
+                includeActionItems: true,
+                includeTranscript: true
+            });
+            if (savedTo) console.log('📝 Meeting notes exported to', savedTo);
+        } catch (error) {
+            console.error('Error exporting meeting notes:', error);
+            alert("Failed to export meeting notes.\n" + (error.message || error));
+        }
+    };
+
+    const handleNoteChange = (e) => {
+        // Editing stays possible while the agent generates, its output is merged as a patch
+        setNote(e.target.value);
+    };
+
     return (
         <div style={{ 
             width: '100%', 
@@ -356,59 +425,117 @@ function NotesSection({ isRecording }) {
                         />
                     )}
                 </h3>
-                <div style={{
-                    background: isRecording 
-                        ? (isUpdatingNotes 
-                            ? 'rgba(76, 175, 80, 0.2)' 
-                            : 'rgba(255, 215, 0, 0.1)')
-                        : 'rgba(255, 215, 0, 0.1)',
-                    border: isRecording 
-                        ? (isUpdatingNotes 
-                            ? '1px solid rgba(76, 175, 80, 0.5)' 
-                            : '1px solid rgba(255, 215, 0, 0.3)')
-                        : '1px solid rgba(255, 215, 0, 0.3)',
-                    borderRadius: 6,
-                    padding: '3px 6px',
-                    fontSize: 10,
-                    color: isRecording 
-                        ? (isUpdatingNotes ? '#4caf50' : '#ffd700')
-                        : '#ffd700',
-                    fontWeight: 600,
-                    textTransform: 'uppercase',
-                    letterSpacing: '0.5px',
-                    display: 'flex',
-                    alignItems: 'center',
-                    gap: 4,
-                    flexDirection: 'column'
-                }}>
-                    <div>
-                        {isRecording 
-                            ? (isUpdatingNotes ? 'Updating...' : 'Live Notes')
-                            : 'Manual Edit'
-                        }
-                    </div>
-                    {isRecording && nextUpdateCountdown !== null && !isUpdatingNotes && (
-                        <div style={{
-                            fontSize: 8,
-                            color: '#999',
-                            fontWeight: 400,
-                            textTransform: 'none',
-                            letterSpacing: 'normal'
-                        }}>
-                            Next update: {nextUpdateCountdown}s
-                        </div>
-                    )}
-                    {isRecording && isUpdatingNotes && (
-                        <div style={{
-                            fontSize: 8,
-                            color: '#4caf50',
-                            fontWeight: 400,
-                            textTransform: 'none',
-                            letterSpacing: 'normal'
-                        }}>
-                            Generating...
+                <div style={{ display: 'flex', alignItems: 'center', gap: 8 }}>
+                    <select
+                        value={exportFormat}
+                        onChange={(e) => setExportFormat(e.target.value)}
+                        title="Meeting notes export format"
+                        style={{
+                            background: '#20202a',
+                            color: '#ccc',
+                            border: '1px solid #333',
+                            borderRadius: 6,
+                            fontSize: 10,
+                            padding: '2px 4px'
+                        }}
+                    >
+                        <option value="pdf">PDF</option>
+                        <option value="docx">DOCX</option>
+                        <option value="html">HTML</option>
+                    </select>
+                    <button
+                        onClick={() => setShowHistory(true)}
+                        disabled={!meetingNoteId}
+                        title="Notes history"
+                        style={{
+                            background: 'none',
+                            border: '1px solid #333',
+                            borderRadius: 6,
+                            color: !meetingNoteId ? '#555' : '#ccc',
+                            fontSize: 10,
+                            fontWeight: 600,
+                            padding: '3px 6px',
+                            cursor: !meetingNoteId ? 'default' : 'pointer'
+                        }}
+                    >
+                        History
+                    </button>
+                    <button
+                        onClick={handleExportNotes}
+                        disabled={!note.trim()}
+                        style={{
+                            background: 'none',
+                            border: '1px solid #333',
+                            borderRadius: 6,
+                            color: !note.trim() ? '#555' : '#ffd700',
+                            fontSize: 10,
+                            fontWeight: 600,
+                            padding: '3px 6px',
+                            cursor: !note.trim() ? 'default' : 'pointer'
+                        }}
+                    >
+                        Export
+                    </button>
+                    <div style={{
+                        background: isRecording 
+                            ? (isUpdatingNotes 
+                                ? 'rgba(76, 175, 80, 0.2)' 
+                                : 'rgba(255, 215, 0, 0.1)')
+                            : 'rgba(255, 215, 0, 0.1)',
+                        border: isRecording 
+                            ? (isUpdatingNotes 
+                                ? '1px solid rgba(76, 175, 80, 0.5)' 
+                                : '1px solid rgba(255, 215, 0, 0.3)')
+                            : '1px solid rgba(255, 215, 0, 0.3)',
+                        borderRadius: 6,
+                        padding: '3px 6px',
+                        fontSize: 10,
+                        color: isRecording 
+                            ? (isUpdatingNotes ? '#4caf50' : '#ffd700')
+                            : '#ffd700',
+                        fontWeight: 600,
+                        textTransform: 'uppercase',
+                        letterSpacing: '0.5px',
+                        display: 'flex',
+                        alignItems: 'center',
+                        gap: 4,
+                        flexDirection: 'column'
+                    }}>
+                        <div>
+                            {isRecording 
+                                ? (isUpdatingNotes ? 'Updating...' : 'Live Notes')
+                                : 'Manual Edit'
+                            }
                         </div>
-                    )}
+                        {isRecording && nextUpdateCountdown !== null && !isUpdatingNotes && (
+                            <div style={{
+                                fontSize: 8,
+                                color: '#999',
+                                fontWeight: 400,
+                                textTransform: 'none',
+                                letterSpacing: 'normal'
+                            }}>
+                                Next update: {nextUpdateCountdown}s
+                            </div>
+                        )}
+                        {isRecording && isUpdatingNotes && (
+                            <div style={{
+                                fontSize: 8,
+                                color: '#4caf50',
+                                fontWeight: 400,
+                                textTransform: 'none',
+                                letterSpacing: 'normal'
+                            }}>
+                                Generating...{' '}
+                                <span
+                                    onClick={handleCancelUpdate}
+                                    style={{ color: '#f44336', cursor: 'pointer', textDecoration: 'underline' }}
+                                >
+                                    Stop
+                                </span>
+                            </div>
+                        )}
+                    </div>
                 </div>
             </div>
 
@@ -481,6 +608,14 @@ function NotesSection({ isRecording }) {
                 </button>
             </div>
 
+            {pendingPatch && (
+                <NotesPatchReview
+                    patch={pendingPatch}
+                    onAccept={handleAcceptPatch}
+                    onReject={handleRejectPatch}
+                />
+            )}
+
             {/* Content Area */}
             <div style={{ 
                 flex: 1, 
@@ -567,7 +702,6 @@ function NotesSection({ isRecording }) {
                             onFocus={() => setIsEditorFocused(true)}
                             onBlur={() => setIsEditorFocused(false)}
                             placeholder="AI Assistant will write notes here as the events get unfolded"
-                            readOnly={isRecording && isUpdatingNotes}
                             style={{
                                 flex: 1,
                                 width: '100%',
@@ -581,9 +715,7 @@ function NotesSection({ isRecording }) {
                                 fontSize: 13,
                                 lineHeight: 1.5,
                                 minHeight: 0,
-                                boxSizing: 'border-box',
-                                opacity: (isRecording && isUpdatingNotes) ? 0.7 : 1,
-                                cursor: (isRecording && isUpdatingNotes) ? 'not-allowed' : 'text'
+                                boxSizing: 'border-box'
                             }}
                         />
                     </div>
@@ -784,6 +916,13 @@ function NotesSection({ isRecording }) {
                     accent-color: #ffd700;
                 }
             `}</style>
+
+            <NotesRevisionsModal
+                open={showHistory}
+                onClose={() => setShowHistory(false)}
+                notesId={meetingNoteId}
+                onRestored={(notes) => setNote(notes.text || "")}
+            />
         </div>
     );
 }
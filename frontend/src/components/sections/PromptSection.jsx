@@ -1,7 +1,8 @@
 import React, { useState, useRef, useEffect } from 'react';
 import { TextField } from '@mui/material';
 import SendIcon from '@mui/icons-material/Send';
-import { SendChatMessage, CreateAIChatMessage, GetAIChatMessagesByWorkspace, UpdateAIChatMessage, DeleteAIChatMessage, InitializeWebSocketFrontend, CloseWebSocketFrontend } from '../../../wailsjs/go/main/App';
+import StopIcon from '@mui/icons-material/Stop';
+import { SendChatMessage, CancelChatGeneration, GetKnowledgeChunk, GetAIChatMessagesByWorkspace, DeleteAIChatMessage, InitializeWebSocketFrontend, CloseWebSocketFrontend } from '../../../wailsjs/go/main/App';
 import { EventsOn } from '../../../wailsjs/runtime/runtime';
 import { useParams } from 'react-router-dom';
 
@@ -14,7 +15,9 @@ function PromptSection() {
     const [isStreaming, setIsStreaming] = useState(false);
     const [currentStreamMessage, setCurrentStreamMessage] = useState("");
     const inputRef = useRef(null);
+    const requestIdRef = useRef(null); // Request ID of the generation being streamed
     const [messageIds, setMessageIds] = useState([]); // Track DB IDs for messages
+    const [openCitation, setOpenCitation] = useState(null); // { key, chunk } of the source being shown
 
     // Load chat history for workspace on mount/workspace change
     useEffect(() => {
@@ -25,7 +28,7 @@ function PromptSection() {
                 if (Array.isArray(dbMessages)) {
                     setMessages([
                         { role: 'system', content: 'How can I help you today?' },
-                        ...dbMessages.map(m => ({ role: m.by === 'user' ? 'user' : 'assistant', content: m.text }))
+                        ...dbMessages.map(m => ({ role: m.by === 'user' ? 'user' : 'assistant', content: m.text, citations: m.citations || [] }))
                     ]);
                     setMessageIds(dbMessages.map(m => m.id));
                 }
@@ -61,15 +64,6 @@ function PromptSection() {
         // Add user message to chat
         const userMessage = { role: 'user', content: prompt };
         setMessages(prevMessages => [...prevMessages, userMessage]);
-        // Save user message to DB
-        let userMsgId = null;
-        try {
-            const dbMsg = await CreateAIChatMessage(parseInt(workspaceId), 'user', prompt);
-            userMsgId = dbMsg?.id;
-            setMessageIds(prev => [...prev, userMsgId]);
-        } catch (err) {
-            console.error('Failed to save user message:', err);
-        }
 
         // Clear input and set streaming state
         const currentPrompt = prompt;
@@ -77,18 +71,9 @@ function PromptSection() {
         setIsStreaming(true);
         setCurrentStreamMessage("");
         setMessages(prevMessages => [...prevMessages, { role: 'assistant', content: '' }]);
-        // Save empty assistant message to DB
-        let assistantMsgId = null;
-        try {
-            const dbMsg = await CreateAIChatMessage(parseInt(workspaceId), 'assistant', '');
-            assistantMsgId = dbMsg?.id;
-            setMessageIds(prev => [...prev, assistantMsgId]);
-        } catch (err) {
-            console.error('Failed to save assistant message:', err);
-        }
         try {
-            // Send chat message to backend (now with workspaceId)
-            await SendChatMessage(parseInt(workspaceId), currentPrompt, "You are a helpful AI assistant.");
+            // Send chat message to backend, which stores both the question and the reply
+            requestIdRef.current = await SendChatMessage(parseInt(workspaceId), currentPrompt, "");
         } catch (error) {
             console.error("Failed to send chat message:", error);
             setIsStreaming(false);
@@ -101,14 +86,47 @@ function PromptSection() {
         }
     };
 
+    const handleCancel = async () => {
+        if (!requestIdRef.current) return;
+        try {
+            await CancelChatGeneration(requestIdRef.current);
+        } catch (err) {
+            console.error('Failed to cancel chat generation:', err);
+        }
+    };
+
+    // Show or hide the knowledge base excerpt a citation points to
+    const toggleCitation = async (key, citation) => {
+        if (openCitation?.key === key) {
+            setOpenCitation(null);
+            return;
+        }
+        try {
+            const chunk = await GetKnowledgeChunk(citation.chunkId);
+            setOpenCitation({ key, chunk, citation });
+        } catch (err) {
+            // The document may have been re-indexed since; fall back to the stored snippet
+            setOpenCitation({ key, chunk: { text: citation.snippet }, citation });
+        }
+    };
+
     useEffect(() => {
+        // Several generations can stream at once; only handle the ones for this chat
+        const isOwnEvent = (data) => {
+            if (data?.workspaceId && data.workspaceId !== parseInt(workspaceId)) return false;
+            if (requestIdRef.current && data?.requestId && data.requestId !== requestIdRef.current) return false;
+            return true;
+        };
+
         // Listen for streaming start
         const unsubscribeStart = EventsOn("chatStreamStart", (data) => {
+            if (!isOwnEvent(data)) return;
             console.log("Chat stream starting:", data.message);
         });
 
         // Listen for streaming chat chunks
-        const unsubscribeChunk = EventsOn("chatStreamChunk", async (data) => {
+        const unsubscribeChunk = EventsOn("chatStreamChunk", (data) => {
+            if (!isOwnEvent(data)) return;
             const responseText = data.token || "";
             setCurrentStreamMessage(prev => prev + responseText);
             setMessages(prevMessages => {
@@ -118,29 +136,52 @@ function PromptSection() {
                 }
                 return newMessages;
             });
-            // Update assistant message in DB
-            if (messageIds.length > 0) {
-                const lastAssistantId = messageIds[messageIds.length - 1];
-                try {
-                    await UpdateAIChatMessage(lastAssistantId, parseInt(workspaceId), 'assistant', currentStreamMessage + responseText);
-                } catch (err) {
-                    console.error('Failed to update assistant message:', err);
-                }
-            }
         });
 
         // Listen for stream completion
         const unsubscribeDone = EventsOn("chatStreamDone", (data) => {
+            if (!isOwnEvent(data)) return;
             setIsStreaming(false);
             setCurrentStreamMessage("");
+            requestIdRef.current = null;
+            // The backend stored the question and the reply
+            setMessageIds(prev => [...prev, data.replyToId, data.messageId].filter(Boolean));
+            if (data.citations && data.citations.length > 0) {
+                setMessages(prevMessages => {
+                    const newMessages = [...prevMessages];
+                    if (newMessages.length > 0 && newMessages[newMessages.length - 1].role === 'assistant') {
+                        newMessages[newMessages.length - 1] = { ...newMessages[newMessages.length - 1], citations: data.citations };
+                    }
+                    return newMessages;
+                });
+            }
             console.log("Chat stream completed:", data.message);
         });
 
+        // Listen for cancelled generations, keeping whatever was streamed so far
+        const unsubscribeCancelled = EventsOn("chatStreamCancelled", (data) => {
+            if (!isOwnEvent(data)) return;
+            console.log("Chat stream cancelled:", data.requestId);
+            setIsStreaming(false);
+            setCurrentStreamMessage("");
+            requestIdRef.current = null;
+            setMessageIds(prev => [...prev, data.replyToId, data.messageId].filter(Boolean));
+            setMessages(prevMessages => {
+                const newMessages = [...prevMessages];
+                if (newMessages.length > 0 && newMessages[newMessages.length - 1].role === 'assistant' && !newMessages[newMessages.length - 1].content) {
+                    newMessages[newMessages.length - 1].content = "(Generation stopped)";
+                }
+                return newMessages;
+            });
+        });
+
         // Listen for stream errors
         const unsubscribeError = EventsOn("chatStreamError", (error) => {
+            if (!isOwnEvent(error)) return;
             console.error("Chat stream error:", error.error);
             setIsStreaming(false);
             setCurrentStreamMessage("");
+            requestIdRef.current = null;
 
             // Update the last message with error
             setMessages(prevMessages => {
@@ -154,10 +195,24 @@ function PromptSection() {
 
         // Listen for info messages (e.g., fallback to BeeAI)
         const unsubscribeInfo = EventsOn("chatStreamInfo", (data) => {
+            if (!isOwnEvent(data)) return;
             console.log("Chat info:", data.message);
             // You could show a small notification here if desired
         });
 
+        // Commands run from a hotkey stream into the chat of the live meeting like typed ones
+        const unsubscribeCommand = EventsOn("assistantCommandStarted", (data) => {
+            if (data.workspaceId !== parseInt(workspaceId)) return;
+            requestIdRef.current = data.requestId;
+            setIsStreaming(true);
+            setCurrentStreamMessage("");
+            setMessages(prevMessages => [...prevMessages, { role: 'user', content: data.message }, { role: 'assistant', content: '' }]);
+        });
+
+        const unsubscribeCommandFailed = EventsOn("assistantCommandFailed", (data) => {
+            console.error(`Failed to run /${data.command}:`, data.error);
+        });
+
         // Focus input on mount
         if (inputRef.current) {
             inputRef.current.focus();
@@ -168,8 +223,11 @@ function PromptSection() {
             unsubscribeStart();
             unsubscribeChunk();
             unsubscribeDone();
+            unsubscribeCancelled();
             unsubscribeError();
             unsubscribeInfo();
+            unsubscribeCommand();
+            unsubscribeCommandFailed();
         };
     }, [currentStreamMessage, messageIds, workspaceId]);
 
@@ -281,6 +339,50 @@ function PromptSection() {
                             wordBreak: 'break-word'
                         }}>
                             {msg.content}
+                            {msg.citations && msg.citations.length > 0 && (
+                                <div style={{ display: 'flex', flexWrap: 'wrap', gap: 4, marginTop: 8 }}>
+                                    {msg.citations.map(citation => {
+                                        const key = `${idx}-${citation.index}`;
+                                        return (
+                                            <span
+                                                key={key}
+                                                onClick={() => toggleCitation(key, citation)}
+                                                title={citation.snippet}
+                                                style={{
+                                                    fontSize: 11,
+                                                    padding: '2px 6px',
+                                                    borderRadius: 4,
+                                                    cursor: 'pointer',
+                                                    background: citation.cited ? 'rgba(255, 215, 0, 0.15)' : 'rgba(255, 255, 255, 0.05)',
+                                                    border: citation.cited ? '1px solid rgba(255, 215, 0, 0.4)' : '1px solid #444',
+                                                    color: citation.cited ? '#ffd700' : '#999'
+                                                }}
+                                            >
+                                                [{citation.index}] {citation.fileName} @{citation.offset}
+                                            </span>
+                                        );
+                                    })}
+                                </div>
+                            )}
+                            {openCitation && openCitation.key.startsWith(`${idx}-`) && (
+                                <div style={{
+                                    marginTop: 6,
+                                    padding: '6px 8px',
+                                    background: '#1a1a1a',
+                                    border: '1px solid #333',
+                                    borderRadius: 4,
+                                    fontSize: 12,
+                                    color: '#ccc',
+                                    whiteSpace: 'pre-wrap',
+                                    maxHeight: 200,
+                                    overflowY: 'auto'
+                                }}>
+                                    <div style={{ color: '#ffd700', marginBottom: 4 }}>
+                                        {openCitation.citation.fileName} (chunk {openCitation.citation.ordinal + 1}, offset {openCitation.citation.offset})
+                                    </div>
+                                    {openCitation.chunk.text}
+                                </div>
+                            )}
                         </div>
                     </div>
                 ))}
@@ -336,6 +438,29 @@ function PromptSection() {
                         }
                     }}
                 />
+                {isStreaming ? (
+                <button
+                    type="button"
+                    onClick={handleCancel}
+                    title="Stop generating"
+                    style={{
+                        background: 'rgba(244, 67, 54, 0.15)',
+                        color: '#f44336',
+                        border: '1px solid rgba(244, 67, 54, 0.4)',
+                        borderRadius: 6,
+                        padding: '8px',
+                        minWidth: 36,
+                        minHeight: 36,
+                        cursor: 'pointer',
+                        display: 'flex',
+                        alignItems: 'center',
+                        justifyContent: 'center',
+                        transition: 'all 0.2s ease'
+                    }}
+                >
+                    <StopIcon style={{ fontSize: 18 }} />
+                </button>
+                ) : (
                 <button
                     type="submit"
                     disabled={!prompt.trim()}
@@ -359,6 +484,7 @@ function PromptSection() {
                 >
                     <SendIcon style={{ fontSize: 18 }} />
                 </button>
+                )}
             </form>
         </div>
     );
@@ -1,6 +1,6 @@
 import React from 'react';
 import { Button, Card, CardContent, IconButton, Menu, MenuItem } from '@mui/material';
-import { Add as AddIcon, MoreVert as MoreVertIcon, Delete as DeleteIcon } from '@mui/icons-material';
+import { Add as AddIcon, MoreVert as MoreVertIcon, Delete as DeleteIcon, Archive as ArchiveIcon, FileDownload as FileDownloadIcon } from '@mui/icons-material';
 
 function WorkspacesSection({ 
     workspaces, 
@@ -12,7 +12,9 @@ function WorkspacesSection({
     menuAnchor,
     onMenuOpen,
     onMenuClose,
-    onDeleteClick
+    onDeleteClick,
+    onArchiveClick,
+    onExportClick
 }) {
     return (
         <div style={{ maxWidth: 1200, margin: '0 auto', width: '100%' }}>
@@ -232,9 +234,17 @@ function WorkspacesSection({
                     }
                 }}
             >
+                <MenuItem onClick={onExportClick}>
+                    <FileDownloadIcon fontSize="small" sx={{ mr: 1, color: '#ccc' }} />
+                    Export Workspace
+                </MenuItem>
+                <MenuItem onClick={onArchiveClick}>
+                    <ArchiveIcon fontSize="small" sx={{ mr: 1, color: '#ccc' }} />
+                    Archive Workspace
+                </MenuItem>
                 <MenuItem onClick={onDeleteClick}>
                     <DeleteIcon fontSize="small" sx={{ mr: 1, color: '#ff5252' }} />
-                    Delete Workspace
+                    Move to Trash
                 </MenuItem>
             </Menu>
         </div>
@@ -0,0 +1,144 @@
+import React, { useState, useEffect } from 'react';
+import { Button } from '@mui/material';
+import { EventsOn } from '../../../wailsjs/runtime/runtime';
+import { GetSessionByID, UpdateActionItem } from '../../../wailsjs/go/main/App';
+
+const rowStyle = {
+    padding: '6px 10px',
+    borderRadius: 6,
+    background: 'rgba(255,255,255,0.04)',
+    marginBottom: 6
+};
+
+// ActionItemsPanel shows the action items earlier meetings left open when a session starts,
+// marks them as the transcript mentions them, and lists what was extracted once it stops
+function ActionItemsPanel({ workspaceId }) {
+    const [carriedOver, setCarriedOver] = useState([]);
+    const [extraction, setExtraction] = useState(null);
+    const [collapsed, setCollapsed] = useState(false);
+
+    useEffect(() => {
+        setCarriedOver([]);
+        setExtraction(null);
+
+        const unsubscribeCarriedOver = EventsOn("actionItemsCarriedOver", (data) => {
+            if (data.workspaceId !== workspaceId) return;
+            setCarriedOver(data.items || []);
+            setExtraction(null);
+            setCollapsed(false);
+        });
+
+        const unsubscribeMentioned = EventsOn("actionItemsMentioned", (data) => {
+            const mentioned = (data.items || []).filter(item => item.workspaceId === workspaceId);
+            if (mentioned.length === 0) return;
+            setCarriedOver(prev => prev.map(item => mentioned.find(m => m.id === item.id) || item));
+        });
+
+        const unsubscribeExtracted = EventsOn("meetingItemsExtracted", async (data) => {
+            try {
+                const session = await GetSessionByID(data.sessionId);
+                if (session.workspaceId !== workspaceId) return;
+                setExtraction({
+                    actionItems: data.actionItems || [],
+                    decisions: data.decisions || [],
+                    questions: data.questions || []
+                });
+                setCollapsed(false);
+            } catch (error) {
+                console.error('Error loading session of extracted meeting items:', error);
+            }
+        });
+
+        return () => {
+            unsubscribeCarriedOver();
+            unsubscribeMentioned();
+            unsubscribeExtracted();
+        };
+    }, [workspaceId]);
+
+    const handleDone = async (item) => {
+        try {
+            const updated = await UpdateActionItem(item.id, item.text, item.owner, item.dueDate, 'done');
+            setCarriedOver(prev => prev.map(i => i.id === updated.id ? updated : i));
+        } catch (error) {
+            console.error('Error updating action item:', error);
+        }
+    };
+
+    if (carriedOver.length === 0 && !extraction) {
+        return null;
+    }
+
+    const renderItem = (item, showDone) => (
+        <div key={item.id} style={rowStyle}>
+            <div style={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', gap: 8 }}>
+                <span style={{
+                    color: item.status === 'open' ? '#fff' : '#888',
+                    textDecoration: item.status === 'done' ? 'line-through' : 'none',
+                    fontSize: '0.85rem'
+                }}>
+                    {item.text}
+                    {item.owner && <span style={{ color: '#999' }}> · {item.owner}</span>}
+                    {item.dueDate && <span style={{ color: '#999' }}> · due {new Date(item.dueDate).toLocaleDateString()}</span>}
+                </span>
+                {showDone && item.status === 'open' && (
+                    <Button size="small" onClick={() => handleDone(item)} sx={{ color: '#ffd700', textTransform: 'none', minWidth: 0 }}>
+                        Done
+                    </Button>
+                )}
+            </div>
+            {item.mentionText && (
+                <div style={{ color: '#4caf50', fontSize: '0.75rem', marginTop: 2 }}>
+                    Mentioned: “{item.mentionText}”
+                </div>
+            )}
+        </div>
+    );
+
+    return (
+        <div style={{
+            borderBottom: '2px solid #333',
+            background: '#1a1a24',
+            padding: '8px 12px',
+            maxHeight: '35%',
+            overflowY: 'auto',
+            flexShrink: 0
+        }}>
+            <div
+                onClick={() => setCollapsed(prev => !prev)}
+                style={{ display: 'flex', justifyContent: 'space-between', cursor: 'pointer', color: '#ffd700', fontWeight: 600, fontSize: '0.9rem' }}
+            >
+                <span>
+                    {extraction
+                        ? `Extracted: ${extraction.actionItems.length} action items, ${extraction.decisions.length} decisions, ${extraction.questions.length} questions`
+                        : `Open from earlier meetings (${carriedOver.filter(item => item.status === 'open').length})`}
+                </span>
+                <span>{collapsed ? '▼' : '▲'}</span>
+            </div>
+
+            {!collapsed && (
+                <div style={{ marginTop: 8 }}>
+                    {extraction ? (
+                        <>
+                            {extraction.actionItems.map(item => renderItem(item, false))}
+                            {extraction.decisions.map(decision => (
+                                <div key={`decision-${decision.id}`} style={{ ...rowStyle, color: '#ccc', fontSize: '0.85rem' }}>
+                                    ✔ {decision.text}
+                                </div>
+                            ))}
+                            {extraction.questions.map(question => (
+                                <div key={`question-${question.id}`} style={{ ...rowStyle, color: '#ccc', fontSize: '0.85rem' }}>
+                                    ? {question.text}
+                                </div>
+                            ))}
+                        </>
+                    ) : (
+                        carriedOver.map(item => renderItem(item, true))
+                    )}
+                </div>
+            )}
+        </div>
+    );
+}
+
+export default ActionItemsPanel;
@@ -0,0 +1,99 @@
+import React, { useState, useEffect } from 'react';
+
+const buttonStyle = {
+    background: 'none',
+    border: '1px solid #333',
+    borderRadius: 6,
+    fontSize: 11,
+    fontWeight: 600,
+    padding: '3px 8px',
+    cursor: 'pointer'
+};
+
+// Shows the changes the markdown agent proposes to the notes and lets the user pick which to apply
+function NotesPatchReview({ patch, onAccept, onReject }) {
+    const [selected, setSelected] = useState([]);
+
+    // Clean changes are selected by default, conflicting ones have to be picked on purpose
+    useEffect(() => {
+        setSelected(patch.hunks.filter(h => !h.conflict).map(h => h.index));
+    }, [patch]);
+
+    const toggle = (index) => {
+        setSelected(prev => prev.includes(index) ? prev.filter(i => i !== index) : [...prev, index]);
+    };
+
+    return (
+        <div style={{
+            marginBottom: 12,
+            background: '#23232f',
+            border: '1px solid rgba(76, 175, 80, 0.4)',
+            borderRadius: 8,
+            padding: 10,
+            maxHeight: '45%',
+            overflowY: 'auto',
+            flexShrink: 0
+        }}>
+            <div style={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', marginBottom: 8 }}>
+                <span style={{ color: '#4caf50', fontSize: 12, fontWeight: 700 }}>
+                    {patch.hunks.length} suggested {patch.hunks.length === 1 ? 'change' : 'changes'}
+                    {patch.conflicts > 0 && (
+                        <span style={{ color: '#ff9800', fontWeight: 400 }}>
+                            {' '}· {patch.conflicts} overlap your edits
+                        </span>
+                    )}
+                </span>
+                <div style={{ display: 'flex', gap: 6 }}>
+                    <button
+                        onClick={() => onAccept(selected)}
+                        disabled={selected.length === 0}
+                        style={{ ...buttonStyle, color: selected.length === 0 ? '#555' : '#4caf50' }}
+                    >
+                        Apply {selected.length === patch.hunks.length ? 'All' : `${selected.length}`}
+                    </button>
+                    <button onClick={onReject} style={{ ...buttonStyle, color: '#ccc' }}>
+                        Dismiss
+                    </button>
+                </div>
+            </div>
+            {patch.hunks.map(hunk => (
+                <label
+                    key={hunk.index}
+                    style={{
+                        display: 'flex',
+                        gap: 8,
+                        alignItems: 'flex-start',
+                        padding: 6,
+                        marginBottom: 6,
+                        borderRadius: 6,
+                        background: 'rgba(255,255,255,0.03)',
+                        border: hunk.conflict ? '1px solid rgba(255, 152, 0, 0.4)' : '1px solid transparent',
+                        cursor: 'pointer'
+                    }}
+                >
+                    <input
+                        type="checkbox"
+                        checked={selected.includes(hunk.index)}
+                        onChange={() => toggle(hunk.index)}
+                        style={{ accentColor: '#4caf50', marginTop: 2 }}
+                    />
+                    <div style={{ flex: 1, fontFamily: 'Consolas, Menlo, monospace', fontSize: 11, whiteSpace: 'pre-wrap' }}>
+                        {hunk.conflict && (
+                            <div style={{ color: '#ff9800', fontFamily: 'inherit', marginBottom: 2 }}>
+                                You edited these lines while the notes were being generated
+                            </div>
+                        )}
+                        {hunk.current.map((line, i) => (
+                            <div key={`c${i}`} style={{ color: '#ef9a9a', background: 'rgba(244, 67, 54, 0.12)' }}>- {line}</div>
+                        ))}
+                        {hunk.proposed.map((line, i) => (
+                            <div key={`p${i}`} style={{ color: '#a5d6a7', background: 'rgba(76, 175, 80, 0.12)' }}>+ {line}</div>
+                        ))}
+                    </div>
+                </label>
+            ))}
+        </div>
+    );
+}
+
+export default NotesPatchReview;
@@ -1,11 +1,10 @@
 import React, { useState, useEffect, useRef } from 'react';
 import { EventsOn, EventsOff } from '../../../wailsjs/runtime/runtime';
 import { 
-    CreateTranscriptionMessage, 
     GetTranscriptionMessagesByWorkspace, 
-    UpdateTranscriptionMessage,
-    GetTranscriptionMessageByMessageID,
-    GetTranscriptionServerStatus 
+    GetTranscriptionServerStatus,
+    SetActiveWorkspace,
+    SaveTranscriptExport
 } from '../../../wailsjs/go/main/App';
 import { useParams } from 'react-router-dom';
 
@@ -14,6 +13,7 @@ function TranscriptScreen() {
     const [selectedText, setSelectedText] = useState("");
     const [showReference, setShowReference] = useState(false);
     const [transcript, setTranscript] = useState([]);
+    const [exportFormat, setExportFormat] = useState('srt');
     const [connectionStatus, setConnectionStatus] = useState({
         connected: false,
         message: "Waiting for Chrome extension connection...",
@@ -97,61 +97,6 @@ function TranscriptScreen() {
         });
     };
 
-    // Helper function to save transcription message to database
-    const saveTranscriptionToDatabase = async (messageData) => {
-        if (!workspaceId) {
-            console.warn("⚠️ No workspace ID available, skipping database save");
-            return null;
-        }
-
-        try {
-            // Check if the function is available (in case Wails bindings haven't been regenerated)
-            if (typeof CreateTranscriptionMessage !== 'function') {
-                console.warn("⚠️ CreateTranscriptionMessage function not available, skipping database save");
-                return null;
-            }
-
-            const timestamp = new Date(messageData.timestamp || Date.now());
-            const result = await CreateTranscriptionMessage(
-                messageData.id,
-                parseInt(workspaceId),
-                messageData.text,
-                messageData.speaker,
-                messageData.source || 'google-meet',
-                messageData.messageType || 'caption_update',
-                timestamp
-            );
-            console.log("💾 Saved transcription to database:", result);
-            return result;
-        } catch (error) {
-            console.error("❌ Failed to save transcription to database:", error);
-            return null;
-        }
-    };
-
-    // Helper function to update transcription message in database
-    const updateTranscriptionInDatabase = async (messageID, text, speaker, timestamp) => {
-        try {
-            // Check if the function is available (in case Wails bindings haven't been regenerated)
-            if (typeof UpdateTranscriptionMessage !== 'function') {
-                console.warn("⚠️ UpdateTranscriptionMessage function not available, skipping database update");
-                return null;
-            }
-
-            const result = await UpdateTranscriptionMessage(
-                messageID,
-                text,
-                speaker,
-                new Date(timestamp || Date.now())
-            );
-            console.log("🔄 Updated transcription in database:", result);
-            return result;
-        } catch (error) {
-            console.error("❌ Failed to update transcription in database:", error);
-            return null;
-        }
-    };
-
     // Listen for transcription events from Go backend
     useEffect(() => {
         console.log("🎤 Setting up transcription event listeners for workspace:", workspaceId);
@@ -159,6 +104,14 @@ function TranscriptScreen() {
         // Clear transcript when workspace changes
         setTranscript([]);
 
+        // Captions are persisted into the workspace of the open session; this only tells the
+        // backend which workspace is on screen
+        if (workspaceId) {
+            SetActiveWorkspace(parseInt(workspaceId)).catch(error => {
+                console.error("❌ Failed to set active workspace:", error);
+            });
+        }
+
         // Load existing transcription messages for this workspace
         const loadExistingTranscriptions = async () => {
             if (!workspaceId) return;
@@ -175,7 +128,7 @@ function TranscriptScreen() {
                 
                 if (existingMessages && existingMessages.length > 0) {
                     const formattedMessages = existingMessages.map(record => ({
-                        id: record.messageID,
+                        id: record.messageId,
                         speaker: record.speaker,
                         text: record.text,
                         timestamp: record.timestamp,
@@ -215,6 +168,11 @@ function TranscriptScreen() {
                 return;
             }
 
+            // Captions of a meeting recorded into another workspace don't belong here
+            if (data.workspaceId && data.workspaceId !== parseInt(workspaceId)) {
+                return;
+            }
+
             const newMessage = {
                 id: data.id || `msg_${Date.now()}_${Math.random()}`, // Generate ID if not provided
                 speaker: data.speaker || 'Unknown',
@@ -252,9 +210,6 @@ function TranscriptScreen() {
                     return prev; // Don't add duplicate
                 }
 
-                // Save to database (async, don't wait for it)
-                saveTranscriptionToDatabase(newMessage);
-
                 // For regular messages, just add them
                 const newTranscript = [...prev, newMessage];
                 return removeDuplicates(newTranscript);
@@ -271,6 +226,11 @@ function TranscriptScreen() {
                 return;
             }
 
+            // Captions of a meeting recorded into another workspace don't belong here
+            if (data.workspaceId && data.workspaceId !== parseInt(workspaceId)) {
+                return;
+            }
+
             setTranscript(prev => {
                 const updated = [...prev];
                 
@@ -279,6 +239,11 @@ function TranscriptScreen() {
                 let foundIndex = -1;
                 for (let i = updated.length - 1; i >= 0; i--) {
                     const message = updated[i];
+                    // The server assigns stable IDs, prefer them when available
+                    if (data.id && message.id === data.id) {
+                        foundIndex = i;
+                        break;
+                    }
                     // Match by oldText and speaker to ensure we're updating the right message
                     // Also check if speakers match (but be flexible about undefined/null)
                     const speakerMatch = (message.speaker === data.speaker) || 
@@ -316,14 +281,6 @@ function TranscriptScreen() {
                         lastUpdated: new Date().toISOString()
                     };
                     console.log(`✅ Updated message at index ${foundIndex}: "${data.oldText}" → "${data.text}" (Speaker: ${originalSpeaker} → ${newSpeaker})`);
-                    
-                    // Update in database (async, don't wait for it)
-                    updateTranscriptionInDatabase(
-                        updated[foundIndex].id, 
-                        data.text || '', 
-                        newSpeaker, 
-                        data.timestamp
-                    );
                 } else {
                     // If no matching message found, treat it as a new message
                     console.log(`⚠️ No matching message found for oldText: "${data.oldText}" (speaker: ${data.speaker}), adding as new message`);
@@ -335,7 +292,7 @@ function TranscriptScreen() {
                     }
                     
                     const newMessage = {
-                        id: `msg_${Date.now()}_${Math.random()}`,
+                        id: data.id || `msg_${Date.now()}_${Math.random()}`,
                         speaker: data.speaker,
                         text: data.text || '',
                         timestamp: data.timestamp,
@@ -347,9 +304,6 @@ function TranscriptScreen() {
                         isSystemMessage: (data.speaker === "System")
                     };
                     updated.push(newMessage);
-                    
-                    // Save new message to database (async, don't wait for it)
-                    saveTranscriptionToDatabase(newMessage);
                 }
                 
                 return removeDuplicates(updated);
@@ -376,6 +330,9 @@ function TranscriptScreen() {
         // Cleanup event listeners on unmount
         return () => {
             console.log("🧹 Cleaning up transcription event listeners for workspace:", workspaceId);
+            SetActiveWorkspace(0).catch(error => {
+                console.error("❌ Failed to reset active workspace:", error);
+            });
             clearInterval(statusInterval);
             unsubscribeNewMessage();
             unsubscribeMessageUpdate();
@@ -415,6 +372,18 @@ function TranscriptScreen() {
         setSelectedText("");
     };
 
+    const handleExportTranscript = async () => {
+        if (!workspaceId) return;
+        try {
+            const savedTo = await SaveTranscriptExport(parseInt(workspaceId), exportFormat, {
+                mergeConsecutive: exportFormat !== 'json'
+            });
+            if (savedTo) console.log('📝 Transcript exported to', savedTo);
+        } catch (error) {
+            console.error('Error exporting transcript:', error);
+        }
+    };
+
     return (
         <>
             <style>
@@ -487,6 +456,41 @@ function TranscriptScreen() {
                     alignItems: 'center',
                     gap: 8
                 }}>
+                    <select
+                        value={exportFormat}
+                        onChange={(e) => setExportFormat(e.target.value)}
+                        title="Transcript export format"
+                        style={{
+                            background: '#20202a',
+                            color: '#ccc',
+                            border: '1px solid #333',
+                            borderRadius: 6,
+                            fontSize: 10,
+                            padding: '2px 4px'
+                        }}
+                    >
+                        <option value="srt">SRT</option>
+                        <option value="vtt">WebVTT</option>
+                        <option value="txt">Text</option>
+                        <option value="md">Markdown</option>
+                        <option value="json">JSON</option>
+                    </select>
+                    <button
+                        onClick={handleExportTranscript}
+                        disabled={transcript.length === 0}
+                        style={{
+                            background: 'none',
+                            border: '1px solid #333',
+                            borderRadius: 6,
+                            color: transcript.length === 0 ? '#555' : '#ffd700',
+                            fontSize: 10,
+                            fontWeight: 600,
+                            padding: '3px 6px',
+                            cursor: transcript.length === 0 ? 'default' : 'pointer'
+                        }}
+                    >
+                        Export
+                    </button>
                     <div style={{
                         background: connectionStatus.connected 
                             ? 'rgba(255, 215, 0, 0.1)' 
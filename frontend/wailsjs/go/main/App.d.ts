@@ -3,6 +3,14 @@
 import {main} from '../models';
 import {time} from '../models';
 
+export function AcceptNotesPatch(arg1:string,arg2:Array<number>):Promise<main.MeetingNotes>;
+
+export function ArchiveWorkspace(arg1:number):Promise<main.Workspace>;
+
+export function CancelChatGeneration(arg1:string):Promise<void>;
+
+export function CancelMarkdownAgent(arg1:string):Promise<void>;
+
 export function CheckGraniteInstallation():Promise<boolean>;
 
 export function CheckLocalOllamaInstallation():Promise<boolean>;
@@ -17,64 +25,124 @@ export function CreateKnowledgeBaseItem(arg1:string,arg2:string,arg3:string,arg4
 
 export function CreateMeetingNotes(arg1:number,arg2:string):Promise<main.MeetingNotes>;
 
+export function CreatePromptTemplate(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.PromptTemplate>;
+
+
 export function CreateTranscriptionMessage(arg1:string,arg2:number,arg3:string,arg4:string,arg5:string,arg6:string,arg7:time.Time):Promise<main.TranscriptionRecord>;
 
 export function CreateWorkspace(arg1:string,arg2:string):Promise<main.Workspace>;
 
 export function DeleteAIChatMessage(arg1:number):Promise<void>;
 
+export function DeleteActionItem(arg1:number):Promise<void>;
+
 export function DeleteKnowledgeBaseItem(arg1:number):Promise<void>;
 
 export function DeleteMeetingNotes(arg1:number):Promise<void>;
 
 export function DeleteMeetingNotesByWorkspace(arg1:number):Promise<void>;
 
+export function DeleteMeetingSummary(arg1:number):Promise<void>;
+
+export function DeletePromptTemplate(arg1:number):Promise<void>;
+
+
 export function DeleteTranscriptionMessage(arg1:number):Promise<void>;
 
 export function DeleteTranscriptionMessagesByWorkspace(arg1:number):Promise<void>;
 
 export function DeleteWorkspace(arg1:number):Promise<void>;
 
+export function DiffNotesRevisions(arg1:number,arg2:number):Promise<main.NotesDiff>;
+
 export function DisconnectWebSocket():Promise<void>;
 
 export function DownloadGraniteModel():Promise<void>;
 
+export function EmbedKnowledgeBase():Promise<number>;
+
+export function ExportMeetingNotes(arg1:number,arg2:string,arg3:main.NotesExportOptions):Promise<string>;
+
+export function ExportPromptTemplates(arg1:Array<number>,arg2:string):Promise<string>;
+
+export function ExportTranscript(arg1:number,arg2:string,arg3:main.TranscriptExportOptions):Promise<string>;
+
+export function ExportWorkspace(arg1:number,arg2:string):Promise<string>;
+
+export function ExtractMeetingItems(arg1:number):Promise<main.MeetingExtraction>;
+
+export function GenerateMeetingSummary(arg1:number,arg2:number):Promise<main.MeetingSummary>;
+
 export function GetAIChatMessageByID(arg1:number):Promise<main.AIChatMessage>;
 
+export function GetAIChatMessagesBySession(arg1:number):Promise<Array<main.AIChatMessage>>;
+
 export function GetAIChatMessagesByWorkspace(arg1:number):Promise<Array<main.AIChatMessage>>;
 
+export function GetActiveWorkspace():Promise<number>;
+
 export function GetAllAIChatMessages():Promise<Array<main.AIChatMessage>>;
 
 export function GetAllKnowledgeBaseItems():Promise<Array<main.KnowledgeBase>>;
 
 export function GetAllWorkspaces():Promise<Array<main.Workspace>>;
 
+export function GetArchivedWorkspaces():Promise<Array<main.Workspace>>;
+
+export function GetCarriedOverActionItems(arg1:number):Promise<Array<main.ActionItem>>;
+
+export function GetChatProviders():Promise<Array<main.ChatProviderInfo>>;
+
 export function GetKnowledgeBaseItemByID(arg1:number):Promise<main.KnowledgeBase>;
 
 export function GetKnowledgeBaseItemByUniqueFileName(arg1:string):Promise<main.KnowledgeBase>;
 
 export function GetKnowledgeBaseItemsByType(arg1:string):Promise<Array<main.KnowledgeBase>>;
 
+export function GetKnowledgeChunk(arg1:number):Promise<main.KnowledgeChunk>;
+
+export function GetLiveNotesStatus(arg1:number):Promise<main.LiveNotesStatus>;
+
 export function GetMeetingNotesByID(arg1:number):Promise<main.MeetingNotes>;
 
 export function GetMeetingNotesByWorkspace(arg1:number):Promise<Array<main.MeetingNotes>>;
 
+export function GetOpenSession(arg1:number):Promise<main.Session>;
+
+export function GetPendingNotesPatch(arg1:number):Promise<main.NotesPatch>;
+
+export function GetSessionByID(arg1:number):Promise<main.Session>;
+
+export function GetSessionsByWorkspace(arg1:number):Promise<Array<main.Session>>;
+
+export function GetSettings():Promise<Record<string, string>>;
+
 export function GetTranscriptionMessageByID(arg1:number):Promise<main.TranscriptionRecord>;
 
 export function GetTranscriptionMessageByMessageID(arg1:string):Promise<main.TranscriptionRecord>;
 
 export function GetTranscriptionMessagesByDateRange(arg1:number,arg2:time.Time,arg3:time.Time):Promise<Array<main.TranscriptionRecord>>;
 
+export function GetTranscriptionMessagesBySession(arg1:number):Promise<Array<main.TranscriptionRecord>>;
+
 export function GetTranscriptionMessagesByWorkspace(arg1:number):Promise<Array<main.TranscriptionRecord>>;
 
 export function GetTranscriptionServerStatus():Promise<Record<string, any>>;
 
 export function GetWorkspaceByID(arg1:number):Promise<main.Workspace>;
 
+export function GetWorkspacePromptTemplate(arg1:number,arg2:string):Promise<main.PromptTemplate>;
+
+export function GlobalSearch(arg1:string,arg2:main.SearchFilters):Promise<Array<main.SearchResult>>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function HealthCheckForFrontend():Promise<string>;
 
+export function ImportPromptTemplates(arg1:string):Promise<Array<main.PromptTemplate>>;
+
+export function ImportWorkspace(arg1:string):Promise<main.ImportReport>;
+
 export function InitializeMarkdownAgentWebSocket():Promise<void>;
 
 export function InitializeTranscriptionServer():Promise<void>;
@@ -87,44 +155,118 @@ export function IsMarkdownAgentWebSocketConnected():Promise<boolean>;
 
 export function IsOllamaRunning():Promise<boolean>;
 
+export function ListActionItems(arg1:number):Promise<Array<main.ActionItem>>;
+
+export function ListAssistantCommands():Promise<Array<main.AssistantCommandInfo>>;
+
+export function ListChatModels(arg1:string):Promise<Array<string>>;
+
+export function ListDecisions(arg1:number):Promise<Array<main.Decision>>;
+
+export function ListMeetingSummaries(arg1:number):Promise<Array<main.MeetingSummary>>;
+
+export function ListNotesRevisions(arg1:number):Promise<Array<main.MeetingNotesRevision>>;
+
+export function ListOpenActionItems():Promise<Array<main.ActionItem>>;
+
+export function ListOpenQuestions(arg1:number):Promise<Array<main.OpenQuestion>>;
+
+export function ListPromptTemplates(arg1:string):Promise<Array<main.PromptTemplate>>;
+
+export function ListPromptVariables():Promise<Array<main.PromptVariable>>;
+
+
+export function ListTrash():Promise<Array<main.Workspace>>;
+
 export function MoveFilesToYumesession(arg1:Array<string>):Promise<Array<string>>;
 
 export function OpenAndGetPDFData(arg1:string):Promise<Array<number>>;
 
 export function OpenMultipleFilesDialog():Promise<Array<string>>;
 
+export function PauseSession(arg1:number):Promise<main.Session>;
+
+export function PreviewPromptTemplate(arg1:number,arg2:string,arg3:string):Promise<string>;
+
+export function PurgeTrash(arg1:number):Promise<number>;
+
+export function ReindexKnowledgeBase():Promise<void>;
+
+export function ReindexKnowledgeBaseItem(arg1:number):Promise<void>;
+
+export function RejectNotesPatch(arg1:string):Promise<void>;
+
+export function RepairDatabase(arg1:boolean):Promise<main.RepairReport>;
+
 export function RestartTranscriptionServer():Promise<void>;
 
+export function RestoreNotesRevision(arg1:number):Promise<main.MeetingNotes>;
+
+export function RestoreWorkspace(arg1:number):Promise<main.Workspace>;
+
+export function ResumeSession(arg1:number):Promise<main.Session>;
+
+export function SaveAgentMeetingNotes(arg1:number,arg2:string):Promise<main.MeetingNotes>;
+
+export function SaveTranscriptExport(arg1:number,arg2:string,arg3:main.TranscriptExportOptions):Promise<string>;
+
 export function SearchKnowledgeBaseItems(arg1:string):Promise<Array<main.KnowledgeBase>>;
 
 export function SearchMeetingNotes(arg1:number,arg2:string):Promise<Array<main.MeetingNotes>>;
 
-export function SendChatMessage(arg1:number,arg2:string,arg3:string):Promise<void>;
+export function SemanticSearchKnowledgeBase(arg1:string,arg2:number):Promise<Array<main.KnowledgeSearchResult>>;
+
+export function SendChatMessage(arg1:number,arg2:string,arg3:string):Promise<string>;
 
-export function SendChatWithSystemPrompt(arg1:number,arg2:string,arg3:string):Promise<void>;
+export function SendChatWithSystemPrompt(arg1:number,arg2:string,arg3:string):Promise<string>;
 
-export function SendMarkdownAgentMessage(arg1:string):Promise<void>;
+export function SendMarkdownAgentMessage(arg1:string):Promise<string>;
 
-export function SendMeetingNotesRequest(arg1:Array<string>,arg2:string):Promise<void>;
+export function SendMeetingNotesRequest(arg1:number,arg2:Array<string>,arg3:string):Promise<string>;
 
-export function SendSimpleChatMessage(arg1:number,arg2:string):Promise<void>;
+export function SendSimpleChatMessage(arg1:number,arg2:string):Promise<string>;
 
 export function SendTestTranscription(arg1:string,arg2:string):Promise<void>;
 
+export function SetActiveWorkspace(arg1:number):Promise<void>;
+
+export function SetWorkspacePromptTemplate(arg1:number,arg2:string,arg3:number):Promise<void>;
+
+export function StartLiveNotes(arg1:number):Promise<main.LiveNotesStatus>;
+
 export function StartOllamaServer():Promise<void>;
 
+export function StartSession(arg1:number,arg2:string,arg3:string):Promise<main.Session>;
+
+export function StopLiveNotes(arg1:number):Promise<void>;
+
+export function StopSession(arg1:number):Promise<main.Session>;
+
 export function StopTranscriptionServer():Promise<void>;
 
 export function SummarizeDocumentForFrontend(arg1:string):Promise<string>;
 
+export function UnarchiveWorkspace(arg1:number):Promise<main.Workspace>;
+
 export function UpdateAIChatMessage(arg1:number,arg2:number,arg3:string,arg4:string):Promise<main.AIChatMessage>;
 
+export function UpdateActionItem(arg1:number,arg2:string,arg3:string,arg4:time.Time,arg5:string):Promise<main.ActionItem>;
+
 export function UpdateKnowledgeBaseItem(arg1:number,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.KnowledgeBase>;
 
 export function UpdateMeetingNotes(arg1:number,arg2:string):Promise<main.MeetingNotes>;
 
+export function UpdatePromptTemplate(arg1:number,arg2:string,arg3:string,arg4:string):Promise<main.PromptTemplate>;
+
+export function UpdateSessionDetails(arg1:number,arg2:string,arg3:Array<string>):Promise<main.Session>;
+
+export function UpdateSettings(arg1:Record<string, string>):Promise<void>;
+
+
 export function UpdateTranscriptionMessage(arg1:string,arg2:string,arg3:string,arg4:time.Time):Promise<main.TranscriptionRecord>;
 
 export function UpdateWorkspace(arg1:number,arg2:string,arg3:string):Promise<main.Workspace>;
 
+export function UpdateWorkspaceChatBackend(arg1:number,arg2:string):Promise<main.Workspace>;
+
 export function UpdateWorkspaceLastOpen(arg1:number):Promise<void>;
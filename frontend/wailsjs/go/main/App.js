@@ -2,6 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcceptNotesPatch(arg1, arg2) {
+  return window['go']['main']['App']['AcceptNotesPatch'](arg1, arg2);
+}
+
+export function ArchiveWorkspace(arg1) {
+  return window['go']['main']['App']['ArchiveWorkspace'](arg1);
+}
+
+export function CancelChatGeneration(arg1) {
+  return window['go']['main']['App']['CancelChatGeneration'](arg1);
+}
+
+export function CancelMarkdownAgent(arg1) {
+  return window['go']['main']['App']['CancelMarkdownAgent'](arg1);
+}
+
 export function CheckGraniteInstallation() {
   return window['go']['main']['App']['CheckGraniteInstallation']();
 }
@@ -30,6 +46,10 @@ export function CreateMeetingNotes(arg1, arg2) {
   return window['go']['main']['App']['CreateMeetingNotes'](arg1, arg2);
 }
 
+export function CreatePromptTemplate(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreatePromptTemplate'](arg1, arg2, arg3, arg4);
+}
+
 export function CreateTranscriptionMessage(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
   return window['go']['main']['App']['CreateTranscriptionMessage'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
 }
@@ -42,6 +62,10 @@ export function DeleteAIChatMessage(arg1) {
   return window['go']['main']['App']['DeleteAIChatMessage'](arg1);
 }
 
+export function DeleteActionItem(arg1) {
+  return window['go']['main']['App']['DeleteActionItem'](arg1);
+}
+
 export function DeleteKnowledgeBaseItem(arg1) {
   return window['go']['main']['App']['DeleteKnowledgeBaseItem'](arg1);
 }
@@ -54,6 +78,14 @@ export function DeleteMeetingNotesByWorkspace(arg1) {
   return window['go']['main']['App']['DeleteMeetingNotesByWorkspace'](arg1);
 }
 
+export function DeleteMeetingSummary(arg1) {
+  return window['go']['main']['App']['DeleteMeetingSummary'](arg1);
+}
+
+export function DeletePromptTemplate(arg1) {
+  return window['go']['main']['App']['DeletePromptTemplate'](arg1);
+}
+
 export function DeleteTranscriptionMessage(arg1) {
   return window['go']['main']['App']['DeleteTranscriptionMessage'](arg1);
 }
@@ -66,6 +98,10 @@ export function DeleteWorkspace(arg1) {
   return window['go']['main']['App']['DeleteWorkspace'](arg1);
 }
 
+export function DiffNotesRevisions(arg1, arg2) {
+  return window['go']['main']['App']['DiffNotesRevisions'](arg1, arg2);
+}
+
 export function DisconnectWebSocket() {
   return window['go']['main']['App']['DisconnectWebSocket']();
 }
@@ -74,14 +110,50 @@ export function DownloadGraniteModel() {
   return window['go']['main']['App']['DownloadGraniteModel']();
 }
 
+export function EmbedKnowledgeBase() {
+  return window['go']['main']['App']['EmbedKnowledgeBase']();
+}
+
+export function ExportMeetingNotes(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportMeetingNotes'](arg1, arg2, arg3);
+}
+
+export function ExportPromptTemplates(arg1, arg2) {
+  return window['go']['main']['App']['ExportPromptTemplates'](arg1, arg2);
+}
+
+export function ExportTranscript(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportTranscript'](arg1, arg2, arg3);
+}
+
+export function ExportWorkspace(arg1, arg2) {
+  return window['go']['main']['App']['ExportWorkspace'](arg1, arg2);
+}
+
+export function ExtractMeetingItems(arg1) {
+  return window['go']['main']['App']['ExtractMeetingItems'](arg1);
+}
+
+export function GenerateMeetingSummary(arg1, arg2) {
+  return window['go']['main']['App']['GenerateMeetingSummary'](arg1, arg2);
+}
+
 export function GetAIChatMessageByID(arg1) {
   return window['go']['main']['App']['GetAIChatMessageByID'](arg1);
 }
 
+export function GetAIChatMessagesBySession(arg1) {
+  return window['go']['main']['App']['GetAIChatMessagesBySession'](arg1);
+}
+
 export function GetAIChatMessagesByWorkspace(arg1) {
   return window['go']['main']['App']['GetAIChatMessagesByWorkspace'](arg1);
 }
 
+export function GetActiveWorkspace() {
+  return window['go']['main']['App']['GetActiveWorkspace']();
+}
+
 export function GetAllAIChatMessages() {
   return window['go']['main']['App']['GetAllAIChatMessages']();
 }
@@ -94,6 +166,18 @@ export function GetAllWorkspaces() {
   return window['go']['main']['App']['GetAllWorkspaces']();
 }
 
+export function GetArchivedWorkspaces() {
+  return window['go']['main']['App']['GetArchivedWorkspaces']();
+}
+
+export function GetCarriedOverActionItems(arg1) {
+  return window['go']['main']['App']['GetCarriedOverActionItems'](arg1);
+}
+
+export function GetChatProviders() {
+  return window['go']['main']['App']['GetChatProviders']();
+}
+
 export function GetKnowledgeBaseItemByID(arg1) {
   return window['go']['main']['App']['GetKnowledgeBaseItemByID'](arg1);
 }
@@ -106,6 +190,14 @@ export function GetKnowledgeBaseItemsByType(arg1) {
   return window['go']['main']['App']['GetKnowledgeBaseItemsByType'](arg1);
 }
 
+export function GetKnowledgeChunk(arg1) {
+  return window['go']['main']['App']['GetKnowledgeChunk'](arg1);
+}
+
+export function GetLiveNotesStatus(arg1) {
+  return window['go']['main']['App']['GetLiveNotesStatus'](arg1);
+}
+
 export function GetMeetingNotesByID(arg1) {
   return window['go']['main']['App']['GetMeetingNotesByID'](arg1);
 }
@@ -114,6 +206,26 @@ export function GetMeetingNotesByWorkspace(arg1) {
   return window['go']['main']['App']['GetMeetingNotesByWorkspace'](arg1);
 }
 
+export function GetOpenSession(arg1) {
+  return window['go']['main']['App']['GetOpenSession'](arg1);
+}
+
+export function GetPendingNotesPatch(arg1) {
+  return window['go']['main']['App']['GetPendingNotesPatch'](arg1);
+}
+
+export function GetSessionByID(arg1) {
+  return window['go']['main']['App']['GetSessionByID'](arg1);
+}
+
+export function GetSessionsByWorkspace(arg1) {
+  return window['go']['main']['App']['GetSessionsByWorkspace'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
 export function GetTranscriptionMessageByID(arg1) {
   return window['go']['main']['App']['GetTranscriptionMessageByID'](arg1);
 }
@@ -126,6 +238,10 @@ export function GetTranscriptionMessagesByDateRange(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetTranscriptionMessagesByDateRange'](arg1, arg2, arg3);
 }
 
+export function GetTranscriptionMessagesBySession(arg1) {
+  return window['go']['main']['App']['GetTranscriptionMessagesBySession'](arg1);
+}
+
 export function GetTranscriptionMessagesByWorkspace(arg1) {
   return window['go']['main']['App']['GetTranscriptionMessagesByWorkspace'](arg1);
 }
@@ -138,6 +254,14 @@ export function GetWorkspaceByID(arg1) {
   return window['go']['main']['App']['GetWorkspaceByID'](arg1);
 }
 
+export function GetWorkspacePromptTemplate(arg1, arg2) {
+  return window['go']['main']['App']['GetWorkspacePromptTemplate'](arg1, arg2);
+}
+
+export function GlobalSearch(arg1, arg2) {
+  return window['go']['main']['App']['GlobalSearch'](arg1, arg2);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -146,6 +270,14 @@ export function HealthCheckForFrontend() {
   return window['go']['main']['App']['HealthCheckForFrontend']();
 }
 
+export function ImportPromptTemplates(arg1) {
+  return window['go']['main']['App']['ImportPromptTemplates'](arg1);
+}
+
+export function ImportWorkspace(arg1) {
+  return window['go']['main']['App']['ImportWorkspace'](arg1);
+}
+
 export function InitializeMarkdownAgentWebSocket() {
   return window['go']['main']['App']['InitializeMarkdownAgentWebSocket']();
 }
@@ -170,6 +302,50 @@ export function IsOllamaRunning() {
   return window['go']['main']['App']['IsOllamaRunning']();
 }
 
+export function ListActionItems(arg1) {
+  return window['go']['main']['App']['ListActionItems'](arg1);
+}
+
+export function ListAssistantCommands() {
+  return window['go']['main']['App']['ListAssistantCommands']();
+}
+
+export function ListChatModels(arg1) {
+  return window['go']['main']['App']['ListChatModels'](arg1);
+}
+
+export function ListDecisions(arg1) {
+  return window['go']['main']['App']['ListDecisions'](arg1);
+}
+
+export function ListMeetingSummaries(arg1) {
+  return window['go']['main']['App']['ListMeetingSummaries'](arg1);
+}
+
+export function ListNotesRevisions(arg1) {
+  return window['go']['main']['App']['ListNotesRevisions'](arg1);
+}
+
+export function ListOpenActionItems() {
+  return window['go']['main']['App']['ListOpenActionItems']();
+}
+
+export function ListOpenQuestions(arg1) {
+  return window['go']['main']['App']['ListOpenQuestions'](arg1);
+}
+
+export function ListPromptTemplates(arg1) {
+  return window['go']['main']['App']['ListPromptTemplates'](arg1);
+}
+
+export function ListPromptVariables() {
+  return window['go']['main']['App']['ListPromptVariables']();
+}
+
+export function ListTrash() {
+  return window['go']['main']['App']['ListTrash']();
+}
+
 export function MoveFilesToYumesession(arg1) {
   return window['go']['main']['App']['MoveFilesToYumesession'](arg1);
 }
@@ -182,10 +358,58 @@ export function OpenMultipleFilesDialog() {
   return window['go']['main']['App']['OpenMultipleFilesDialog']();
 }
 
+export function PauseSession(arg1) {
+  return window['go']['main']['App']['PauseSession'](arg1);
+}
+
+export function PreviewPromptTemplate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewPromptTemplate'](arg1, arg2, arg3);
+}
+
+export function PurgeTrash(arg1) {
+  return window['go']['main']['App']['PurgeTrash'](arg1);
+}
+
+export function ReindexKnowledgeBase() {
+  return window['go']['main']['App']['ReindexKnowledgeBase']();
+}
+
+export function ReindexKnowledgeBaseItem(arg1) {
+  return window['go']['main']['App']['ReindexKnowledgeBaseItem'](arg1);
+}
+
+export function RejectNotesPatch(arg1) {
+  return window['go']['main']['App']['RejectNotesPatch'](arg1);
+}
+
+export function RepairDatabase(arg1) {
+  return window['go']['main']['App']['RepairDatabase'](arg1);
+}
+
 export function RestartTranscriptionServer() {
   return window['go']['main']['App']['RestartTranscriptionServer']();
 }
 
+export function RestoreNotesRevision(arg1) {
+  return window['go']['main']['App']['RestoreNotesRevision'](arg1);
+}
+
+export function RestoreWorkspace(arg1) {
+  return window['go']['main']['App']['RestoreWorkspace'](arg1);
+}
+
+export function ResumeSession(arg1) {
+  return window['go']['main']['App']['ResumeSession'](arg1);
+}
+
+export function SaveAgentMeetingNotes(arg1, arg2) {
+  return window['go']['main']['App']['SaveAgentMeetingNotes'](arg1, arg2);
+}
+
+export function SaveTranscriptExport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveTranscriptExport'](arg1, arg2, arg3);
+}
+
 export function SearchKnowledgeBaseItems(arg1) {
   return window['go']['main']['App']['SearchKnowledgeBaseItems'](arg1);
 }
@@ -194,6 +418,10 @@ export function SearchMeetingNotes(arg1, arg2) {
   return window['go']['main']['App']['SearchMeetingNotes'](arg1, arg2);
 }
 
+export function SemanticSearchKnowledgeBase(arg1, arg2) {
+  return window['go']['main']['App']['SemanticSearchKnowledgeBase'](arg1, arg2);
+}
+
 export function SendChatMessage(arg1, arg2, arg3) {
   return window['go']['main']['App']['SendChatMessage'](arg1, arg2, arg3);
 }
@@ -206,8 +434,8 @@ export function SendMarkdownAgentMessage(arg1) {
   return window['go']['main']['App']['SendMarkdownAgentMessage'](arg1);
 }
 
-export function SendMeetingNotesRequest(arg1, arg2) {
-  return window['go']['main']['App']['SendMeetingNotesRequest'](arg1, arg2);
+export function SendMeetingNotesRequest(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SendMeetingNotesRequest'](arg1, arg2, arg3);
 }
 
 export function SendSimpleChatMessage(arg1, arg2) {
@@ -218,10 +446,34 @@ export function SendTestTranscription(arg1, arg2) {
   return window['go']['main']['App']['SendTestTranscription'](arg1, arg2);
 }
 
+export function SetActiveWorkspace(arg1) {
+  return window['go']['main']['App']['SetActiveWorkspace'](arg1);
+}
+
+export function SetWorkspacePromptTemplate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetWorkspacePromptTemplate'](arg1, arg2, arg3);
+}
+
+export function StartLiveNotes(arg1) {
+  return window['go']['main']['App']['StartLiveNotes'](arg1);
+}
+
 export function StartOllamaServer() {
   return window['go']['main']['App']['StartOllamaServer']();
 }
 
+export function StartSession(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartSession'](arg1, arg2, arg3);
+}
+
+export function StopLiveNotes(arg1) {
+  return window['go']['main']['App']['StopLiveNotes'](arg1);
+}
+
+export function StopSession(arg1) {
+  return window['go']['main']['App']['StopSession'](arg1);
+}
+
 export function StopTranscriptionServer() {
   return window['go']['main']['App']['StopTranscriptionServer']();
 }
@@ -230,10 +482,18 @@ export function SummarizeDocumentForFrontend(arg1) {
   return window['go']['main']['App']['SummarizeDocumentForFrontend'](arg1);
 }
 
+export function UnarchiveWorkspace(arg1) {
+  return window['go']['main']['App']['UnarchiveWorkspace'](arg1);
+}
+
 export function UpdateAIChatMessage(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['UpdateAIChatMessage'](arg1, arg2, arg3, arg4);
 }
 
+export function UpdateActionItem(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['UpdateActionItem'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function UpdateKnowledgeBaseItem(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['UpdateKnowledgeBaseItem'](arg1, arg2, arg3, arg4, arg5);
 }
@@ -242,6 +502,18 @@ export function UpdateMeetingNotes(arg1, arg2) {
   return window['go']['main']['App']['UpdateMeetingNotes'](arg1, arg2);
 }
 
+export function UpdatePromptTemplate(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['UpdatePromptTemplate'](arg1, arg2, arg3, arg4);
+}
+
+export function UpdateSessionDetails(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateSessionDetails'](arg1, arg2, arg3);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
 export function UpdateTranscriptionMessage(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['UpdateTranscriptionMessage'](arg1, arg2, arg3, arg4);
 }
@@ -250,6 +522,10 @@ export function UpdateWorkspace(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdateWorkspace'](arg1, arg2, arg3);
 }
 
+export function UpdateWorkspaceChatBackend(arg1, arg2) {
+  return window['go']['main']['App']['UpdateWorkspaceChatBackend'](arg1, arg2);
+}
+
 export function UpdateWorkspaceLastOpen(arg1) {
   return window['go']['main']['App']['UpdateWorkspaceLastOpen'](arg1);
 }
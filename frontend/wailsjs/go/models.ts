@@ -1,12 +1,52 @@
+export namespace gorm {
+	
+	export class DeletedAt {
+	    Time: time.Time;
+	    Valid: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletedAt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Time = this.convertValues(source["Time"], time.Time);
+	        this.Valid = source["Valid"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
 	export class Workspace {
 	    id: number;
 	    title: string;
 	    description: string;
+	    chatBackend: string;
 	    lastOpenTime: time.Time;
 	    createdAt: time.Time;
 	    updatedAt: time.Time;
+	    archivedAt?: time.Time;
+	    deletedAt: gorm.DeletedAt;
 	
 	    static createFrom(source: any = {}) {
 	        return new Workspace(source);
@@ -17,9 +57,12 @@ export namespace main {
 	        this.id = source["id"];
 	        this.title = source["title"];
 	        this.description = source["description"];
+	        this.chatBackend = source["chatBackend"];
 	        this.lastOpenTime = this.convertValues(source["lastOpenTime"], time.Time);
 	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
 	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	        this.archivedAt = this.convertValues(source["archivedAt"], time.Time);
+	        this.deletedAt = this.convertValues(source["deletedAt"], gorm.DeletedAt);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -40,13 +83,50 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class Citation {
+	    index: number;
+	    knowledgeBaseId: number;
+	    fileName: string;
+	    chunkId: number;
+	    ordinal: number;
+	    offset: number;
+	    length: number;
+	    score: number;
+	    snippet: string;
+	    cited: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Citation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.knowledgeBaseId = source["knowledgeBaseId"];
+	        this.fileName = source["fileName"];
+	        this.chunkId = source["chunkId"];
+	        this.ordinal = source["ordinal"];
+	        this.offset = source["offset"];
+	        this.length = source["length"];
+	        this.score = source["score"];
+	        this.snippet = source["snippet"];
+	        this.cited = source["cited"];
+	    }
+	}
 	export class AIChatMessage {
 	    id: number;
 	    workspaceId: number;
+	    sessionId?: number;
 	    by: string;
 	    text: string;
 	    createdAt: time.Time;
 	    updatedAt: time.Time;
+	    model?: string;
+	    latencyMs?: number;
+	    promptTokens?: number;
+	    completionTokens?: number;
+	    replyToId?: number;
+	    citations?: Citation[];
 	    workspace?: Workspace;
 	
 	    static createFrom(source: any = {}) {
@@ -57,11 +137,255 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
 	        this.by = source["by"];
 	        this.text = source["text"];
 	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
 	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	        this.model = source["model"];
+	        this.latencyMs = source["latencyMs"];
+	        this.promptTokens = source["promptTokens"];
+	        this.completionTokens = source["completionTokens"];
+	        this.replyToId = source["replyToId"];
+	        this.citations = this.convertValues(source["citations"], Citation);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ActionItem {
+	    id: number;
+	    workspaceId: number;
+	    sessionId?: number;
+	    text: string;
+	    owner: string;
+	    dueDate?: time.Time;
+	    status: string;
+	    sourceMessageId: string;
+	    sourceText: string;
+	    mentionedAt?: time.Time;
+	    mentionedIn?: number;
+	    mentionText: string;
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	    workspace?: Workspace;
+	
+	    static createFrom(source: any = {}) {
+	        return new ActionItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
+	        this.text = source["text"];
+	        this.owner = source["owner"];
+	        this.dueDate = this.convertValues(source["dueDate"], time.Time);
+	        this.status = source["status"];
+	        this.sourceMessageId = source["sourceMessageId"];
+	        this.sourceText = source["sourceText"];
+	        this.mentionedAt = this.convertValues(source["mentionedAt"], time.Time);
+	        this.mentionedIn = source["mentionedIn"];
+	        this.mentionText = source["mentionText"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AssistantCommandInfo {
+	    name: string;
+	    usage: string;
+	    description: string;
+	    hotkey: string;
+	    hotkeySetting: string;
+	    hotkeyError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssistantCommandInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.usage = source["usage"];
+	        this.description = source["description"];
+	        this.hotkey = source["hotkey"];
+	        this.hotkeySetting = source["hotkeySetting"];
+	        this.hotkeyError = source["hotkeyError"];
+	    }
+	}
+	export class ProviderCapabilities {
+	    streaming: boolean;
+	    cancellation: boolean;
+	    modelListing: boolean;
+	    tokenCounts: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProviderCapabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.streaming = source["streaming"];
+	        this.cancellation = source["cancellation"];
+	        this.modelListing = source["modelListing"];
+	        this.tokenCounts = source["tokenCounts"];
+	    }
+	}
+	export class ChatProviderInfo {
+	    name: string;
+	    capabilities: ProviderCapabilities;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatProviderInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.capabilities = this.convertValues(source["capabilities"], ProviderCapabilities);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Decision {
+	    id: number;
+	    workspaceId: number;
+	    sessionId?: number;
+	    text: string;
+	    sourceMessageId: string;
+	    sourceText: string;
+	    createdAt: time.Time;
+	    workspace?: Workspace;
+	
+	    static createFrom(source: any = {}) {
+	        return new Decision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
+	        this.text = source["text"];
+	        this.sourceMessageId = source["sourceMessageId"];
+	        this.sourceText = source["sourceText"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiffLine {
+	    op: string;
+	    text: string;
+	    oldLine: number;
+	    newLine: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.text = source["text"];
+	        this.oldLine = source["oldLine"];
+	        this.newLine = source["newLine"];
+	    }
+	}
+	export class ImportReport {
+	    workspace?: Workspace;
+	    sessions: number;
+	    transcripts: number;
+	    chatMessages: number;
+	    hasNotes: boolean;
+	    knowledgeImported: number;
+	    knowledgeDeduplicated: number;
+	    conflicts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	        this.sessions = source["sessions"];
+	        this.transcripts = source["transcripts"];
+	        this.chatMessages = source["chatMessages"];
+	        this.hasNotes = source["hasNotes"];
+	        this.knowledgeImported = source["knowledgeImported"];
+	        this.knowledgeDeduplicated = source["knowledgeDeduplicated"];
+	        this.conflicts = source["conflicts"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -88,6 +412,7 @@ export namespace main {
 	    type: string;
 	    oneLineSummary: string;
 	    fullSummary: string;
+	    indexedAt?: time.Time;
 	    createdAt: time.Time;
 	    updatedAt: time.Time;
 	
@@ -102,6 +427,7 @@ export namespace main {
 	        this.type = source["type"];
 	        this.oneLineSummary = source["oneLineSummary"];
 	        this.fullSummary = source["fullSummary"];
+	        this.indexedAt = this.convertValues(source["indexedAt"], time.Time);
 	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
 	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
 	    }
@@ -124,26 +450,753 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class MeetingNotes {
+	export class KnowledgeChunk {
 	    id: number;
-	    workspaceId: number;
+	    knowledgeBaseId: number;
+	    ordinal: number;
+	    offset: number;
 	    text: string;
+	    embeddingModel: string;
 	    createdAt: time.Time;
-	    updatedAt: time.Time;
-	    workspace?: Workspace;
 	
 	    static createFrom(source: any = {}) {
-	        return new MeetingNotes(source);
+	        return new KnowledgeChunk(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.workspaceId = source["workspaceId"];
+	        this.knowledgeBaseId = source["knowledgeBaseId"];
+	        this.ordinal = source["ordinal"];
+	        this.offset = source["offset"];
 	        this.text = source["text"];
+	        this.embeddingModel = source["embeddingModel"];
 	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
-	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
-	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class KnowledgeSearchResult {
+	    chunkId: number;
+	    knowledgeBaseId: number;
+	    fileName: string;
+	    ordinal: number;
+	    offset: number;
+	    text: string;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new KnowledgeSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chunkId = source["chunkId"];
+	        this.knowledgeBaseId = source["knowledgeBaseId"];
+	        this.fileName = source["fileName"];
+	        this.ordinal = source["ordinal"];
+	        this.offset = source["offset"];
+	        this.text = source["text"];
+	        this.score = source["score"];
+	    }
+	}
+	export class LiveNotesStatus {
+	    sessionId: number;
+	    notesId: number;
+	    running: boolean;
+	    inFlight: boolean;
+	    pendingLines: number;
+	    interval: number;
+	    updates: number;
+	    lastUpdate?: time.Time;
+	    lastError: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LiveNotesStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.notesId = source["notesId"];
+	        this.running = source["running"];
+	        this.inFlight = source["inFlight"];
+	        this.pendingLines = source["pendingLines"];
+	        this.interval = source["interval"];
+	        this.updates = source["updates"];
+	        this.lastUpdate = this.convertValues(source["lastUpdate"], time.Time);
+	        this.lastError = source["lastError"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MeetingExtraction {
+	    sessionId: number;
+	    actionItems: ActionItem[];
+	    decisions: Decision[];
+	    questions: OpenQuestion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingExtraction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.actionItems = this.convertValues(source["actionItems"], ActionItem);
+	        this.decisions = this.convertValues(source["decisions"], Decision);
+	        this.questions = this.convertValues(source["questions"], OpenQuestion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MeetingNotes {
+	    id: number;
+	    workspaceId: number;
+	    text: string;
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	    workspace?: Workspace;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingNotes(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.text = source["text"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MeetingNotesRevision {
+	    id: number;
+	    notesId: number;
+	    workspaceId: number;
+	    sessionId?: number;
+	    author: string;
+	    text: string;
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingNotesRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.notesId = source["notesId"];
+	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
+	        this.author = source["author"];
+	        this.text = source["text"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MeetingSummary {
+	    id: number;
+	    workspaceId: number;
+	    sessionId: number;
+	    templateId?: number;
+	    templateName: string;
+	    templateVersion: number;
+	    promptVersion: number;
+	    model: string;
+	    chunks: number;
+	    text: string;
+	    createdAt: time.Time;
+	    workspace?: Workspace;
+	
+	    static createFrom(source: any = {}) {
+	        return new MeetingSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
+	        this.templateId = source["templateId"];
+	        this.templateName = source["templateName"];
+	        this.templateVersion = source["templateVersion"];
+	        this.promptVersion = source["promptVersion"];
+	        this.model = source["model"];
+	        this.chunks = source["chunks"];
+	        this.text = source["text"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotesDiff {
+	    fromId: number;
+	    toId: number;
+	    added: number;
+	    removed: number;
+	    lines: DiffLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotesDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fromId = source["fromId"];
+	        this.toId = source["toId"];
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	        this.lines = this.convertValues(source["lines"], DiffLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotesExportOptions {
+	    includeActionItems: boolean;
+	    includeTranscript: boolean;
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotesExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.includeActionItems = source["includeActionItems"];
+	        this.includeTranscript = source["includeTranscript"];
+	        this.path = source["path"];
+	    }
+	}
+	export class NotesHunk {
+	    index: number;
+	    start: number;
+	    end: number;
+	    current: string[];
+	    proposed: string[];
+	    conflict: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotesHunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.current = source["current"];
+	        this.proposed = source["proposed"];
+	        this.conflict = source["conflict"];
+	    }
+	}
+	export class NotesPatch {
+	    id: string;
+	    notesId: number;
+	    hunks: NotesHunk[];
+	    conflicts: number;
+	    createdAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotesPatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.notesId = source["notesId"];
+	        this.hunks = this.convertValues(source["hunks"], NotesHunk);
+	        this.conflicts = source["conflicts"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OpenQuestion {
+	    id: number;
+	    workspaceId: number;
+	    sessionId?: number;
+	    text: string;
+	    askedBy: string;
+	    sourceMessageId: string;
+	    sourceText: string;
+	    createdAt: time.Time;
+	    workspace?: Workspace;
+	
+	    static createFrom(source: any = {}) {
+	        return new OpenQuestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
+	        this.text = source["text"];
+	        this.askedBy = source["askedBy"];
+	        this.sourceMessageId = source["sourceMessageId"];
+	        this.sourceText = source["sourceText"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OrphanReport {
+	    table: string;
+	    column: string;
+	    parent: string;
+	    count: number;
+	    ids: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OrphanReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.column = source["column"];
+	        this.parent = source["parent"];
+	        this.count = source["count"];
+	        this.ids = source["ids"];
+	    }
+	}
+	
+	export class PromptTemplate {
+	    id: number;
+	    name: string;
+	    kind: string;
+	    description: string;
+	    body: string;
+	    builtIn: boolean;
+	    version: number;
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.description = source["description"];
+	        this.body = source["body"];
+	        this.builtIn = source["builtIn"];
+	        this.version = source["version"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptVariable {
+	    name: string;
+	    description: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	    }
+	}
+	export class RepairReport {
+	    orphans: OrphanReport[];
+	    total: number;
+	    removed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepairReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.orphans = this.convertValues(source["orphans"], OrphanReport);
+	        this.total = source["total"];
+	        this.removed = source["removed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchFilters {
+	    workspaceId: number;
+	    types: string[];
+	    from: time.Time;
+	    to: time.Time;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchFilters(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.workspaceId = source["workspaceId"];
+	        this.types = source["types"];
+	        this.from = this.convertValues(source["from"], time.Time);
+	        this.to = this.convertValues(source["to"], time.Time);
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchResult {
+	    type: string;
+	    id: number;
+	    workspaceId: number;
+	    workspaceTitle: string;
+	    title: string;
+	    snippet: string;
+	    timestamp: time.Time;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.workspaceTitle = source["workspaceTitle"];
+	        this.title = source["title"];
+	        this.snippet = source["snippet"];
+	        this.timestamp = this.convertValues(source["timestamp"], time.Time);
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionPause {
+	    id: number;
+	    sessionId: number;
+	    pausedAt: time.Time;
+	    resumedAt?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionPause(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sessionId = source["sessionId"];
+	        this.pausedAt = this.convertValues(source["pausedAt"], time.Time);
+	        this.resumedAt = this.convertValues(source["resumedAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Session {
+	    id: number;
+	    workspaceId: number;
+	    title: string;
+	    source: string;
+	    status: string;
+	    startTime: time.Time;
+	    endTime?: time.Time;
+	    participants: string[];
+	    createdAt: time.Time;
+	    updatedAt: time.Time;
+	    pauses: SessionPause[];
+	    workspace?: Workspace;
+	
+	    static createFrom(source: any = {}) {
+	        return new Session(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.title = source["title"];
+	        this.source = source["source"];
+	        this.status = source["status"];
+	        this.startTime = this.convertValues(source["startTime"], time.Time);
+	        this.endTime = this.convertValues(source["endTime"], time.Time);
+	        this.participants = source["participants"];
+	        this.createdAt = this.convertValues(source["createdAt"], time.Time);
+	        this.updatedAt = this.convertValues(source["updatedAt"], time.Time);
+	        this.pauses = this.convertValues(source["pauses"], SessionPause);
+	        this.workspace = this.convertValues(source["workspace"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TranscriptExportOptions {
+	    sessionId?: number;
+	    from: time.Time;
+	    to: time.Time;
+	    speakers: string[];
+	    mergeConsecutive: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscriptExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.from = this.convertValues(source["from"], time.Time);
+	        this.to = this.convertValues(source["to"], time.Time);
+	        this.speakers = source["speakers"];
+	        this.mergeConsecutive = source["mergeConsecutive"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -168,6 +1221,7 @@ export namespace main {
 	    id: number;
 	    messageId: string;
 	    workspaceId: number;
+	    sessionId?: number;
 	    text: string;
 	    speaker: string;
 	    timestamp: time.Time;
@@ -186,6 +1240,7 @@ export namespace main {
 	        this.id = source["id"];
 	        this.messageId = source["messageId"];
 	        this.workspaceId = source["workspaceId"];
+	        this.sessionId = source["sessionId"];
 	        this.text = source["text"];
 	        this.speaker = source["speaker"];
 	        this.timestamp = this.convertValues(source["timestamp"], time.Time);
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	goruntime "runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/menu/keys"
+)
+
+// globalHotkey is a key combination registered with the operating system, so it runs its
+// command while another app, such as the meeting, has focus
+type globalHotkey struct {
+	Key   string // as parsed by keys.Parse, e.g. "r", "f5" or "page up"
+	Ctrl  bool
+	Alt   bool // Option on macOS
+	Shift bool
+	Cmd   bool // Command on macOS, only used there
+}
+
+func (h globalHotkey) String() string {
+	var parts []string
+	for _, modifier := range []struct {
+		on   bool
+		name string
+	}{{h.Cmd, "cmd"}, {h.Ctrl, "ctrl"}, {h.Alt, "alt"}, {h.Shift, "shift"}} {
+		if modifier.on {
+			parts = append(parts, modifier.name)
+		}
+	}
+	return strings.Join(append(parts, h.Key), "+")
+}
+
+// globalHotkeyPunctuation are the keys besides letters and digits that have the same place
+// on the common keyboard layouts of every platform
+const globalHotkeyPunctuation = "`-=[]\\;',./"
+
+// parseGlobalHotkey parses an accelerator such as "cmdorctrl+optionoralt+shift+r". Apart from
+// function keys, a global hotkey needs a modifier other than shift, so it doesn't swallow a
+// key that is typed into other apps.
+func parseGlobalHotkey(value string) (globalHotkey, error) {
+	accelerator, err := keys.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return globalHotkey{}, err
+	}
+	if !globalHotkeyKey(accelerator.Key) {
+		return globalHotkey{}, fmt.Errorf("'%s' can't be used in a global hotkey", accelerator.Key)
+	}
+
+	hotkey := globalHotkey{Key: accelerator.Key}
+	for _, modifier := range accelerator.Modifiers {
+		switch modifier {
+		case keys.CmdOrCtrlKey:
+			if goruntime.GOOS == "darwin" {
+				hotkey.Cmd = true
+			} else {
+				hotkey.Ctrl = true
+			}
+		case keys.ControlKey:
+			hotkey.Ctrl = true
+		case keys.OptionOrAltKey:
+			hotkey.Alt = true
+		case keys.ShiftKey:
+			hotkey.Shift = true
+		}
+	}
+	functionKey := len(hotkey.Key) > 1 && hotkey.Key[0] == 'f'
+	if !hotkey.Ctrl && !hotkey.Alt && !hotkey.Cmd && !functionKey {
+		return globalHotkey{}, fmt.Errorf("a global hotkey needs cmdorctrl, ctrl or optionoralt unless it is a function key")
+	}
+	return hotkey, nil
+}
+
+// globalHotkeyKey reports whether every platform can register key: letters, digits, the
+// punctuation in globalHotkeyPunctuation, F1 to F20 and the named navigation keys
+func globalHotkeyKey(key string) bool {
+	if len(key) == 1 {
+		c := key[0]
+		return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.IndexByte(globalHotkeyPunctuation, c) >= 0
+	}
+	if number, ok := strings.CutPrefix(key, "f"); ok {
+		n, err := strconv.Atoi(number)
+		return err == nil && n >= 1 && n <= 20
+	}
+	switch key {
+	case "backspace", "tab", "return", "enter", "escape", "space", "delete",
+		"left", "right", "up", "down", "home", "end", "page up", "page down":
+		return true
+	}
+	return false
+}
+
+// globalHotkeys holds the hotkeys registered for the assistant commands
+var globalHotkeys = struct {
+	sync.Mutex
+	stop   func()            // releases the registered hotkeys, nil if none are
+	errors map[string]string // why a command's hotkey isn't registered, by hotkey setting
+}{errors: map[string]string{}}
+
+// registerGlobalHotkeys registers the hotkeys saved in the commands' hotkey settings with the
+// operating system, replacing the ones registered before. A hotkey another app holds already
+// is skipped, the reason is listed by ListAssistantCommands.
+func (a *App) registerGlobalHotkeys() {
+	globalHotkeys.Lock()
+	defer globalHotkeys.Unlock()
+	if globalHotkeys.stop != nil {
+		globalHotkeys.stop()
+		globalHotkeys.stop = nil
+	}
+	globalHotkeys.errors = map[string]string{}
+
+	var hotkeys []globalHotkey
+	var commands []*assistantCommand
+	for _, command := range assistantCommands {
+		value := strings.TrimSpace(GetSetting(command.HotkeySetting))
+		if value == "" {
+			continue
+		}
+		hotkey, err := parseGlobalHotkey(value)
+		if err != nil {
+			log.Printf("Ignoring invalid hotkey %q for /%s: %v", value, command.Name, err)
+			globalHotkeys.errors[command.HotkeySetting] = err.Error()
+			continue
+		}
+		if taken := slices.Index(hotkeys, hotkey); taken >= 0 {
+			globalHotkeys.errors[command.HotkeySetting] = fmt.Sprintf("%s is the hotkey of /%s already", hotkey, commands[taken].Name)
+			continue
+		}
+		hotkeys = append(hotkeys, hotkey)
+		commands = append(commands, command)
+	}
+	if len(hotkeys) == 0 {
+		return
+	}
+
+	stop, errs := listenGlobalHotkeys(hotkeys, func(index int) {
+		// Called on the thread listening for hotkeys, which must not wait for the command
+		go a.runAssistantHotkey(commands[index])
+	})
+	globalHotkeys.stop = stop
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Failed to register hotkey %s for /%s: %v", hotkeys[i], commands[i].Name, err)
+			globalHotkeys.errors[commands[i].HotkeySetting] = err.Error()
+		}
+	}
+}
+
+// globalHotkeyError returns why the hotkey in setting isn't registered, empty if it is
+func globalHotkeyError(setting string) string {
+	globalHotkeys.Lock()
+	defer globalHotkeys.Unlock()
+	return globalHotkeys.errors[setting]
+}
@@ -0,0 +1,56 @@
+#include <Carbon/Carbon.h>
+#include <dispatch/dispatch.h>
+#include <pthread.h>
+
+#include "global-hotkeys_darwin.h"
+
+extern void globalHotkeyPressed(UInt32 id);
+
+static OSStatus handleHotkey(EventHandlerCallRef next, EventRef event, void *data) {
+	EventHotKeyID hotkeyID;
+	OSStatus status = GetEventParameter(event, kEventParamDirectObject, typeEventHotKeyID, NULL, sizeof(hotkeyID), NULL, &hotkeyID);
+	if (status != noErr) {
+		return status;
+	}
+	globalHotkeyPressed(hotkeyID.id);
+	return noErr;
+}
+
+// registerOnMainThread registers a hotkey, installing the handler of hotkey events the first
+// time. Carbon events are delivered by the main run loop, so both happen on the main thread.
+static void registerOnMainThread(void *context) {
+	static int handlerInstalled;
+	hotkeyRegistration *registration = context;
+
+	if (!handlerInstalled) {
+		EventTypeSpec eventType = {kEventClassKeyboard, kEventHotKeyPressed};
+		registration->status = InstallApplicationEventHandler(NewEventHandlerUPP(handleHotkey), 1, &eventType, NULL, NULL);
+		if (registration->status != noErr) {
+			return;
+		}
+		handlerInstalled = 1;
+	}
+	EventHotKeyID hotkeyID = {0x796d736e, registration->id}; // 'ymsn'
+	registration->status = RegisterEventHotKey(registration->keyCode, registration->modifiers, hotkeyID,
+		GetApplicationEventTarget(), 0, &registration->ref);
+}
+
+static void unregisterOnMainThread(void *ref) {
+	UnregisterEventHotKey(ref);
+}
+
+static void onMainThread(void *context, dispatch_function_t work) {
+	if (pthread_main_np()) {
+		work(context);
+	} else {
+		dispatch_sync_f(dispatch_get_main_queue(), context, work);
+	}
+}
+
+void registerHotkey(hotkeyRegistration *registration) {
+	onMainThread(registration, registerOnMainThread);
+}
+
+void unregisterHotkey(EventHotKeyRef ref) {
+	onMainThread(ref, unregisterOnMainThread);
+}
@@ -0,0 +1,137 @@
+package main
+
+/*
+#cgo LDFLAGS: -framework Carbon
+#include "global-hotkeys_darwin.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// macKeyCodes maps the keys of a global hotkey to their virtual key code. Letters, digits and
+// punctuation are the codes of their place on an ANSI keyboard.
+var macKeyCodes = map[string]C.UInt32{
+	"a": C.kVK_ANSI_A, "b": C.kVK_ANSI_B, "c": C.kVK_ANSI_C, "d": C.kVK_ANSI_D, "e": C.kVK_ANSI_E,
+	"f": C.kVK_ANSI_F, "g": C.kVK_ANSI_G, "h": C.kVK_ANSI_H, "i": C.kVK_ANSI_I, "j": C.kVK_ANSI_J,
+	"k": C.kVK_ANSI_K, "l": C.kVK_ANSI_L, "m": C.kVK_ANSI_M, "n": C.kVK_ANSI_N, "o": C.kVK_ANSI_O,
+	"p": C.kVK_ANSI_P, "q": C.kVK_ANSI_Q, "r": C.kVK_ANSI_R, "s": C.kVK_ANSI_S, "t": C.kVK_ANSI_T,
+	"u": C.kVK_ANSI_U, "v": C.kVK_ANSI_V, "w": C.kVK_ANSI_W, "x": C.kVK_ANSI_X, "y": C.kVK_ANSI_Y,
+	"z": C.kVK_ANSI_Z,
+	"0": C.kVK_ANSI_0, "1": C.kVK_ANSI_1, "2": C.kVK_ANSI_2, "3": C.kVK_ANSI_3, "4": C.kVK_ANSI_4,
+	"5": C.kVK_ANSI_5, "6": C.kVK_ANSI_6, "7": C.kVK_ANSI_7, "8": C.kVK_ANSI_8, "9": C.kVK_ANSI_9,
+	"`": C.kVK_ANSI_Grave, "-": C.kVK_ANSI_Minus, "=": C.kVK_ANSI_Equal, "[": C.kVK_ANSI_LeftBracket,
+	"]": C.kVK_ANSI_RightBracket, "\\": C.kVK_ANSI_Backslash, ";": C.kVK_ANSI_Semicolon,
+	"'": C.kVK_ANSI_Quote, ",": C.kVK_ANSI_Comma, ".": C.kVK_ANSI_Period, "/": C.kVK_ANSI_Slash,
+
+	"backspace": C.kVK_Delete,
+	"tab":       C.kVK_Tab,
+	"return":    C.kVK_Return,
+	"enter":     C.kVK_Return,
+	"escape":    C.kVK_Escape,
+	"space":     C.kVK_Space,
+	"delete":    C.kVK_ForwardDelete,
+	"left":      C.kVK_LeftArrow,
+	"right":     C.kVK_RightArrow,
+	"up":        C.kVK_UpArrow,
+	"down":      C.kVK_DownArrow,
+	"home":      C.kVK_Home,
+	"end":       C.kVK_End,
+	"page up":   C.kVK_PageUp,
+	"page down": C.kVK_PageDown,
+}
+
+// macFunctionKeys are the key codes of F1 to F20, which aren't in order
+var macFunctionKeys = []C.UInt32{
+	C.kVK_F1, C.kVK_F2, C.kVK_F3, C.kVK_F4, C.kVK_F5, C.kVK_F6, C.kVK_F7, C.kVK_F8, C.kVK_F9, C.kVK_F10,
+	C.kVK_F11, C.kVK_F12, C.kVK_F13, C.kVK_F14, C.kVK_F15, C.kVK_F16, C.kVK_F17, C.kVK_F18, C.kVK_F19, C.kVK_F20,
+}
+
+func macKeyCode(key string) C.UInt32 {
+	if code, ok := macKeyCodes[key]; ok {
+		return code
+	}
+	n, _ := strconv.Atoi(key[1:])
+	return macFunctionKeys[n-1]
+}
+
+func macModifiers(hotkey globalHotkey) C.UInt32 {
+	var modifiers C.UInt32
+	if hotkey.Cmd {
+		modifiers |= C.cmdKey
+	}
+	if hotkey.Ctrl {
+		modifiers |= C.controlKey
+	}
+	if hotkey.Alt {
+		modifiers |= C.optionKey
+	}
+	if hotkey.Shift {
+		modifiers |= C.shiftKey
+	}
+	return modifiers
+}
+
+// macHotkeys are the callbacks of the registered hotkeys by ID. IDs aren't reused, so a
+// hotkey event delivered after its hotkey was unregistered is ignored.
+var macHotkeys = struct {
+	sync.Mutex
+	lastID    C.UInt32
+	callbacks map[C.UInt32]func()
+}{callbacks: map[C.UInt32]func(){}}
+
+//export globalHotkeyPressed
+func globalHotkeyPressed(id C.UInt32) {
+	macHotkeys.Lock()
+	callback := macHotkeys.callbacks[id]
+	macHotkeys.Unlock()
+	if callback != nil {
+		callback()
+	}
+}
+
+// listenGlobalHotkeys registers the hotkeys with Carbon's RegisterEventHotKey and calls
+// pressed, on the main thread, with the index of the hotkey pressed. errs holds why a hotkey
+// couldn't be registered at its index.
+func listenGlobalHotkeys(hotkeys []globalHotkey, pressed func(index int)) (stop func(), errs []error) {
+	errs = make([]error, len(hotkeys))
+	var ids []C.UInt32
+	var refs []C.EventHotKeyRef
+	for i, hotkey := range hotkeys {
+		macHotkeys.Lock()
+		macHotkeys.lastID++
+		id := macHotkeys.lastID
+		macHotkeys.callbacks[id] = func() { pressed(i) }
+		macHotkeys.Unlock()
+
+		registration := C.hotkeyRegistration{keyCode: macKeyCode(hotkey.Key), modifiers: macModifiers(hotkey), id: id}
+		C.registerHotkey(&registration)
+		if registration.status != C.noErr {
+			if registration.status == C.eventHotKeyExistsErr {
+				errs[i] = fmt.Errorf("%s is used by another app", hotkey)
+			} else {
+				errs[i] = fmt.Errorf("RegisterEventHotKey failed with status %d", registration.status)
+			}
+			macHotkeys.Lock()
+			delete(macHotkeys.callbacks, id)
+			macHotkeys.Unlock()
+			continue
+		}
+		ids = append(ids, id)
+		refs = append(refs, registration.ref)
+	}
+
+	return func() {
+		for _, ref := range refs {
+			C.unregisterHotkey(ref)
+		}
+		macHotkeys.Lock()
+		for _, id := range ids {
+			delete(macHotkeys.callbacks, id)
+		}
+		macHotkeys.Unlock()
+	}, errs
+}
@@ -0,0 +1,14 @@
+#pragma once
+
+#include <Carbon/Carbon.h>
+
+typedef struct {
+	UInt32 keyCode;
+	UInt32 modifiers;
+	UInt32 id;
+	EventHotKeyRef ref;
+	OSStatus status;
+} hotkeyRegistration;
+
+void registerHotkey(hotkeyRegistration *registration);
+void unregisterHotkey(EventHotKeyRef ref);
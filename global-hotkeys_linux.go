@@ -0,0 +1,180 @@
+package main
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+#include <X11/keysym.h>
+
+static int grabFailed;
+
+static int recordGrabError(Display *display, XErrorEvent *event) {
+	grabFailed = 1;
+	return 0;
+}
+
+// Caps Lock and Num Lock are part of the event state, so a key is grabbed with every
+// combination of them
+static unsigned int lockMasks[] = {0, LockMask, Mod2Mask, LockMask | Mod2Mask};
+
+// grabKey grabs keycode with modifiers on the root window. It returns 0 when another client
+// holds the combination already. Xlib's default error handler would exit the process, so
+// errors are recorded by a handler of our own while grabbing.
+static int grabKey(Display *display, int keycode, unsigned int modifiers) {
+	XErrorHandler previous;
+	int i;
+
+	XSync(display, False);
+	grabFailed = 0;
+	previous = XSetErrorHandler(recordGrabError);
+	for (i = 0; i < 4; i++) {
+		XGrabKey(display, keycode, modifiers | lockMasks[i], DefaultRootWindow(display), False, GrabModeAsync, GrabModeAsync);
+	}
+	XSync(display, False);
+	XSetErrorHandler(previous);
+
+	if (grabFailed) {
+		for (i = 0; i < 4; i++) {
+			XUngrabKey(display, keycode, modifiers | lockMasks[i], DefaultRootWindow(display));
+		}
+		XSync(display, False);
+		return 0;
+	}
+	return 1;
+}
+
+// nextKeyPress reads the pending events until a key press, returning 0 when there is none
+static int nextKeyPress(Display *display, unsigned int *keycode, unsigned int *modifiers) {
+	XEvent event;
+	while (XPending(display) > 0) {
+		XNextEvent(display, &event);
+		if (event.type == KeyPress) {
+			*keycode = event.xkey.keycode;
+			*modifiers = event.xkey.state & (ShiftMask | ControlMask | Mod1Mask | Mod4Mask);
+			return 1;
+		}
+	}
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// x11HotkeyPoll is how often the X server connection is checked for pressed hotkeys
+const x11HotkeyPoll = 50 * time.Millisecond
+
+// x11Keysyms maps the named keys of a global hotkey to their X keysym. Letters, digits and
+// punctuation are their Latin-1 code.
+var x11Keysyms = map[string]C.KeySym{
+	"backspace": C.XK_BackSpace,
+	"tab":       C.XK_Tab,
+	"return":    C.XK_Return,
+	"enter":     C.XK_Return,
+	"escape":    C.XK_Escape,
+	"space":     C.XK_space,
+	"delete":    C.XK_Delete,
+	"left":      C.XK_Left,
+	"right":     C.XK_Right,
+	"up":        C.XK_Up,
+	"down":      C.XK_Down,
+	"home":      C.XK_Home,
+	"end":       C.XK_End,
+	"page up":   C.XK_Prior,
+	"page down": C.XK_Next,
+}
+
+func x11Keysym(key string) C.KeySym {
+	if keysym, ok := x11Keysyms[key]; ok {
+		return keysym
+	}
+	if len(key) == 1 {
+		return C.KeySym(key[0])
+	}
+	n, _ := strconv.Atoi(key[1:])
+	return C.XK_F1 + C.KeySym(n-1)
+}
+
+func x11Modifiers(hotkey globalHotkey) C.uint {
+	var modifiers C.uint
+	if hotkey.Ctrl {
+		modifiers |= C.ControlMask
+	}
+	if hotkey.Alt {
+		modifiers |= C.Mod1Mask
+	}
+	if hotkey.Shift {
+		modifiers |= C.ShiftMask
+	}
+	if hotkey.Cmd {
+		modifiers |= C.Mod4Mask
+	}
+	return modifiers
+}
+
+// listenGlobalHotkeys grabs the hotkeys on the X server through a connection of its own and
+// calls pressed with the index of the hotkey pressed. errs holds why a hotkey couldn't be
+// grabbed at its index. Under Wayland the grabs only see keys typed into X11 apps.
+func listenGlobalHotkeys(hotkeys []globalHotkey, pressed func(index int)) (stop func(), errs []error) {
+	errs = make([]error, len(hotkeys))
+	done := make(chan struct{})
+	ready := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		display := C.XOpenDisplay(nil)
+		if display == nil {
+			for i := range errs {
+				errs[i] = fmt.Errorf("can't connect to the X server")
+			}
+			close(ready)
+			return
+		}
+		// Closing the connection releases its grabs
+		defer C.XCloseDisplay(display)
+
+		type combination struct{ keycode, modifiers C.uint }
+		grabbed := map[combination]int{}
+		for i, hotkey := range hotkeys {
+			keycode := C.XKeysymToKeycode(display, x11Keysym(hotkey.Key))
+			if keycode == 0 {
+				errs[i] = fmt.Errorf("the keyboard has no %s key", hotkey.Key)
+				continue
+			}
+			modifiers := x11Modifiers(hotkey)
+			if C.grabKey(display, C.int(keycode), modifiers) == 0 {
+				errs[i] = fmt.Errorf("%s is used by another app", hotkey)
+				continue
+			}
+			grabbed[combination{C.uint(keycode), modifiers}] = i
+		}
+		close(ready)
+
+		ticker := time.NewTicker(x11HotkeyPoll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				var pressedKey combination
+				for C.nextKeyPress(display, &pressedKey.keycode, &pressedKey.modifiers) != 0 {
+					if index, ok := grabbed[pressedKey]; ok {
+						pressed(index)
+					}
+				}
+			}
+		}
+	}()
+
+	<-ready
+	// Waits for the connection to close, so the hotkeys can be grabbed again right away
+	return func() {
+		close(done)
+		<-stopped
+	}, errs
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	goruntime "runtime"
+	"testing"
+)
+
+func TestParseGlobalHotkey(t *testing.T) {
+	cmdOrCtrl := globalHotkey{Ctrl: true}
+	if goruntime.GOOS == "darwin" {
+		cmdOrCtrl = globalHotkey{Cmd: true}
+	}
+
+	cases := []struct {
+		value string
+		want  globalHotkey
+		valid bool
+	}{
+		{"cmdorctrl+optionoralt+shift+r", globalHotkey{Key: "r", Ctrl: cmdOrCtrl.Ctrl, Cmd: cmdOrCtrl.Cmd, Alt: true, Shift: true}, true},
+		{" ctrl+Shift+D ", globalHotkey{Key: "d", Ctrl: true, Shift: true}, true},
+		{"optionoralt+page up", globalHotkey{Key: "page up", Alt: true}, true},
+		{"ctrl+/", globalHotkey{Key: "/", Ctrl: true}, true},
+		{"f9", globalHotkey{Key: "f9"}, true},
+		{"shift+f20", globalHotkey{Key: "f20", Shift: true}, true},
+		{"r", globalHotkey{}, false},       // would swallow typing
+		{"shift+r", globalHotkey{}, false}, // as would shift
+		{"ctrl+f21", globalHotkey{}, false},
+		{"ctrl+numlock", globalHotkey{}, false},
+		{"ctrl+plus", globalHotkey{}, false}, // needs shift on most layouts
+		{"ctrl+é", globalHotkey{}, false},
+		{"hyper+r", globalHotkey{}, false},
+	}
+	for _, c := range cases {
+		got, err := parseGlobalHotkey(c.value)
+		if (err == nil) != c.valid {
+			t.Errorf("parseGlobalHotkey(%q) error = %v, want valid %v", c.value, err, c.valid)
+			continue
+		}
+		if c.valid && got != c.want {
+			t.Errorf("parseGlobalHotkey(%q) = %+v, want %+v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestGlobalHotkeyString(t *testing.T) {
+	hotkey := globalHotkey{Key: "page up", Ctrl: true, Shift: true}
+	if got := hotkey.String(); got != "ctrl+shift+page up" {
+		t.Errorf("String() = %q", got)
+	}
+}
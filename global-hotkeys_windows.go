@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	goruntime "runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                = syscall.NewLazyDLL("user32.dll")
+	procRegisterHotKey    = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey  = user32.NewProc("UnregisterHotKey")
+	procGetMessage        = user32.NewProc("GetMessageW")
+	procPeekMessage       = user32.NewProc("PeekMessageW")
+	procPostThreadMessage = user32.NewProc("PostThreadMessageW")
+
+	procGetCurrentThreadID = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCurrentThreadId")
+)
+
+const (
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modWin      = 0x0008
+	modNoRepeat = 0x4000
+
+	wmQuit   = 0x0012
+	wmHotkey = 0x0312
+
+	errorHotkeyAlreadyRegistered = syscall.Errno(1409)
+)
+
+// windowsKeys maps the keys of a global hotkey that aren't letters, digits or function keys
+// to their virtual-key code
+var windowsKeys = map[string]uintptr{
+	"backspace": 0x08, // VK_BACK
+	"tab":       0x09, // VK_TAB
+	"return":    0x0D, // VK_RETURN
+	"enter":     0x0D,
+	"escape":    0x1B, // VK_ESCAPE
+	"space":     0x20, // VK_SPACE
+	"page up":   0x21, // VK_PRIOR
+	"page down": 0x22, // VK_NEXT
+	"end":       0x23, // VK_END
+	"home":      0x24, // VK_HOME
+	"left":      0x25, // VK_LEFT
+	"up":        0x26, // VK_UP
+	"right":     0x27, // VK_RIGHT
+	"down":      0x28, // VK_DOWN
+	"delete":    0x2E, // VK_DELETE
+	";":         0xBA, // VK_OEM_1
+	"=":         0xBB, // VK_OEM_PLUS
+	",":         0xBC, // VK_OEM_COMMA
+	"-":         0xBD, // VK_OEM_MINUS
+	".":         0xBE, // VK_OEM_PERIOD
+	"/":         0xBF, // VK_OEM_2
+	"`":         0xC0, // VK_OEM_3
+	"[":         0xDB, // VK_OEM_4
+	"\\":        0xDC, // VK_OEM_5
+	"]":         0xDD, // VK_OEM_6
+	"'":         0xDE, // VK_OEM_7
+}
+
+func windowsVirtualKey(key string) uintptr {
+	if code, ok := windowsKeys[key]; ok {
+		return code
+	}
+	if len(key) == 1 {
+		// Letters and digits are their upper case character
+		c := key[0]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		return uintptr(c)
+	}
+	n, _ := strconv.Atoi(key[1:])
+	return 0x70 + uintptr(n-1) // VK_F1
+}
+
+func windowsModifiers(hotkey globalHotkey) uintptr {
+	modifiers := uintptr(modNoRepeat)
+	if hotkey.Ctrl {
+		modifiers |= modControl
+	}
+	if hotkey.Alt {
+		modifiers |= modAlt
+	}
+	if hotkey.Shift {
+		modifiers |= modShift
+	}
+	if hotkey.Cmd {
+		modifiers |= modWin
+	}
+	return modifiers
+}
+
+// windowsMessage is the MSG structure GetMessage fills in
+type windowsMessage struct {
+	hwnd     uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pt       struct{ x, y int32 }
+	lPrivate uint32
+}
+
+// listenGlobalHotkeys registers the hotkeys with RegisterHotKey and calls pressed with the
+// index of the hotkey pressed. Hotkeys belong to the thread that registered them, which reads
+// its message queue until stop. errs holds why a hotkey couldn't be registered at its index.
+func listenGlobalHotkeys(hotkeys []globalHotkey, pressed func(index int)) (stop func(), errs []error) {
+	errs = make([]error, len(hotkeys))
+	ready := make(chan uintptr)
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		goruntime.LockOSThread()
+		defer goruntime.UnlockOSThread()
+
+		var message windowsMessage
+		// Creates the thread's message queue before stop can post to it
+		procPeekMessage.Call(uintptr(unsafe.Pointer(&message)), 0, 0, 0, 0)
+		threadID, _, _ := procGetCurrentThreadID.Call()
+
+		for i, hotkey := range hotkeys {
+			// IDs start at 1, as 0 isn't a valid one
+			ok, _, err := procRegisterHotKey.Call(0, uintptr(i+1), windowsModifiers(hotkey), windowsVirtualKey(hotkey.Key))
+			if ok == 0 {
+				if errors.Is(err, errorHotkeyAlreadyRegistered) {
+					err = fmt.Errorf("%s is used by another app", hotkey)
+				}
+				errs[i] = err
+			}
+		}
+		ready <- threadID
+
+		for {
+			result, _, _ := procGetMessage.Call(uintptr(unsafe.Pointer(&message)), 0, 0, 0)
+			if int32(result) <= 0 {
+				break
+			}
+			if message.message == wmHotkey {
+				pressed(int(message.wParam) - 1)
+			}
+		}
+		for i := range hotkeys {
+			if errs[i] == nil {
+				procUnregisterHotKey.Call(0, uintptr(i+1))
+			}
+		}
+	}()
+
+	threadID := <-ready
+	// Waits for the hotkeys to be unregistered, so they can be registered again right away
+	return func() {
+		procPostThreadMessage.Call(threadID, wmQuit, 0, 0)
+		<-stopped
+	}, errs
+}
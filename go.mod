@@ -3,8 +3,11 @@ module myproject
 go 1.23
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/gorilla/websocket v1.5.3
 	github.com/wailsapp/wails/v2 v2.10.1
+	github.com/yuin/goldmark v1.7.4
+	golang.org/x/image v0.12.0
 	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.30.0
 )
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// workspaceDependents are the tables whose rows belong to a workspace, children before parents
+var workspaceDependents = []string{
+	"transcript_summaries",
+	"meeting_summaries",
+	"action_items",
+	"decisions",
+	"open_questions",
+	"workspace_prompt_templates",
+	"transcription_records",
+	"ai_chat_messages",
+	"meeting_notes_revisions",
+	"meeting_notes",
+	"sessions",
+}
+
+// deleteWorkspaceRows permanently removes a workspace and everything that belongs to it
+func deleteWorkspaceRows(tx *gorm.DB, workspaceID uint) error {
+	sessions := tx.Table("sessions").Select("id").Where("workspace_id = ?", workspaceID)
+	if err := tx.Exec("DELETE FROM session_pauses WHERE session_id IN (?)", sessions).Error; err != nil {
+		return fmt.Errorf("failed to delete session pauses: %v", err)
+	}
+	for _, table := range workspaceDependents {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE workspace_id = ?", workspaceID).Error; err != nil {
+			return fmt.Errorf("failed to delete %s: %v", table, err)
+		}
+	}
+
+	return tx.Unscoped().Delete(&Workspace{}, workspaceID).Error
+}
+
+// orphanCheck finds rows of Table whose Column points at a missing row of Parent
+type orphanCheck struct {
+	Table  string
+	Column string
+	Parent string
+}
+
+var orphanChecks = []orphanCheck{
+	{"transcription_records", "workspace_id", "workspaces"},
+	{"meeting_notes", "workspace_id", "workspaces"},
+	{"ai_chat_messages", "workspace_id", "workspaces"},
+	{"sessions", "workspace_id", "workspaces"},
+	{"transcript_summaries", "workspace_id", "workspaces"},
+	{"meeting_summaries", "workspace_id", "workspaces"},
+	{"action_items", "workspace_id", "workspaces"},
+	{"decisions", "workspace_id", "workspaces"},
+	{"open_questions", "workspace_id", "workspaces"},
+	{"workspace_prompt_templates", "workspace_id", "workspaces"},
+	{"workspace_prompt_templates", "template_id", "prompt_templates"},
+	{"session_pauses", "session_id", "sessions"},
+	{"meeting_notes_revisions", "notes_id", "meeting_notes"},
+	{"knowledge_chunks", "knowledge_base_id", "knowledge_bases"},
+}
+
+// OrphanReport counts the rows of a table pointing at a missing parent
+type OrphanReport struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Parent string `json:"parent"`
+	Count  int64  `json:"count"`
+	IDs    []uint `json:"ids"` // first few offending rows
+}
+
+// RepairReport is the outcome of RepairDatabase
+type RepairReport struct {
+	Orphans []OrphanReport `json:"orphans"`
+	Total   int64          `json:"total"`
+	Removed bool           `json:"removed"` // false for a dry run
+}
+
+// Number of offending row IDs listed per table in a RepairReport
+const maxReportedOrphanIDs = 20
+
+// RepairDatabase finds rows left behind by deletions made before foreign keys were enforced
+// and, unless dryRun is set, removes them
+func RepairDatabase(dryRun bool) (*RepairReport, error) {
+	report := &RepairReport{Orphans: []OrphanReport{}, Removed: !dryRun}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		// Pauses of an orphaned session become orphans once it is removed, check foreign keys at commit
+		if err := tx.Exec("PRAGMA defer_foreign_keys = ON").Error; err != nil {
+			return err
+		}
+
+		for _, check := range orphanChecks {
+			if !tx.Migrator().HasTable(check.Table) {
+				continue
+			}
+			condition := fmt.Sprintf("%s NOT IN (SELECT id FROM %s)", check.Column, check.Parent)
+
+			var count int64
+			if err := tx.Table(check.Table).Where(condition).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check %s: %v", check.Table, err)
+			}
+			if count == 0 {
+				continue
+			}
+
+			orphans := OrphanReport{Table: check.Table, Column: check.Column, Parent: check.Parent, Count: count}
+			if err := tx.Table(check.Table).Where(condition).Order("id").Limit(maxReportedOrphanIDs).Pluck("id", &orphans.IDs).Error; err != nil {
+				return fmt.Errorf("failed to list orphans in %s: %v", check.Table, err)
+			}
+			if !dryRun {
+				if err := tx.Exec("DELETE FROM " + check.Table + " WHERE " + condition).Error; err != nil {
+					return fmt.Errorf("failed to remove orphans from %s: %v", check.Table, err)
+				}
+			}
+
+			log.Printf("Found %d orphaned rows in %s", count, check.Table)
+			report.Orphans = append(report.Orphans, orphans)
+			report.Total += count
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to repair database: %v", err)
+		return nil, err
+	}
+
+	if report.Total > 0 && !dryRun {
+		knowledgeEmbeddings.invalidate()
+	}
+	return report, nil
+}
+
+// RepairDatabase reports rows orphaned by earlier workspace deletions and removes them unless dryRun is set
+func (a *App) RepairDatabase(dryRun bool) (*RepairReport, error) {
+	return RepairDatabase(dryRun)
+}
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Size of knowledge base chunks, in characters, and the overlap between consecutive chunks
+const (
+	knowledgeChunkSize    = 1200
+	knowledgeChunkOverlap = 200
+)
+
+// Default number of knowledge base chunks retrieved for a chat question
+const defaultRetrievalTopK = 4
+
+// knowledgeBaseDir is where MoveFilesToYumesession copies knowledge base files
+const knowledgeBaseDir = "yumesession/knowledge_base"
+
+// KnowledgeChunk represents an indexed piece of a knowledge base document
+type KnowledgeChunk struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	KnowledgeBaseID uint      `gorm:"not null;index" json:"knowledgeBaseId"`
+	Ordinal         int       `gorm:"not null" json:"ordinal"` // position of the chunk in the document
+	Offset          int       `gorm:"not null" json:"offset"`  // byte offset of the chunk in the extracted text
+	Text            string    `gorm:"type:text" json:"text"`
+	Embedding       []byte    `json:"-"`                           // little-endian float32 vector, see encodeEmbedding
+	EmbeddingModel  string    `gorm:"index" json:"embeddingModel"` // model that produced Embedding
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// Citation points at the knowledge base chunk an answer drew on
+type Citation struct {
+	Index           int     `json:"index"` // the [n] label used in the prompt
+	KnowledgeBaseID uint    `json:"knowledgeBaseId"`
+	FileName        string  `json:"fileName"`
+	ChunkID         uint    `json:"chunkId"`
+	Ordinal         int     `json:"ordinal"`
+	Offset          int     `json:"offset"`
+	Length          int     `json:"length"`
+	Score           float64 `json:"score"`
+	Snippet         string  `json:"snippet"`
+	Cited           bool    `json:"cited"` // whether the answer referred to it
+}
+
+// RetrievedChunk is a chunk selected for a chat question, with its relevance score
+type RetrievedChunk struct {
+	Chunk KnowledgeChunk
+	Item  KnowledgeBase
+	Score float64
+}
+
+// indexingItems tracks knowledge base items currently being indexed. The value is true when
+// the item was indexed again while in progress, and has to be re-run once the current run ends.
+var (
+	indexingMu    sync.Mutex
+	indexingItems = make(map[uint]bool)
+)
+
+// extractDocumentText returns the plain text of a knowledge base item's source.
+// Text files are read directly, other documents are converted by the Python backend.
+// Items the backend cannot convert fall back to their summary; an unreachable
+// backend is an error so the item is indexed again later.
+func extractDocumentText(item KnowledgeBase) (string, error) {
+	if item.UniqueFileName == "" {
+		return item.FullSummary, nil
+	}
+
+	path := filepath.Join(knowledgeBaseDir, item.UniqueFileName)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".md", ".markdown", ".csv":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		return string(data), nil
+	}
+
+	text, err := extractWithBackend(path)
+	if err != nil {
+		if _, unreachable := err.(*url.Error); unreachable {
+			return "", fmt.Errorf("Python backend not accessible at localhost:8000: %v", err)
+		}
+		log.Printf("Failed to extract text from %s, indexing its summary instead: %v", path, err)
+		return item.FullSummary, nil
+	}
+	return text, nil
+}
+
+// extractWithBackend asks the Python backend to convert a document to Markdown
+func extractWithBackend(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(map[string]string{"file_path": absPath})
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Post("http://localhost:8000/extract", "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Text  string `json:"text"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("%s", result.Error)
+	}
+	return result.Text, nil
+}
+
+// textChunk is a piece of a document and its byte offset
+type textChunk struct {
+	Offset int
+	Text   string
+}
+
+// chunkText splits text into overlapping chunks of about size bytes,
+// preferring to break at paragraph, line or word boundaries
+func chunkText(text string, size, overlap int) []textChunk {
+	var chunks []textChunk
+	start := 0
+	for start < len(text) {
+		end := start + size
+		if end >= len(text) {
+			end = len(text)
+		} else {
+			end = chunkBoundary(text, start, end)
+		}
+
+		if chunk := strings.TrimSpace(text[start:end]); chunk != "" {
+			// Offset of the trimmed chunk, so it can be located in the source
+			offset := start + strings.Index(text[start:end], chunk)
+			chunks = append(chunks, textChunk{Offset: offset, Text: chunk})
+		}
+		if end == len(text) {
+			break
+		}
+
+		next := end - overlap
+		for next > start && !isRuneStart(text[next]) {
+			next--
+		}
+		if next <= start {
+			next = end
+		}
+		// Start the next chunk on a word boundary
+		if i := strings.IndexAny(text[next:end], " \n\t"); i >= 0 {
+			next += i + 1
+		}
+		start = next
+	}
+	return chunks
+}
+
+// chunkBoundary moves end back to the best break point in the second half of text[start:end]
+func chunkBoundary(text string, start, end int) int {
+	window := text[start:end]
+	for _, sep := range []string{"\n\n", "\n", ". ", " "} {
+		if i := strings.LastIndex(window, sep); i > len(window)/2 {
+			return start + i + len(sep)
+		}
+	}
+	// No break point: avoid splitting a UTF-8 sequence
+	for end > start && !isRuneStart(text[end]) {
+		end--
+	}
+	return end
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// IndexKnowledgeBaseItem extracts, chunks and stores the text of a knowledge base item,
+// replacing any chunks indexed before. If the item is already being indexed, another run is
+// queued after the current one so the latest version of the item gets indexed.
+func IndexKnowledgeBaseItem(id uint) error {
+	indexingMu.Lock()
+	if _, busy := indexingItems[id]; busy {
+		indexingItems[id] = true
+		indexingMu.Unlock()
+		return nil
+	}
+	indexingItems[id] = false
+	indexingMu.Unlock()
+
+	for {
+		err := indexKnowledgeBaseItem(id)
+
+		indexingMu.Lock()
+		if !indexingItems[id] {
+			delete(indexingItems, id)
+			indexingMu.Unlock()
+			return err
+		}
+		indexingItems[id] = false
+		indexingMu.Unlock()
+	}
+}
+
+func indexKnowledgeBaseItem(id uint) error {
+	item, err := GetKnowledgeBaseItemByID(id)
+	if err != nil {
+		return err
+	}
+
+	text, err := extractDocumentText(*item)
+	if err != nil {
+		return err
+	}
+
+	pieces := chunkText(text, knowledgeChunkSize, knowledgeChunkOverlap)
+	chunks := make([]KnowledgeChunk, 0, len(pieces))
+	for i, piece := range pieces {
+		chunks = append(chunks, KnowledgeChunk{
+			KnowledgeBaseID: id,
+			Ordinal:         i,
+			Offset:          piece.Offset,
+			Text:            piece.Text,
+		})
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("knowledge_base_id = ?", id).Delete(&KnowledgeChunk{}).Error; err != nil {
+			return err
+		}
+		if len(chunks) > 0 {
+			if err := tx.CreateInBatches(chunks, 100).Error; err != nil {
+				return err
+			}
+		}
+		// Items without text are indexed too, IndexMissingKnowledgeBaseItems skips them from now on
+		return tx.Model(&KnowledgeBase{}).Where("id = ?", id).UpdateColumn("indexed_at", time.Now()).Error
+	})
+	if err != nil {
+		log.Printf("Failed to store chunks of knowledge base item %d: %v", id, err)
+		return err
+	}
+	knowledgeEmbeddings.invalidate()
+
+	log.Printf("Indexed knowledge base item %d into %d chunks", id, len(chunks))
+	return nil
+}
+
+// indexKnowledgeBaseItemAsync indexes and embeds an item in the background
+func (a *App) indexKnowledgeBaseItemAsync(id uint) {
+	go func() {
+		if err := IndexKnowledgeBaseItem(id); err != nil {
+			log.Printf("Failed to index knowledge base item %d: %v", id, err)
+			return
+		}
+		a.embedKnowledgeChunksAsync()
+	}()
+}
+
+// IndexMissingKnowledgeBaseItems indexes every knowledge base item that has not been indexed yet
+func IndexMissingKnowledgeBaseItems() {
+	var ids []uint
+	err := DB.Model(&KnowledgeBase{}).Where("indexed_at IS NULL").Pluck("id", &ids).Error
+	if err != nil {
+		log.Printf("Failed to find unindexed knowledge base items: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		if err := IndexKnowledgeBaseItem(id); err != nil {
+			log.Printf("Failed to index knowledge base item %d: %v", id, err)
+		}
+	}
+}
+
+// indexKnowledgeBaseWhenReady waits for the Python backend to come up, then indexes
+// the knowledge base items that have not been indexed yet and embeds their chunks
+func (a *App) indexKnowledgeBaseWhenReady() {
+	for attempt := 0; attempt < 60; attempt++ {
+		if status, _, err := HealthCheck(); err == nil && status == "ok" {
+			IndexMissingKnowledgeBaseItems()
+			a.embedKnowledgeChunksAsync()
+			return
+		}
+		time.Sleep(5 * time.Second)
+	}
+	log.Printf("Python backend did not come up, knowledge base items were not indexed")
+}
+
+// DeleteKnowledgeChunks removes the indexed chunks of a knowledge base item
+func DeleteKnowledgeChunks(knowledgeBaseID uint) error {
+	result := DB.Where("knowledge_base_id = ?", knowledgeBaseID).Delete(&KnowledgeChunk{})
+	if result.Error != nil {
+		log.Printf("Failed to delete chunks of knowledge base item %d: %v", knowledgeBaseID, result.Error)
+		return result.Error
+	}
+	knowledgeEmbeddings.invalidate()
+	return nil
+}
+
+// GetKnowledgeChunkByID retrieves an indexed chunk by ID
+func GetKnowledgeChunkByID(id uint) (*KnowledgeChunk, error) {
+	var chunk KnowledgeChunk
+	result := DB.Omit("embedding").First(&chunk, id)
+	if result.Error != nil {
+		log.Printf("Failed to get knowledge chunk by ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &chunk, nil
+}
+
+// retrievalTopK returns the configured number of chunks to retrieve per question
+func retrievalTopK() int {
+	k, err := strconv.Atoi(GetSetting(SettingRetrievalTopK))
+	if err != nil || k < 0 {
+		return defaultRetrievalTopK
+	}
+	return k
+}
+
+// stopWords are ignored when matching questions against chunks
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "had": true, "her": true, "was": true, "one": true,
+	"our": true, "out": true, "has": true, "his": true, "how": true, "its": true, "who": true,
+	"did": true, "does": true, "what": true, "when": true, "where": true, "which": true,
+	"with": true, "this": true, "that": true, "from": true, "they": true, "have": true,
+	"will": true, "would": true, "there": true, "their": true, "about": true, "into": true,
+	"been": true, "were": true, "than": true, "then": true, "them": true, "these": true,
+	"those": true, "your": true, "please": true, "tell": true, "give": true,
+}
+
+// tokenizeForSearch lowercases text and splits it into searchable terms
+func tokenizeForSearch(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// retrieveKnowledge returns the chunks to include for a chat question: the semantically
+// closest ones when the knowledge base has been embedded, keyword matches otherwise
+func retrieveKnowledge(query string, k int) []RetrievedChunk {
+	if k <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	retrieved, err := semanticRetrieve(ctx, query, k)
+	if err != nil {
+		log.Printf("Semantic retrieval unavailable, falling back to keyword search: %v", err)
+	}
+	if err == nil && len(retrieved) > 0 {
+		relevant := retrieved[:0]
+		for _, chunk := range retrieved {
+			if chunk.Score >= minSemanticScore {
+				relevant = append(relevant, chunk)
+			}
+		}
+		return relevant
+	}
+
+	retrieved, _ = RetrieveKnowledgeChunks(query, k)
+	return retrieved
+}
+
+// RetrieveKnowledgeChunks returns the k chunks most relevant to query, ranked with BM25
+func RetrieveKnowledgeChunks(query string, k int) ([]RetrievedChunk, error) {
+	queryTerms := tokenizeForSearch(query)
+	if len(queryTerms) == 0 || k <= 0 {
+		return nil, nil
+	}
+
+	var chunks []KnowledgeChunk
+	if err := DB.Omit("embedding").Find(&chunks).Error; err != nil {
+		log.Printf("Failed to load knowledge chunks: %v", err)
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	// Term frequencies per chunk and document frequencies across chunks
+	const k1, b = 1.2, 0.75
+	termFreqs := make([]map[string]int, len(chunks))
+	lengths := make([]int, len(chunks))
+	docFreq := make(map[string]int)
+	totalLength := 0
+	for i, chunk := range chunks {
+		terms := tokenizeForSearch(chunk.Text)
+		freqs := make(map[string]int)
+		for _, term := range terms {
+			freqs[term]++
+		}
+		for term := range freqs {
+			docFreq[term]++
+		}
+		termFreqs[i] = freqs
+		lengths[i] = len(terms)
+		totalLength += len(terms)
+	}
+	avgLength := float64(totalLength) / float64(len(chunks))
+	if avgLength == 0 {
+		avgLength = 1
+	}
+
+	type scored struct {
+		index int
+		score float64
+	}
+	var results []scored
+	for i := range chunks {
+		score := 0.0
+		for _, term := range queryTerms {
+			tf := float64(termFreqs[i][term])
+			if tf == 0 {
+				continue
+			}
+			n := float64(docFreq[term])
+			idf := math.Log(1 + (float64(len(chunks))-n+0.5)/(n+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(lengths[i])/avgLength))
+		}
+		if score > 0 {
+			results = append(results, scored{index: i, score: score})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].score > results[j].score })
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	items := make(map[uint]KnowledgeBase)
+	retrieved := make([]RetrievedChunk, 0, len(results))
+	for _, result := range results {
+		chunk := chunks[result.index]
+		item, ok := items[chunk.KnowledgeBaseID]
+		if !ok {
+			found, err := GetKnowledgeBaseItemByID(chunk.KnowledgeBaseID)
+			if err != nil {
+				continue // chunk of a deleted item
+			}
+			item = *found
+			items[item.ID] = item
+		}
+		retrieved = append(retrieved, RetrievedChunk{Chunk: chunk, Item: item, Score: result.score})
+	}
+	return retrieved, nil
+}
+
+// knowledgeSourceName returns the name shown for a knowledge base item in prompts and citations
+func knowledgeSourceName(item KnowledgeBase) string {
+	if item.UniqueFileName != "" {
+		return item.UniqueFileName
+	}
+	return item.OneLineSummary
+}
+
+// citationFor describes a retrieved chunk as the citation labelled [index]
+func citationFor(index int, retrieved RetrievedChunk) Citation {
+	snippet := retrieved.Chunk.Text
+	if runes := []rune(snippet); len(runes) > 200 {
+		snippet = string(runes[:200]) + "…"
+	}
+	return Citation{
+		Index:           index,
+		KnowledgeBaseID: retrieved.Item.ID,
+		FileName:        knowledgeSourceName(retrieved.Item),
+		ChunkID:         retrieved.Chunk.ID,
+		Ordinal:         retrieved.Chunk.Ordinal,
+		Offset:          retrieved.Chunk.Offset,
+		Length:          len(retrieved.Chunk.Text),
+		Score:           retrieved.Score,
+		Snippet:         snippet,
+	}
+}
+
+// markCitedSources flags the citations whose [n] label appears in answer
+func markCitedSources(citations []Citation, answer string) []Citation {
+	marked := make([]Citation, len(citations))
+	for i, citation := range citations {
+		citation.Cited = strings.Contains(answer, fmt.Sprintf("[%d]", citation.Index))
+		marked[i] = citation
+	}
+	return marked
+}
+
+// Knowledge base index methods exposed to the frontend
+
+// ReindexKnowledgeBaseItem rebuilds the chunks of an item and embeds them in the background
+func (a *App) ReindexKnowledgeBaseItem(id uint) error {
+	if err := IndexKnowledgeBaseItem(id); err != nil {
+		return err
+	}
+	a.embedKnowledgeChunksAsync()
+	return nil
+}
+
+// ReindexKnowledgeBase rebuilds the chunks of every knowledge base item and embeds them in
+// the background. Chunks of the items indexed before a failure are embedded as well.
+func (a *App) ReindexKnowledgeBase() error {
+	items, err := GetAllKnowledgeBaseItems()
+	if err != nil {
+		return err
+	}
+	defer a.embedKnowledgeChunksAsync()
+	for _, item := range items {
+		if err := IndexKnowledgeBaseItem(item.ID); err != nil {
+			return fmt.Errorf("failed to index %s: %v", knowledgeSourceName(item), err)
+		}
+	}
+	return nil
+}
+
+// GetKnowledgeChunk returns a cited chunk so the frontend can show its source
+func (a *App) GetKnowledgeChunk(id uint) (*KnowledgeChunk, error) {
+	return GetKnowledgeChunkByID(id)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkText(t *testing.T) {
+	cases := map[string]string{
+		"english":  strings.Repeat("The budget review covers marketing and hiring. ", 200),
+		"japanese": strings.Repeat("会議記録予算討論", 300),
+		"mixed":    strings.Repeat("予算 budget 会議\n\n", 150),
+	}
+	for name, text := range cases {
+		chunks := chunkText(text, knowledgeChunkSize, knowledgeChunkOverlap)
+		if len(chunks) < 2 {
+			t.Errorf("%s: got %d chunks, want several", name, len(chunks))
+			continue
+		}
+		for i, chunk := range chunks {
+			if !utf8.ValidString(chunk.Text) {
+				t.Errorf("%s: chunk %d is not valid UTF-8", name, i)
+			}
+			if !strings.HasPrefix(text[chunk.Offset:], chunk.Text) {
+				t.Errorf("%s: chunk %d is not found at offset %d", name, i, chunk.Offset)
+			}
+			if len(chunk.Text) > knowledgeChunkSize {
+				t.Errorf("%s: chunk %d has %d bytes, more than %d", name, i, len(chunk.Text), knowledgeChunkSize)
+			}
+			if i > 0 && chunk.Offset <= chunks[i-1].Offset {
+				t.Errorf("%s: chunk %d starts at %d, not after chunk %d at %d", name, i, chunk.Offset, i-1, chunks[i-1].Offset)
+			}
+		}
+		last := chunks[len(chunks)-1]
+		if last.Offset+len(last.Text) != len(strings.TrimRight(text, " \n")) {
+			t.Errorf("%s: the last chunk ends at %d, want the end of the text", name, last.Offset+len(last.Text))
+		}
+	}
+}
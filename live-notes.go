@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Defaults of the live note-taker settings
+const (
+	defaultLiveNotesInterval   = 60 // seconds between two updates of the notes
+	defaultLiveNotesBatchLines = 20 // new transcript lines that trigger an update before the interval
+)
+
+const (
+	liveNotesPollInterval = 5 * time.Second  // how often new transcript lines are looked for
+	liveNotesSettleTime   = 5 * time.Second  // captions younger than this may still be updated by the extension
+	liveNotesMaxInterval  = 10 * time.Minute // longest the interval grows while backing off
+	liveNotesTimeout      = 5 * time.Minute  // longest a generation may take before it is cancelled
+)
+
+// LiveNotesStatus describes the live note-taker of a session
+type LiveNotesStatus struct {
+	SessionID    uint       `json:"sessionId"`
+	NotesID      uint       `json:"notesId"`
+	Running      bool       `json:"running"`
+	InFlight     bool       `json:"inFlight"`     // a generation is in progress
+	PendingLines int        `json:"pendingLines"` // transcript lines not sent to the agent yet
+	Interval     int        `json:"interval"`     // seconds between updates, grows while the agent falls behind
+	Updates      int        `json:"updates"`      // generations saved so far
+	LastUpdate   *time.Time `json:"lastUpdate"`
+	LastError    string     `json:"lastError"`
+}
+
+// liveNoteTaker keeps the meeting notes of an open session up to date. New transcript lines
+// are sent to the markdown agent in batches, one generation at a time, and the result is saved
+// as a notes revision.
+type liveNoteTaker struct {
+	app         *App
+	sessionID   uint
+	workspaceID uint
+	notesID     uint
+	stop        chan struct{}
+
+	mutex        sync.Mutex
+	lastRecordID uint // last transcript record sent to the agent
+	lastSent     time.Time
+	interval     time.Duration
+	status       LiveNotesStatus
+}
+
+// Live note-takers, by session ID
+var liveNoteTakers sync.Map
+
+// liveNotesEnabled reports whether sessions start with a live note-taker
+func liveNotesEnabled() bool {
+	enabled, err := strconv.ParseBool(GetSetting(SettingLiveNotesEnabled))
+	return err == nil && enabled
+}
+
+// liveNotesInterval returns the configured time between two updates of the notes
+func liveNotesInterval() time.Duration {
+	seconds, err := strconv.Atoi(GetSetting(SettingLiveNotesInterval))
+	if err != nil || seconds <= 0 {
+		seconds = defaultLiveNotesInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// liveNotesBatchLines returns the number of new transcript lines that trigger an update early
+func liveNotesBatchLines() int {
+	lines, err := strconv.Atoi(GetSetting(SettingLiveNotesBatchLines))
+	if err != nil || lines <= 0 {
+		return defaultLiveNotesBatchLines
+	}
+	return lines
+}
+
+// workspaceMeetingNotes returns the meeting notes of a workspace, creating empty ones if needed
+func workspaceMeetingNotes(workspaceID uint) (*MeetingNotes, error) {
+	notes, err := GetMeetingNotesByWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) > 0 {
+		return &notes[0], nil
+	}
+
+	created, err := CreateMeetingNotes(workspaceID, "")
+	if err != nil {
+		// The frontend may have created them meanwhile
+		if notes, retryErr := GetMeetingNotesByWorkspace(workspaceID); retryErr == nil && len(notes) > 0 {
+			return &notes[0], nil
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// startLiveNotes starts the live note-taker of a session, unless it is already running
+func (a *App) startLiveNotes(session *Session) (*LiveNotesStatus, error) {
+	if session.Status == SessionStatusStopped {
+		return nil, fmt.Errorf("session %d is stopped", session.ID)
+	}
+	if existing, ok := liveNoteTakers.Load(session.ID); ok {
+		return existing.(*liveNoteTaker).currentStatus(), nil
+	}
+
+	notes, err := workspaceMeetingNotes(session.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := liveNotesInterval()
+	taker := &liveNoteTaker{
+		app:         a,
+		sessionID:   session.ID,
+		workspaceID: session.WorkspaceID,
+		notesID:     notes.ID,
+		stop:        make(chan struct{}),
+		lastSent:    time.Now(),
+		interval:    interval,
+		status: LiveNotesStatus{
+			SessionID: session.ID,
+			NotesID:   notes.ID,
+			Running:   true,
+			Interval:  int(interval / time.Second),
+		},
+	}
+	if existing, loaded := liveNoteTakers.LoadOrStore(session.ID, taker); loaded {
+		return existing.(*liveNoteTaker).currentStatus(), nil
+	}
+
+	log.Printf("Started live note-taking for session %d into meeting notes %d", session.ID, notes.ID)
+	go taker.run()
+	return taker.currentStatus(), nil
+}
+
+// stopLiveNotes stops the live note-taker of a session. Lines not sent yet are sent in a last
+// update in the background.
+func stopLiveNotes(sessionID uint) {
+	if taker, ok := liveNoteTakers.LoadAndDelete(sessionID); ok {
+		close(taker.(*liveNoteTaker).stop)
+	}
+}
+
+// run checks for new transcript lines until the note-taker is stopped or the session ends
+func (t *liveNoteTaker) run() {
+	ticker := time.NewTicker(liveNotesPollInterval)
+	defer ticker.Stop()
+	defer func() {
+		liveNoteTakers.CompareAndDelete(t.sessionID, t)
+		t.mutex.Lock()
+		t.status.Running = false
+		t.mutex.Unlock()
+		log.Printf("Stopped live note-taking for session %d", t.sessionID)
+	}()
+
+	for {
+		select {
+		case <-t.stop:
+			t.update(true)
+			return
+		case <-ticker.C:
+			session, err := GetSessionByID(t.sessionID)
+			if err != nil {
+				return
+			}
+			if session.Status == SessionStatusStopped {
+				t.update(true)
+				return
+			}
+			if session.Status == SessionStatusActive {
+				t.update(false)
+			}
+		}
+	}
+}
+
+// unsentRecords returns the transcript lines of the session not sent to the agent yet. Unless
+// final is set, lines from the last few seconds are held back while their captions settle.
+func (t *liveNoteTaker) unsentRecords(final bool) ([]TranscriptionRecord, error) {
+	t.mutex.Lock()
+	lastRecordID := t.lastRecordID
+	t.mutex.Unlock()
+
+	var records []TranscriptionRecord
+	result := DB.Where("session_id = ? AND id > ?", t.sessionID, lastRecordID).Order("id ASC").Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if final {
+		return records, nil
+	}
+
+	settled := time.Now().Add(-liveNotesSettleTime)
+	for i, record := range records {
+		if record.UpdatedAt.After(settled) {
+			return records[:i], nil
+		}
+	}
+	return records, nil
+}
+
+// update sends the unsent transcript lines to the agent once enough of them piled up or the
+// interval elapsed, and waits for the generation to be saved
+func (t *liveNoteTaker) update(final bool) {
+	records, err := t.unsentRecords(final)
+	if err != nil {
+		log.Printf("Failed to get transcript of session %d for live notes: %v", t.sessionID, err)
+		return
+	}
+
+	t.mutex.Lock()
+	t.status.PendingLines = len(records)
+	due := final || len(records) >= liveNotesBatchLines() || time.Since(t.lastSent) >= t.interval
+	t.mutex.Unlock()
+	if len(records) == 0 || !due {
+		return
+	}
+
+	notes, err := GetMeetingNotesByID(t.notesID)
+	if err != nil {
+		t.backOff(err)
+		return
+	}
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, formatTranscriptLine(record))
+	}
+
+	request := &notesAgentRequest{NotesID: t.notesID, Base: notes.Text, Live: true, done: make(chan struct{})}
+	started := time.Now()
+	requestID, err := sendMeetingNotesRequest(lines, request)
+	if err != nil {
+		t.backOff(err)
+		return
+	}
+
+	t.mutex.Lock()
+	t.lastSent = started
+	t.status.InFlight = true
+	t.mutex.Unlock()
+	defer func() {
+		t.mutex.Lock()
+		t.status.InFlight = false
+		t.mutex.Unlock()
+	}()
+
+	select {
+	case <-request.done:
+	case <-time.After(liveNotesTimeout):
+		// The lines are sent again with the next update, a late result must not be saved too
+		if !markdownWsManager.forgetNotesRequest(requestID) {
+			// Completed just now, its output is being saved
+			<-request.done
+			break
+		}
+		if err := markdownWsManager.Cancel(requestID); err != nil {
+			log.Printf("Failed to cancel live notes request %s: %v", requestID, err)
+		}
+		t.backOff(fmt.Errorf("the markdown agent took longer than %s", liveNotesTimeout))
+		return
+	}
+	if !request.Saved {
+		t.backOff(fmt.Errorf("the markdown agent did not complete request %s", requestID))
+		return
+	}
+
+	took := time.Since(started)
+	now := time.Now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.lastRecordID = records[len(records)-1].ID
+	t.status.PendingLines = 0
+	t.status.Updates++
+	t.status.LastUpdate = &now
+	t.status.LastError = ""
+
+	// The agent is slower than the meeting: send bigger batches less often
+	configured := liveNotesInterval()
+	if took > t.interval {
+		t.interval = min(2*t.interval, liveNotesMaxInterval)
+		log.Printf("Live notes for session %d took %s, backing off to every %s", t.sessionID, took.Round(time.Second), t.interval)
+	} else if t.interval > configured {
+		t.interval = max(t.interval/2, configured)
+	} else {
+		t.interval = configured
+	}
+	t.status.Interval = int(t.interval / time.Second)
+}
+
+// backOff doubles the interval after a failed update, so an unavailable agent is not
+// retried every few seconds
+func (t *liveNoteTaker) backOff(err error) {
+	log.Printf("Live notes update for session %d failed: %v", t.sessionID, err)
+
+	t.mutex.Lock()
+	t.lastSent = time.Now()
+	t.interval = min(2*t.interval, liveNotesMaxInterval)
+	t.status.Interval = int(t.interval / time.Second)
+	t.status.LastError = err.Error()
+	status := t.status
+	t.mutex.Unlock()
+
+	runtime.EventsEmit(t.app.ctx, "liveNotesError", map[string]interface{}{
+		"sessionId": t.sessionID,
+		"error":     err.Error(),
+		"status":    status,
+	})
+}
+
+// currentStatus returns a copy of the note-taker status
+func (t *liveNoteTaker) currentStatus() *LiveNotesStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	status := t.status
+	return &status
+}
+
+// applyLiveNotes saves the changes of a live note-taker generation. Changes to lines the user
+// edited while the agent was generating are not saved but proposed as a patch for review.
+func (a *App) applyLiveNotes(requestID string, request *notesAgentRequest) {
+	notes, err := GetMeetingNotesByID(request.NotesID)
+	if err != nil {
+		log.Printf("Failed to get meeting notes %d for live notes request %s: %v", request.NotesID, requestID, err)
+		return
+	}
+
+	patch := buildNotesPatch(requestID, request.NotesID, request.Base, notes.Text, request.Output.String())
+	var clean []NotesHunk
+	for _, hunk := range patch.Hunks {
+		if !hunk.Conflict {
+			clean = append(clean, hunk)
+		}
+	}
+	if len(clean) > 0 {
+		if notes, err = saveMeetingNotes(request.NotesID, applyNotesHunks(patch.text, clean), NotesAuthorAgent, false); err != nil {
+			return
+		}
+	}
+	request.Saved = true
+
+	runtime.EventsEmit(a.ctx, "liveNotesUpdated", map[string]interface{}{
+		"requestId": requestID,
+		"notesId":   request.NotesID,
+		"notes":     notes,
+		"applied":   len(clean),
+		"conflicts": patch.Conflicts,
+	})
+
+	if patch.Conflicts == 0 {
+		return
+	}
+	// Compared again with the saved notes, only the conflicting changes are left
+	review, err := ProposeNotesPatch(requestID, request.NotesID, request.Base, request.Output.String())
+	if err != nil {
+		log.Printf("Failed to build meeting notes patch for live notes request %s: %v", requestID, err)
+		return
+	}
+	runtime.EventsEmit(a.ctx, "markdownAgentProposedPatch", map[string]interface{}{
+		"requestId": requestID,
+		"notesId":   request.NotesID,
+		"patch":     review,
+	})
+}
+
+// Live note-taking methods exposed to the frontend
+
+// StartLiveNotes starts keeping the meeting notes of an open session up to date
+func (a *App) StartLiveNotes(sessionID uint) (*LiveNotesStatus, error) {
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return a.startLiveNotes(session)
+}
+
+// StopLiveNotes stops the live note-taker of a session after a last update
+func (a *App) StopLiveNotes(sessionID uint) {
+	stopLiveNotes(sessionID)
+}
+
+// GetLiveNotesStatus returns the state of the live note-taker of a session
+func (a *App) GetLiveNotesStatus(sessionID uint) *LiveNotesStatus {
+	if taker, ok := liveNoteTakers.Load(sessionID); ok {
+		return taker.(*liveNoteTaker).currentStatus()
+	}
+	return &LiveNotesStatus{SessionID: sessionID}
+}
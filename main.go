@@ -159,6 +159,7 @@ func main() {
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
+		Menu:             app.applicationMenu(),
 		OnStartup:        app.startup,
 		Bind: []interface{}{
 			app,
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+// Action item states
+const (
+	ActionItemOpen      = "open"
+	ActionItemDone      = "done"
+	ActionItemCancelled = "cancelled"
+)
+
+// Number of transcript lines sent to the model in one extraction request
+const extractionBatchLines = 150
+
+// ActionItem is a task someone took on during a meeting
+type ActionItem struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	WorkspaceID     uint       `gorm:"not null;index" json:"workspaceId"`
+	SessionID       *uint      `gorm:"index" json:"sessionId"` // Session it was extracted from, if any
+	Text            string     `gorm:"not null" json:"text"`
+	Owner           string     `json:"owner"`                        // empty if nobody was named
+	DueDate         *time.Time `json:"dueDate"`                      // nil if no date was mentioned
+	Status          string     `gorm:"not null;index" json:"status"` // "open", "done" or "cancelled"
+	SourceMessageID string     `gorm:"index" json:"sourceMessageId"` // TranscriptionRecord.MessageID it came from
+	SourceText      string     `json:"sourceText"`                   // transcript line it came from
+	MentionedAt     *time.Time `json:"mentionedAt"`                  // last time a later meeting referred to it
+	MentionedIn     *uint      `json:"mentionedIn"`                  // session of the last mention
+	MentionText     string     `json:"mentionText"`                  // transcript line of the last mention
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
+	// Foreign key relationship
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// Decision is something agreed on during a meeting
+type Decision struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	WorkspaceID     uint      `gorm:"not null;index" json:"workspaceId"`
+	SessionID       *uint     `gorm:"index" json:"sessionId"`
+	Text            string    `gorm:"not null" json:"text"`
+	SourceMessageID string    `gorm:"index" json:"sourceMessageId"`
+	SourceText      string    `json:"sourceText"`
+	CreatedAt       time.Time `json:"createdAt"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
+	// Foreign key relationship
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// OpenQuestion is a question raised during a meeting that was left unanswered
+type OpenQuestion struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	WorkspaceID     uint      `gorm:"not null;index" json:"workspaceId"`
+	SessionID       *uint     `gorm:"index" json:"sessionId"`
+	Text            string    `gorm:"not null" json:"text"`
+	AskedBy         string    `json:"askedBy"`
+	SourceMessageID string    `gorm:"index" json:"sourceMessageId"`
+	SourceText      string    `json:"sourceText"`
+	CreatedAt       time.Time `json:"createdAt"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
+	// Foreign key relationship
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// MeetingExtraction holds the items an extraction pass added for a session
+type MeetingExtraction struct {
+	SessionID   uint           `json:"sessionId"`
+	ActionItems []ActionItem   `json:"actionItems"`
+	Decisions   []Decision     `json:"decisions"`
+	Questions   []OpenQuestion `json:"questions"`
+}
+
+// extractedItems is the JSON the model is asked to reply with. Line refers to the [n] label of
+// the transcript line an item came from.
+type extractedItems struct {
+	ActionItems []struct {
+		Text    string `json:"text"`
+		Owner   string `json:"owner"`
+		DueDate string `json:"due_date"`
+		Line    int    `json:"line"`
+	} `json:"action_items"`
+	Decisions []struct {
+		Text string `json:"text"`
+		Line int    `json:"line"`
+	} `json:"decisions"`
+	Questions []struct {
+		Text    string `json:"text"`
+		AskedBy string `json:"asked_by"`
+		Line    int    `json:"line"`
+	} `json:"questions"`
+}
+
+const extractionSystemPrompt = `You extract structured records from meeting transcripts. Every transcript line starts with its label [n].
+Reply with JSON only, no prose, in this shape:
+{"action_items": [{"text": "...", "owner": "...", "due_date": "YYYY-MM-DD", "line": n}],
+ "decisions": [{"text": "...", "line": n}],
+ "questions": [{"text": "...", "asked_by": "...", "line": n}]}
+action_items are tasks someone committed to or was asked to do; owner is the person responsible, empty if nobody was named; due_date is empty if no deadline was mentioned.
+decisions are things the participants agreed on.
+questions are questions that were raised and not answered in the transcript.
+line is the label of the transcript line the record comes from. Use empty arrays when there is nothing to report.`
+
+// parseExtractedItems reads the model reply, tolerating a code fence or text around the JSON
+func parseExtractedItems(reply string) (*extractedItems, error) {
+	text := trimMarkdownFence(reply)
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in model reply")
+	}
+
+	var items extractedItems
+	if err := json.Unmarshal([]byte(text[start:end+1]), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse model reply: %v", err)
+	}
+	return &items, nil
+}
+
+// parseDueDate reads a YYYY-MM-DD date from the model, nil if it is missing or malformed
+func parseDueDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// itemKey identifies an extracted item, so running the extraction again doesn't duplicate it
+func itemKey(sourceMessageID, text string) string {
+	return sourceMessageID + "\x00" + strings.ToLower(strings.TrimSpace(text))
+}
+
+// existingItemKeys returns the keys of the items of model already extracted from a session
+func existingItemKeys(model interface{}, sessionID uint) (map[string]bool, error) {
+	var rows []struct {
+		SourceMessageID string
+		Text            string
+	}
+	if err := DB.Model(model).Where("session_id = ?", sessionID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		keys[itemKey(row.SourceMessageID, row.Text)] = true
+	}
+	return keys, nil
+}
+
+// Sessions whose transcript is currently being extracted
+var extractingSessions sync.Map
+
+// ExtractMeetingItems asks the model for the action items, decisions and open questions of a
+// session's transcript and stores them, each linked to the transcript line it came from.
+// Items already extracted from the same line are not added again.
+func ExtractMeetingItems(ctx context.Context, sessionID uint) (*MeetingExtraction, error) {
+	if _, busy := extractingSessions.LoadOrStore(sessionID, true); busy {
+		return nil, fmt.Errorf("session %d is already being extracted", sessionID)
+	}
+	defer extractingSessions.Delete(sessionID)
+
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := GetTranscriptionMessagesBySession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := chatProviderForWorkspace(session.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	actionKeys, err := existingItemKeys(&ActionItem{}, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	decisionKeys, err := existingItemKeys(&Decision{}, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	questionKeys, err := existingItemKeys(&OpenQuestion{}, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	extraction := &MeetingExtraction{SessionID: sessionID, ActionItems: []ActionItem{}, Decisions: []Decision{}, Questions: []OpenQuestion{}}
+	meetingDate := fmt.Sprintf("The meeting took place on %s.", session.StartTime.Format("Monday 2006-01-02"))
+
+	for start := 0; start < len(records); start += extractionBatchLines {
+		batch := records[start:min(start+extractionBatchLines, len(records))]
+		lines := make([]string, 0, len(batch))
+		for i, record := range batch {
+			lines = append(lines, fmt.Sprintf("[%d] %s", i+1, formatTranscriptLine(record)))
+		}
+		// source returns the transcript line a label refers to, nil for a label the model made up
+		source := func(line int) *TranscriptionRecord {
+			if line < 1 || line > len(batch) {
+				return nil
+			}
+			return &batch[line-1]
+		}
+
+		requestCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+		reply, _, err := provider.Complete(requestCtx, []ChatMessage{
+			{Role: "system", Content: extractionSystemPrompt},
+			{Role: "user", Content: meetingDate + "\n\n" + strings.Join(lines, "\n")},
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract meeting items: %v", err)
+		}
+		items, err := parseExtractedItems(reply)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items.ActionItems {
+			record := source(item.Line)
+			if record == nil || strings.TrimSpace(item.Text) == "" || actionKeys[itemKey(record.MessageID, item.Text)] {
+				continue
+			}
+			actionKeys[itemKey(record.MessageID, item.Text)] = true
+			extraction.ActionItems = append(extraction.ActionItems, ActionItem{
+				WorkspaceID:     session.WorkspaceID,
+				SessionID:       &session.ID,
+				Text:            strings.TrimSpace(item.Text),
+				Owner:           strings.TrimSpace(item.Owner),
+				DueDate:         parseDueDate(item.DueDate),
+				Status:          ActionItemOpen,
+				SourceMessageID: record.MessageID,
+				SourceText:      formatTranscriptLine(*record),
+			})
+		}
+		for _, item := range items.Decisions {
+			record := source(item.Line)
+			if record == nil || strings.TrimSpace(item.Text) == "" || decisionKeys[itemKey(record.MessageID, item.Text)] {
+				continue
+			}
+			decisionKeys[itemKey(record.MessageID, item.Text)] = true
+			extraction.Decisions = append(extraction.Decisions, Decision{
+				WorkspaceID:     session.WorkspaceID,
+				SessionID:       &session.ID,
+				Text:            strings.TrimSpace(item.Text),
+				SourceMessageID: record.MessageID,
+				SourceText:      formatTranscriptLine(*record),
+			})
+		}
+		for _, item := range items.Questions {
+			record := source(item.Line)
+			if record == nil || strings.TrimSpace(item.Text) == "" || questionKeys[itemKey(record.MessageID, item.Text)] {
+				continue
+			}
+			questionKeys[itemKey(record.MessageID, item.Text)] = true
+			extraction.Questions = append(extraction.Questions, OpenQuestion{
+				WorkspaceID:     session.WorkspaceID,
+				SessionID:       &session.ID,
+				Text:            strings.TrimSpace(item.Text),
+				AskedBy:         strings.TrimSpace(item.AskedBy),
+				SourceMessageID: record.MessageID,
+				SourceText:      formatTranscriptLine(*record),
+			})
+		}
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if len(extraction.ActionItems) > 0 {
+			if err := tx.Create(&extraction.ActionItems).Error; err != nil {
+				return err
+			}
+		}
+		if len(extraction.Decisions) > 0 {
+			if err := tx.Create(&extraction.Decisions).Error; err != nil {
+				return err
+			}
+		}
+		if len(extraction.Questions) > 0 {
+			return tx.Create(&extraction.Questions).Error
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to save meeting items of session %d: %v", sessionID, err)
+		return nil, err
+	}
+
+	log.Printf("Extracted %d action items, %d decisions and %d open questions from session %d",
+		len(extraction.ActionItems), len(extraction.Decisions), len(extraction.Questions), sessionID)
+	return extraction, nil
+}
+
+// extractMeetingItemsAsync runs the extraction of a session in the background and reports
+// the result through the meetingItemsExtracted event
+func (a *App) extractMeetingItemsAsync(sessionID uint) {
+	go func() {
+		extraction, err := ExtractMeetingItems(context.Background(), sessionID)
+		if err != nil {
+			log.Printf("Failed to extract meeting items of session %d: %v", sessionID, err)
+			return
+		}
+		runtime.EventsEmit(a.ctx, "meetingItemsExtracted", extraction)
+	}()
+}
+
+// ListActionItems retrieves the action items of a workspace, newest first
+func ListActionItems(workspaceID uint) ([]ActionItem, error) {
+	var items []ActionItem
+	result := DB.Where("workspace_id = ?", workspaceID).Order("created_at DESC, id DESC").Find(&items)
+	if result.Error != nil {
+		log.Printf("Failed to get action items for workspace %d: %v", workspaceID, result.Error)
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// GetActionItemByID retrieves an action item by ID
+func GetActionItemByID(id uint) (*ActionItem, error) {
+	var item ActionItem
+	result := DB.First(&item, id)
+	if result.Error != nil {
+		log.Printf("Failed to get action item by ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// UpdateActionItem updates the text, owner, due date and status of an action item
+func UpdateActionItem(id uint, text, owner string, dueDate *time.Time, status string) (*ActionItem, error) {
+	switch status {
+	case ActionItemOpen, ActionItemDone, ActionItemCancelled:
+	default:
+		return nil, fmt.Errorf("unknown action item status: %s", status)
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("action item text is empty")
+	}
+
+	item, err := GetActionItemByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := DB.Model(item).Select("Text", "Owner", "DueDate", "Status").Updates(&ActionItem{
+		Text:    strings.TrimSpace(text),
+		Owner:   strings.TrimSpace(owner),
+		DueDate: dueDate,
+		Status:  status,
+	})
+	if result.Error != nil {
+		log.Printf("Failed to update action item %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return GetActionItemByID(id)
+}
+
+// DeleteActionItem deletes an action item
+func DeleteActionItem(id uint) error {
+	result := DB.Unscoped().Delete(&ActionItem{}, id)
+	if result.Error != nil {
+		log.Printf("Failed to delete action item %d: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// ListDecisions retrieves the decisions of a workspace, newest first
+func ListDecisions(workspaceID uint) ([]Decision, error) {
+	var decisions []Decision
+	result := DB.Where("workspace_id = ?", workspaceID).Order("created_at DESC, id DESC").Find(&decisions)
+	if result.Error != nil {
+		log.Printf("Failed to get decisions for workspace %d: %v", workspaceID, result.Error)
+		return nil, result.Error
+	}
+	return decisions, nil
+}
+
+// ListOpenQuestions retrieves the open questions of a workspace, newest first
+func ListOpenQuestions(workspaceID uint) ([]OpenQuestion, error) {
+	var questions []OpenQuestion
+	result := DB.Where("workspace_id = ?", workspaceID).Order("created_at DESC, id DESC").Find(&questions)
+	if result.Error != nil {
+		log.Printf("Failed to get open questions for workspace %d: %v", workspaceID, result.Error)
+		return nil, result.Error
+	}
+	return questions, nil
+}
+
+// Meeting item methods exposed to the frontend
+
+// ExtractMeetingItems extracts the action items, decisions and open questions of a session
+func (a *App) ExtractMeetingItems(sessionID uint) (*MeetingExtraction, error) {
+	return ExtractMeetingItems(a.ctx, sessionID)
+}
+
+func (a *App) ListActionItems(workspaceID uint) ([]ActionItem, error) {
+	return ListActionItems(workspaceID)
+}
+
+func (a *App) UpdateActionItem(id uint, text, owner string, dueDate *time.Time, status string) (*ActionItem, error) {
+	return UpdateActionItem(id, text, owner, dueDate, status)
+}
+
+func (a *App) DeleteActionItem(id uint) error {
+	return DeleteActionItem(id)
+}
+
+func (a *App) ListDecisions(workspaceID uint) ([]Decision, error) {
+	return ListDecisions(workspaceID)
+}
+
+func (a *App) ListOpenQuestions(workspaceID uint) ([]OpenQuestion, error) {
+	return ListOpenQuestions(workspaceID)
+}
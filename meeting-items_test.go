@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExtractedItems(t *testing.T) {
+	const object = `{"action_items": [{"text": "Send the deck", "owner": "Alice", "due_date": "2026-10-23", "line": 1}], "decisions": [], "questions": []}`
+	cases := []struct {
+		name  string
+		reply string
+		ok    bool
+	}{
+		{name: "plain JSON", reply: object, ok: true},
+		{name: "json fence", reply: "```json\n" + object + "\n```", ok: true},
+		{name: "bare fence", reply: "```\n" + object + "\n```", ok: true},
+		{name: "prose around", reply: "Here are the items:\n" + object + "\nLet me know if you need more.", ok: true},
+		{name: "no JSON", reply: "There were no action items."},
+		{name: "truncated", reply: `{"action_items": [{"text": "Send the deck"`},
+		{name: "wrong shape", reply: `{"action_items": "none"}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			items, err := parseExtractedItems(c.reply)
+			if !c.ok {
+				if err == nil {
+					t.Errorf("parsed %+v, want an error", items)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items.ActionItems) != 1 || items.ActionItems[0].Text != "Send the deck" || items.ActionItems[0].Owner != "Alice" || items.ActionItems[0].Line != 1 {
+				t.Errorf("action items = %+v", items.ActionItems)
+			}
+		})
+	}
+}
+
+func TestExtractMeetingItems(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Launch", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &scriptedProvider{}
+	chatProviders["scripted"] = provider
+	t.Cleanup(func() { delete(chatProviders, "scripted") })
+	if err := DB.Model(workspace).Update("chat_backend", "scripted").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := StartSession(workspace.ID, "", "zoom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	addCaption(t, workspace.ID, "a", "Alice", "I'll send the deck by Friday", start)
+	addCaption(t, workspace.ID, "b", "Bob", "Then we launch on the 3rd", start.Add(time.Second))
+	addCaption(t, workspace.ID, "c", "Carol", "Who talks to legal?", start.Add(2*time.Second))
+
+	// Labels are the positions of the lines in the request, [9] does not exist
+	provider.reply = func(system, user string) string {
+		return `{"action_items": [
+  {"text": "Send the deck", "owner": "Alice", "due_date": "2026-10-23", "line": 1},
+  {"text": " send the DECK ", "owner": "Alice", "line": 1},
+  {"text": "Book the venue", "owner": "Bob", "line": 9},
+  {"text": "  ", "line": 2}],
+ "decisions": [{"text": "Launch on the 3rd", "line": 2}, {"text": "Invented", "line": 0}],
+ "questions": [{"text": "Who talks to legal?", "asked_by": "Carol", "line": 3}]}`
+	}
+
+	extraction, err := ExtractMeetingItems(context.Background(), session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(provider.requests[0], "[1] Alice: I'll send the deck by Friday\n[2] Bob:") {
+		t.Errorf("request = %q, want labelled transcript lines", provider.requests[0])
+	}
+
+	if len(extraction.ActionItems) != 1 {
+		t.Fatalf("action items = %+v, want the deck only", extraction.ActionItems)
+	}
+	item := extraction.ActionItems[0]
+	if item.SourceMessageID != "a" || item.SourceText != "Alice: I'll send the deck by Friday" || item.Owner != "Alice" || item.Status != ActionItemOpen {
+		t.Errorf("action item = %+v", item)
+	}
+	if item.DueDate == nil || item.DueDate.Format(time.DateOnly) != "2026-10-23" {
+		t.Errorf("due date = %v", item.DueDate)
+	}
+	if len(extraction.Decisions) != 1 || extraction.Decisions[0].SourceMessageID != "b" {
+		t.Errorf("decisions = %+v, want the launch date from line b", extraction.Decisions)
+	}
+	if len(extraction.Questions) != 1 || extraction.Questions[0].SourceMessageID != "c" || extraction.Questions[0].AskedBy != "Carol" {
+		t.Errorf("questions = %+v, want Carol's question from line c", extraction.Questions)
+	}
+
+	// Running the extraction again only adds what is new
+	provider.reply = func(system, user string) string {
+		return `{"action_items": [{"text": "SEND THE DECK", "line": 1}, {"text": "Brief legal", "owner": "Carol", "line": 3}],
+ "decisions": [{"text": "Launch on the 3rd", "line": 2}], "questions": []}`
+	}
+	extraction, err = ExtractMeetingItems(context.Background(), session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extraction.ActionItems) != 1 || extraction.ActionItems[0].Text != "Brief legal" || len(extraction.Decisions) != 0 {
+		t.Errorf("second run extracted %+v and %+v, want only the new action item", extraction.ActionItems, extraction.Decisions)
+	}
+	items, err := ListActionItems(workspace.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decisions, err := ListDecisions(workspace.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || len(decisions) != 1 {
+		t.Errorf("stored %d action items and %d decisions, want 2 and 1", len(items), len(decisions))
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+// summaryPromptVersion identifies the map-reduce prompts below. Bump it whenever they change,
+// so summaries record which prompts produced them.
+const summaryPromptVersion = 1
+
+// MeetingSummary is a summary of a session's transcript written from a summary prompt template
+type MeetingSummary struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	WorkspaceID     uint      `gorm:"not null;index" json:"workspaceId"`
+	SessionID       uint      `gorm:"not null;index" json:"sessionId"`
+	TemplateID      *uint     `gorm:"index" json:"templateId"` // PromptTemplate it was written from, nil once deleted
+	TemplateName    string    `json:"templateName"`
+	TemplateVersion int       `json:"templateVersion"`
+	PromptVersion   int       `json:"promptVersion"`         // summaryPromptVersion used
+	Model           string    `json:"model"`                 // model that wrote it
+	Chunks          int       `json:"chunks"`                // transcript chunks summarised separately, 1 if it fit at once
+	Text            string    `gorm:"type:text" json:"text"` // Markdown format
+	CreatedAt       time.Time `json:"createdAt"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
+	// Foreign key relationship
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// Progress stages reported through the meetingSummaryProgress event
+const (
+	SummaryStageMap    = "map"    // summarising transcript chunks
+	SummaryStageReduce = "reduce" // merging chunk summaries
+	SummaryStageWrite  = "write"  // writing the final summary from the template
+)
+
+// summaryProgress reports how far a summary has come
+type summaryProgress func(stage string, done, total int)
+
+// summaryChunkTokens returns the size of the transcript chunks summarised separately,
+// leaving room in the context for the instructions and the reply
+func summaryChunkTokens() int {
+	return max(contextTokenBudget()/2, 512)
+}
+
+// groupByTokens splits texts into consecutive groups of at most maxTokens, keeping at least
+// one text per group
+func groupByTokens(texts []string, maxTokens int) [][]string {
+	var groups [][]string
+	var current []string
+	tokens := 0
+	for _, text := range texts {
+		cost := estimateTokens(text) + 1
+		if len(current) > 0 && tokens+cost > maxTokens {
+			groups = append(groups, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, text)
+		tokens += cost
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// summarizeTranscript runs the map-reduce summarisation of transcript lines: chunks that don't
+// fit in the context are summarised separately, and the partial summaries are merged until they
+// fit. The final summary is written following the rendered template instructions. It returns
+// the summary, the number of chunks and the model that wrote it.
+func summarizeTranscript(ctx context.Context, provider ChatProvider, lines []string, instructions string, progress summaryProgress) (string, int, string, error) {
+	model := provider.Name()
+	complete := func(system, user string) (string, error) {
+		requestCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+		reply, stats, err := provider.Complete(requestCtx, []ChatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		})
+		if stats != nil && stats.Model != "" {
+			model = stats.Model
+		}
+		return strings.TrimSpace(reply), err
+	}
+
+	limit := summaryChunkTokens()
+	chunks := groupByTokens(lines, limit)
+	material := "Meeting transcript:\n" + strings.Join(lines, "\n")
+
+	if len(chunks) > 1 {
+		partials := make([]string, 0, len(chunks))
+		for i, chunk := range chunks {
+			progress(SummaryStageMap, i, len(chunks))
+			partial, err := complete(
+				"You summarise part of a meeting transcript. Keep every fact, number, name, decision, action item (with owner and deadline) and open question. Reply with concise bullet points, no preamble.",
+				fmt.Sprintf("Part %d of %d of the transcript:\n%s", i+1, len(chunks), strings.Join(chunk, "\n")))
+			if err != nil {
+				return "", 0, "", fmt.Errorf("failed to summarise transcript chunk %d of %d: %v", i+1, len(chunks), err)
+			}
+			partials = append(partials, fmt.Sprintf("Part %d:\n%s", i+1, partial))
+		}
+
+		// Merge neighbouring summaries until they fit in one request
+		for round := 1; len(groupByTokens(partials, limit)) > 1; round++ {
+			groups := groupByTokens(partials, limit)
+			if len(groups) == len(partials) {
+				// No two neighbouring summaries fit in one request: shorten them so they merge in
+				// pairs, leaving a token for the separator and one for the truncation mark
+				shortened := limit/2 - 2
+				log.Printf("Transcript summaries are too long to merge, shortening %d of them to %d tokens", len(partials), shortened)
+				for i := range partials {
+					partials[i] = truncateToTokens(partials[i], shortened)
+				}
+				groups = groupByTokens(partials, limit)
+			}
+			merged := make([]string, 0, len(groups))
+			for i, group := range groups {
+				progress(SummaryStageReduce, i, len(groups))
+				if len(group) == 1 {
+					merged = append(merged, group[0])
+					continue
+				}
+				text, err := complete(
+					"You merge consecutive summaries of parts of one meeting into a single summary, in meeting order. Keep every fact, number, name, decision, action item and open question; drop repetition. Reply with concise bullet points, no preamble.",
+					strings.Join(group, "\n\n"))
+				if err != nil {
+					return "", 0, "", fmt.Errorf("failed to merge transcript summaries (round %d): %v", round, err)
+				}
+				merged = append(merged, text)
+			}
+			partials = merged
+		}
+		material = "Summaries of the meeting, in order:\n" + strings.Join(partials, "\n\n")
+	}
+
+	progress(SummaryStageWrite, 0, 1)
+	summary, err := complete(
+		"You write meeting summaries from transcripts or notes about a meeting. Only report what the material supports. Reply with the summary only, no preamble.\n\n"+instructions,
+		material)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to write meeting summary: %v", err)
+	}
+	return trimMarkdownFence(summary), len(chunks), model, nil
+}
+
+// summaryTemplate returns the summary prompt template with templateID, or the one the
+// workspace uses when templateID is 0
+func summaryTemplate(workspaceID, templateID uint) (*PromptTemplate, error) {
+	if templateID == 0 {
+		return GetWorkspacePromptTemplate(workspaceID, PromptKindSummary)
+	}
+	template, err := GetPromptTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.Kind != PromptKindSummary {
+		return nil, fmt.Errorf("prompt template %q is a %s template, not %s", template.Name, template.Kind, PromptKindSummary)
+	}
+	return template, nil
+}
+
+// GenerateMeetingSummary summarises the transcript of a session with a summary prompt template,
+// the workspace's when templateID is 0, and saves the result. progress may be nil.
+func GenerateMeetingSummary(ctx context.Context, sessionID, templateID uint, progress summaryProgress) (*MeetingSummary, error) {
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := summaryTemplate(session.WorkspaceID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	// The transcript is sent as the message, so the template is rendered without it
+	instructions, err := renderPromptTemplate(template.Name, template.Body, &promptData{workspaceID: session.WorkspaceID, transcript: []string{}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render summary template %q: %v", template.Name, err)
+	}
+	records, err := GetTranscriptionMessagesBySession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("session %d has no transcript to summarise", sessionID)
+	}
+	provider, err := chatProviderForWorkspace(session.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		progress = func(string, int, int) {}
+	}
+
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, formatTranscriptLine(record))
+	}
+	text, chunks, model, err := summarizeTranscript(ctx, provider, lines, instructions, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &MeetingSummary{
+		WorkspaceID:     session.WorkspaceID,
+		SessionID:       sessionID,
+		TemplateID:      &template.ID,
+		TemplateName:    template.Name,
+		TemplateVersion: template.Version,
+		PromptVersion:   summaryPromptVersion,
+		Model:           model,
+		Chunks:          chunks,
+		Text:            text,
+	}
+	if err := DB.Create(summary).Error; err != nil {
+		log.Printf("Failed to save meeting summary for session %d: %v", sessionID, err)
+		return nil, err
+	}
+
+	log.Printf("Summarised session %d with template %q in %d chunks", sessionID, template.Name, chunks)
+	return summary, nil
+}
+
+// ListMeetingSummaries retrieves the summaries of a session, newest first
+func ListMeetingSummaries(sessionID uint) ([]MeetingSummary, error) {
+	var summaries []MeetingSummary
+	result := DB.Where("session_id = ?", sessionID).Order("created_at DESC, id DESC").Find(&summaries)
+	if result.Error != nil {
+		log.Printf("Failed to get meeting summaries for session %d: %v", sessionID, result.Error)
+		return nil, result.Error
+	}
+	return summaries, nil
+}
+
+// DeleteMeetingSummary deletes a meeting summary
+func DeleteMeetingSummary(id uint) error {
+	result := DB.Unscoped().Delete(&MeetingSummary{}, id)
+	if result.Error != nil {
+		log.Printf("Failed to delete meeting summary %d: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// Meeting summary methods exposed to the frontend
+
+// GenerateMeetingSummary summarises a session's transcript with a summary prompt template, the
+// workspace's when templateID is 0, reporting progress through the meetingSummaryProgress event
+func (a *App) GenerateMeetingSummary(sessionID, templateID uint) (*MeetingSummary, error) {
+	return GenerateMeetingSummary(a.ctx, sessionID, templateID, func(stage string, done, total int) {
+		runtime.EventsEmit(a.ctx, "meetingSummaryProgress", map[string]interface{}{
+			"sessionId": sessionID,
+			"stage":     stage,
+			"done":      done,
+			"total":     total,
+		})
+	})
+}
+
+func (a *App) ListMeetingSummaries(sessionID uint) ([]MeetingSummary, error) {
+	return ListMeetingSummaries(sessionID)
+}
+
+func (a *App) DeleteMeetingSummary(id uint) error {
+	return DeleteMeetingSummary(id)
+}
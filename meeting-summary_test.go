@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedProvider answers Complete with reply and records the requests it got
+type scriptedProvider struct {
+	ChatProvider
+	model    string
+	reply    func(system, user string) string
+	requests []string
+}
+
+func (p *scriptedProvider) Name() string { return "scripted" }
+
+func (p *scriptedProvider) Complete(ctx context.Context, messages []ChatMessage) (string, *ChatCompletionStats, error) {
+	p.requests = append(p.requests, messages[1].Content)
+	return p.reply(messages[0].Content, messages[1].Content), &ChatCompletionStats{Model: p.model}, nil
+}
+
+func TestSummarizeTranscriptRecordsModel(t *testing.T) {
+	useTestDatabase(t)
+	provider := &scriptedProvider{model: "llama3:8b", reply: func(string, string) string { return "```markdown\n## Summary\n```" }}
+
+	summary, chunks, model, err := summarizeTranscript(context.Background(), provider, []string{"Alice: hello"}, builtInPromptBody(PromptKindSummary), func(string, int, int) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != "## Summary" || chunks != 1 || model != "llama3:8b" {
+		t.Errorf("summary = %q, chunks = %d, model = %q", summary, chunks, model)
+	}
+	if len(provider.requests) != 1 || !strings.Contains(provider.requests[0], "Alice: hello") {
+		t.Errorf("requests = %q", provider.requests)
+	}
+}
+
+func TestSummarizeTranscriptShortensLongPartials(t *testing.T) {
+	useTestDatabase(t)
+	if err := SetSetting(SettingContextTokens, "1024"); err != nil {
+		t.Fatal(err)
+	}
+	limit := summaryChunkTokens()
+
+	// Every chunk summary takes most of a request, so no two of them can be merged as they are
+	long := strings.Repeat("word ", limit*3/4)
+	provider := &scriptedProvider{reply: func(system, user string) string {
+		if strings.Contains(system, "summarise part") {
+			return long
+		}
+		return "- merged"
+	}}
+
+	var lines []string
+	for i := 0; i < 4*limit/10; i++ {
+		lines = append(lines, "Alice: "+strings.Repeat("talk ", 10))
+	}
+	_, chunks, model, err := summarizeTranscript(context.Background(), provider, lines, builtInPromptBody(PromptKindSummary), func(string, int, int) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks < 3 || model != "scripted" {
+		t.Errorf("chunks = %d, model = %q, want several chunks and the provider name without stats", chunks, model)
+	}
+	for i, request := range provider.requests {
+		if tokens := estimateTokens(request); tokens > limit+16 {
+			t.Errorf("request %d has %d tokens, more than the %d a chunk may take", i, tokens, limit)
+		}
+	}
+}
+
+func TestGenerateMeetingSummaryUsesWorkspaceTemplate(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Acme", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var system string
+	provider := &scriptedProvider{model: "llama3:8b", reply: func(s, user string) string {
+		system = s
+		return "## Summary"
+	}}
+	chatProviders["scripted"] = provider
+	t.Cleanup(func() { delete(chatProviders, "scripted") })
+	if err := DB.Model(workspace).Update("chat_backend", "scripted").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	template, err := CreatePromptTemplate("Customer call", PromptKindSummary, "", "Summarise the {{.Workspace}} call.{{.Transcript}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetWorkspacePromptTemplate(workspace.ID, PromptKindSummary, template.ID); err != nil {
+		t.Fatal(err)
+	}
+	session, err := StartSession(workspace.ID, "", "zoom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addCaption(t, workspace.ID, "a", "Alice", "We renew in March", time.Now())
+
+	summary, err := GenerateMeetingSummary(context.Background(), session.ID, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(system, "\n\nSummarise the Acme call.") {
+		t.Errorf("system prompt = %q, want the rendered workspace template", system)
+	}
+	if summary.TemplateID == nil || *summary.TemplateID != template.ID || summary.TemplateName != "Customer call" || summary.TemplateVersion != 1 {
+		t.Errorf("summary = %+v, want it to record the template", summary)
+	}
+
+	chat, err := GetWorkspacePromptTemplate(workspace.ID, PromptKindChat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateMeetingSummary(context.Background(), session.ID, chat.ID, nil); err == nil {
+		t.Error("summarised with a chat template")
+	}
+
+	// Summaries keep the name of a deleted template
+	if err := DeletePromptTemplate(template.ID); err != nil {
+		t.Fatal(err)
+	}
+	summaries, err := ListMeetingSummaries(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].TemplateID != nil || summaries[0].TemplateName != "Customer call" {
+		t.Errorf("summaries after deleting the template = %+v", summaries)
+	}
+}
+
+func TestMigrateSummaryTemplates(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Acme", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Put the database back the way version 9 left it
+	var migration Migration
+	for _, m := range migrations {
+		if m.Version == 10 {
+			migration = m
+		}
+	}
+	err = execStatements(DB,
+		"DELETE FROM prompt_templates WHERE kind = 'summary'",
+		`CREATE TABLE summary_templates (
+			id integer PRIMARY KEY AUTOINCREMENT,
+			name text NOT NULL,
+			description text,
+			instructions text NOT NULL,
+			built_in numeric NOT NULL,
+			version integer NOT NULL DEFAULT 1,
+			created_at datetime,
+			updated_at datetime)`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, template := range builtInPromptTemplates {
+		if template.Kind == PromptKindSummary {
+			DB.Exec("INSERT INTO summary_templates (name, description, instructions, built_in, version, created_at, updated_at) VALUES (?, ?, ?, true, 1, ?, ?)",
+				template.Name, template.Description, template.Body, now, now)
+		}
+	}
+	// A user template named like a chat template, with braces from before templates were rendered
+	DB.Exec("INSERT INTO summary_templates (id, name, description, instructions, built_in, version, created_at, updated_at) VALUES (40, 'Assistant', '', 'List {{decisions}} first', false, 3, ?, ?)", now, now)
+	oldID := uint(40)
+	if err := DB.Create(&MeetingSummary{WorkspaceID: workspace.ID, SessionID: 1, TemplateID: &oldID, TemplateName: "Assistant", Text: "## Summary"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migration.Up(DB); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := ListPromptTemplates(PromptKindSummary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 5 {
+		t.Fatalf("summary prompt templates = %+v, want the 4 built-in ones and the user's", templates)
+	}
+	moved := templates[len(templates)-1]
+	if moved.Name != "Assistant (2)" || moved.BuiltIn || moved.Version != 3 {
+		t.Errorf("moved template = %+v", moved)
+	}
+	if rendered, err := renderPromptTemplate(moved.Name, moved.Body, &promptData{}); err != nil || rendered != "List {{decisions}} first" {
+		t.Errorf("moved template renders as %q, %v", rendered, err)
+	}
+	var summary MeetingSummary
+	if err := DB.First(&summary).Error; err != nil {
+		t.Fatal(err)
+	}
+	if summary.TemplateID == nil || *summary.TemplateID != moved.ID {
+		t.Errorf("summary template ID = %v, want %d", summary.TemplateID, moved.ID)
+	}
+	if DB.Migrator().HasTable("summary_templates") {
+		t.Error("summary_templates was not dropped")
+	}
+}
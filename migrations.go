@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Directory the database is copied to before migrations run
+const databaseBackupDir = "yumesession/backups"
+
+// SchemaMigration records a migration applied to the database
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"not null" json:"name"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// TableName keeps the conventional migrations table name
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migration upgrades the schema by one version. Up runs inside a transaction.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+}
+
+// migrations lists every schema change in order. Never edit or reorder a released migration,
+// append a new one instead. Migrations after the first are plain SQL, so they keep describing
+// the schema of their version when the models change.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(schemaV1...)
+		},
+	},
+	{
+		Version: 2,
+		Name:    "workspace trash and archive",
+		Up: func(tx *gorm.DB) error {
+			return execStatements(tx,
+				"ALTER TABLE workspaces ADD COLUMN archived_at datetime",
+				"ALTER TABLE workspaces ADD COLUMN deleted_at datetime",
+				"CREATE INDEX idx_workspaces_archived_at ON workspaces(archived_at)",
+				"CREATE INDEX idx_workspaces_deleted_at ON workspaces(deleted_at)",
+				"ALTER TABLE transcription_records ADD COLUMN deleted_at datetime",
+				"CREATE INDEX idx_transcription_records_deleted_at ON transcription_records(deleted_at)",
+				"ALTER TABLE meeting_notes ADD COLUMN deleted_at datetime",
+				"CREATE INDEX idx_meeting_notes_deleted_at ON meeting_notes(deleted_at)",
+				"ALTER TABLE ai_chat_messages ADD COLUMN deleted_at datetime",
+				"CREATE INDEX idx_ai_chat_messages_deleted_at ON ai_chat_messages(deleted_at)",
+				"ALTER TABLE sessions ADD COLUMN deleted_at datetime",
+				"CREATE INDEX idx_sessions_deleted_at ON sessions(deleted_at)",
+			)
+		},
+	},
+	{
+		Version: 3,
+		Name:    "meeting notes revisions",
+		Up: func(tx *gorm.DB) error {
+			err := execStatements(tx,
+				`CREATE TABLE meeting_notes_revisions (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					notes_id integer NOT NULL,
+					workspace_id integer NOT NULL,
+					session_id integer,
+					author text NOT NULL,
+					text text,
+					created_at datetime,
+					updated_at datetime,
+					CONSTRAINT fk_meeting_notes_revisions_notes FOREIGN KEY (notes_id) REFERENCES meeting_notes(id))`,
+				"CREATE INDEX idx_meeting_notes_revisions_notes_id ON meeting_notes_revisions(notes_id)",
+				"CREATE INDEX idx_meeting_notes_revisions_workspace_id ON meeting_notes_revisions(workspace_id)",
+				"CREATE INDEX idx_meeting_notes_revisions_session_id ON meeting_notes_revisions(session_id)",
+			)
+			if err != nil {
+				return err
+			}
+			// Existing notes start their history from their current text
+			return tx.Exec(`INSERT INTO meeting_notes_revisions (notes_id, workspace_id, author, text, created_at, updated_at)
+				SELECT id, workspace_id, ?, text, updated_at, updated_at FROM meeting_notes WHERE text <> ''`, NotesAuthorUser).Error
+		},
+	},
+	{
+		Version: 4,
+		Name:    "action items, decisions and open questions",
+		Up: func(tx *gorm.DB) error {
+			return execStatements(tx,
+				`CREATE TABLE action_items (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					workspace_id integer NOT NULL,
+					session_id integer,
+					text text NOT NULL,
+					owner text,
+					due_date datetime,
+					status text NOT NULL,
+					source_message_id text,
+					source_text text,
+					created_at datetime,
+					updated_at datetime,
+					deleted_at datetime,
+					CONSTRAINT fk_action_items_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id))`,
+				"CREATE INDEX idx_action_items_workspace_id ON action_items(workspace_id)",
+				"CREATE INDEX idx_action_items_session_id ON action_items(session_id)",
+				"CREATE INDEX idx_action_items_status ON action_items(status)",
+				"CREATE INDEX idx_action_items_source_message_id ON action_items(source_message_id)",
+				"CREATE INDEX idx_action_items_deleted_at ON action_items(deleted_at)",
+				`CREATE TABLE decisions (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					workspace_id integer NOT NULL,
+					session_id integer,
+					text text NOT NULL,
+					source_message_id text,
+					source_text text,
+					created_at datetime,
+					deleted_at datetime,
+					CONSTRAINT fk_decisions_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id))`,
+				"CREATE INDEX idx_decisions_workspace_id ON decisions(workspace_id)",
+				"CREATE INDEX idx_decisions_session_id ON decisions(session_id)",
+				"CREATE INDEX idx_decisions_source_message_id ON decisions(source_message_id)",
+				"CREATE INDEX idx_decisions_deleted_at ON decisions(deleted_at)",
+				`CREATE TABLE open_questions (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					workspace_id integer NOT NULL,
+					session_id integer,
+					text text NOT NULL,
+					asked_by text,
+					source_message_id text,
+					source_text text,
+					created_at datetime,
+					deleted_at datetime,
+					CONSTRAINT fk_open_questions_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id))`,
+				"CREATE INDEX idx_open_questions_workspace_id ON open_questions(workspace_id)",
+				"CREATE INDEX idx_open_questions_session_id ON open_questions(session_id)",
+				"CREATE INDEX idx_open_questions_source_message_id ON open_questions(source_message_id)",
+				"CREATE INDEX idx_open_questions_deleted_at ON open_questions(deleted_at)",
+			)
+		},
+	},
+	{
+		Version: 5,
+		Name:    "action item mentions",
+		Up: func(tx *gorm.DB) error {
+			return execStatements(tx,
+				"ALTER TABLE action_items ADD COLUMN mentioned_at datetime",
+				"ALTER TABLE action_items ADD COLUMN mentioned_in integer",
+				"ALTER TABLE action_items ADD COLUMN mention_text text",
+			)
+		},
+	},
+	{
+		Version: 6,
+		Name:    "meeting summaries and summary templates",
+		Up: func(tx *gorm.DB) error {
+			err := execStatements(tx,
+				`CREATE TABLE summary_templates (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					name text NOT NULL,
+					description text,
+					instructions text NOT NULL,
+					built_in numeric NOT NULL,
+					version integer NOT NULL DEFAULT 1,
+					created_at datetime,
+					updated_at datetime)`,
+				"CREATE UNIQUE INDEX idx_summary_templates_name ON summary_templates(name)",
+				`CREATE TABLE meeting_summaries (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					workspace_id integer NOT NULL,
+					session_id integer NOT NULL,
+					template_id integer,
+					template_name text,
+					template_version integer,
+					prompt_version integer,
+					model text,
+					chunks integer,
+					text text,
+					created_at datetime,
+					deleted_at datetime,
+					CONSTRAINT fk_meeting_summaries_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id))`,
+				"CREATE INDEX idx_meeting_summaries_workspace_id ON meeting_summaries(workspace_id)",
+				"CREATE INDEX idx_meeting_summaries_session_id ON meeting_summaries(session_id)",
+				"CREATE INDEX idx_meeting_summaries_template_id ON meeting_summaries(template_id)",
+				"CREATE INDEX idx_meeting_summaries_deleted_at ON meeting_summaries(deleted_at)",
+			)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, template := range builtInPromptTemplates {
+				if template.Kind != PromptKindSummary {
+					continue
+				}
+				err := tx.Exec(`INSERT INTO summary_templates (name, description, instructions, built_in, version, created_at, updated_at)
+					VALUES (?, ?, ?, true, 1, ?, ?)`, template.Name, template.Description, template.Body, now, now).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 7,
+		Name:    "prompt templates",
+		Up: func(tx *gorm.DB) error {
+			err := execStatements(tx,
+				`CREATE TABLE prompt_templates (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					name text NOT NULL,
+					kind text NOT NULL,
+					description text,
+					body text NOT NULL,
+					built_in numeric NOT NULL,
+					version integer NOT NULL DEFAULT 1,
+					created_at datetime,
+					updated_at datetime)`,
+				"CREATE UNIQUE INDEX idx_prompt_templates_name ON prompt_templates(name)",
+				"CREATE INDEX idx_prompt_templates_kind ON prompt_templates(kind)",
+				`CREATE TABLE workspace_prompt_templates (
+					workspace_id integer,
+					kind text,
+					template_id integer NOT NULL,
+					PRIMARY KEY (workspace_id, kind),
+					CONSTRAINT fk_workspace_prompt_templates_workspace FOREIGN KEY (workspace_id) REFERENCES workspaces(id),
+					CONSTRAINT fk_workspace_prompt_templates_template FOREIGN KEY (template_id) REFERENCES prompt_templates(id))`,
+				"CREATE INDEX idx_workspace_prompt_templates_template_id ON workspace_prompt_templates(template_id)",
+			)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, template := range builtInPromptTemplates {
+				// Summary templates are still in summary_templates, version 10 moves them here
+				if template.Kind == PromptKindSummary {
+					continue
+				}
+				err := tx.Exec(`INSERT INTO prompt_templates (name, kind, description, body, built_in, version, created_at, updated_at)
+					VALUES (?, ?, ?, ?, true, 1, ?, ?)`, template.Name, template.Kind, template.Description, template.Body, now, now).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 8,
+		Name:    "knowledge base indexing time",
+		Up: func(tx *gorm.DB) error {
+			return execStatements(tx,
+				"ALTER TABLE knowledge_bases ADD COLUMN indexed_at datetime",
+				// Items with chunks were indexed by earlier versions
+				"UPDATE knowledge_bases SET indexed_at = updated_at WHERE id IN (SELECT knowledge_base_id FROM knowledge_chunks)",
+			)
+		},
+	},
+	{
+		Version: 9,
+		Name:    "full-text search index",
+		Up: func(tx *gorm.DB) error {
+			// Builds without FTS5 search with LIKE matching, setupSearchIndex creates the index
+			// the first time the database is opened by a build with FTS5
+			enabled, err := fts5Enabled(tx)
+			if err != nil || !enabled {
+				return err
+			}
+			return execStatements(tx, searchIndexStatements...)
+		},
+	},
+	{
+		Version: 10,
+		Name:    "summary templates as prompt templates",
+		Up: func(tx *gorm.DB) error {
+			var templates []struct {
+				ID           uint
+				Name         string
+				Description  string
+				Instructions string
+				BuiltIn      bool
+				Version      int
+				CreatedAt    time.Time
+				UpdatedAt    time.Time
+			}
+			if err := tx.Raw("SELECT * FROM summary_templates ORDER BY id").Scan(&templates).Error; err != nil {
+				return err
+			}
+
+			for _, template := range templates {
+				// Names are unique across every kind of prompt template
+				name := template.Name
+				for i := 2; ; i++ {
+					var taken int64
+					if err := tx.Raw("SELECT COUNT(*) FROM prompt_templates WHERE name = ?", name).Scan(&taken).Error; err != nil {
+						return err
+					}
+					if taken == 0 {
+						break
+					}
+					name = fmt.Sprintf("%s (%d)", template.Name, i)
+				}
+				// Instructions were plain text, braces in them must not be read as template actions
+				body := template.Instructions
+				if _, err := parsePromptTemplate(name, body); err != nil {
+					body = strings.ReplaceAll(body, "{{", `{{"{{"}}`)
+				}
+
+				err := tx.Exec(`INSERT INTO prompt_templates (name, kind, description, body, built_in, version, created_at, updated_at)
+					VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, name, PromptKindSummary, template.Description, body, template.BuiltIn, template.Version, template.CreatedAt, template.UpdatedAt).Error
+				if err != nil {
+					return err
+				}
+				var id uint
+				if err := tx.Raw("SELECT last_insert_rowid()").Scan(&id).Error; err != nil {
+					return err
+				}
+				// Negated until every summary is remapped, so a new ID can't be taken for an old one
+				if err := tx.Exec("UPDATE meeting_summaries SET template_id = ? WHERE template_id = ?", -int64(id), template.ID).Error; err != nil {
+					return err
+				}
+			}
+			return execStatements(tx,
+				"UPDATE meeting_summaries SET template_id = -template_id WHERE template_id < 0",
+				"DROP TABLE summary_templates",
+			)
+		},
+	},
+}
+
+// execStatements runs SQL statements in order, stopping at the first error
+func execStatements(tx *gorm.DB, statements ...string) error {
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// latestSchemaVersion is the schema version this build of the app expects
+func latestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// currentSchemaVersion returns the highest migration applied to the database, 0 for a new database
+func currentSchemaVersion() (int, error) {
+	var version int
+	err := DB.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// runMigrations brings the database at path up to the latest schema version,
+// backing it up first. It refuses databases written by a newer version of the app.
+func runMigrations(path string) error {
+	if err := DB.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+
+	current, err := currentSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %v", err)
+	}
+	latest := latestSchemaVersion()
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than this app supports (%d), please update YumeSession", current, latest)
+	}
+	if current == latest {
+		return nil
+	}
+
+	// A database that predates versioning has tables but no recorded migrations, so back it up too
+	hasData := current > 0 || DB.Migrator().HasTable(&Workspace{})
+	if hasData {
+		backup, err := backupDatabase(path, current)
+		if err != nil {
+			return fmt.Errorf("failed to back up database before migrating: %v", err)
+		}
+		log.Printf("Backed up database to %s", backup)
+	}
+
+	// Migrations may rebuild tables, which must not trip or cascade foreign keys.
+	// The pragma is per connection and ignored inside a transaction, so pin one connection.
+	return DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+
+		for _, migration := range migrations {
+			if migration.Version <= current {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+			}
+			log.Printf("Applied migration %d: %s", migration.Version, migration.Name)
+		}
+		return nil
+	})
+}
+
+// backupDatabase writes a consistent copy of the database next to the other backups
+// and returns its path
+func backupDatabase(path string, version int) (string, error) {
+	if err := os.MkdirAll(databaseBackupDir, 0755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s.v%d.%s.bak", filepath.Base(path), version, time.Now().Format("20060102-150405"))
+	backup := filepath.Join(databaseBackupDir, name)
+	if err := DB.Exec("VACUUM INTO ?", backup).Error; err != nil {
+		return "", err
+	}
+	return backup, nil
+}
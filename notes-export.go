@@ -0,0 +1,912 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Meeting notes export formats
+const (
+	NotesFormatHTML = "html"
+	NotesFormatPDF  = "pdf"
+	NotesFormatDOCX = "docx"
+)
+
+// NotesExportOptions controls what goes into an exported meeting notes document
+type NotesExportOptions struct {
+	IncludeActionItems bool   `json:"includeActionItems"` // appendix listing the workspace's tracked action items
+	IncludeTranscript  bool   `json:"includeTranscript"`  // appendix with the workspace transcript
+	Path               string `json:"path"`               // where to save the document, asks when empty
+}
+
+// Kinds of notesBlock
+const (
+	notesBlockHeading   = "heading"
+	notesBlockParagraph = "paragraph"
+	notesBlockItem      = "item"
+	notesBlockCode      = "code"
+	notesBlockQuote     = "quote"
+	notesBlockRule      = "rule"
+	notesBlockTable     = "table"
+)
+
+// notesRun is a piece of text with a single style
+type notesRun struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Code   bool
+	Link   string
+}
+
+// notesBlock is a block of a meeting notes document, the format-independent form
+// every exporter renders from
+type notesBlock struct {
+	Kind   string
+	Level  int    // heading level, or nesting depth of a list item
+	Marker string // bullet, number or checkbox of a list item
+	Runs   []notesRun
+	Rows   [][]string // table cells, header row first
+}
+
+// notesDocument is a meeting notes document ready to be rendered
+type notesDocument struct {
+	Title  string
+	Date   time.Time
+	Blocks []notesBlock
+}
+
+// Markdown parser for meeting notes, with tables and task lists
+var notesMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// Checkbox markers of task list items
+const (
+	taskOpenMarker = "[ ]"
+	taskDoneMarker = "[x]"
+)
+
+// notesBuilder turns a Markdown syntax tree into notesBlocks
+type notesBuilder struct {
+	source []byte
+	blocks []notesBlock
+}
+
+// parseNotesMarkdown converts Markdown to notesBlocks
+func parseNotesMarkdown(source string) []notesBlock {
+	b := &notesBuilder{source: []byte(source)}
+	doc := notesMarkdown.Parser().Parse(text.NewReader(b.source))
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		b.block(n, 0, false)
+	}
+	return b.blocks
+}
+
+// block converts one block node, at list nesting depth, inside a blockquote if quote is set
+func (b *notesBuilder) block(n ast.Node, depth int, quote bool) {
+	switch node := n.(type) {
+	case *ast.Heading:
+		b.blocks = append(b.blocks, notesBlock{Kind: notesBlockHeading, Level: node.Level, Runs: b.inline(node, notesRun{})})
+
+	case *ast.Paragraph, *ast.TextBlock:
+		kind := notesBlockParagraph
+		if quote {
+			kind = notesBlockQuote
+		}
+		b.blocks = append(b.blocks, notesBlock{Kind: kind, Level: depth, Runs: b.inline(node, notesRun{})})
+
+	case *ast.List:
+		number := node.Start
+		for item := node.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "•"
+			if node.IsOrdered() {
+				marker = fmt.Sprintf("%d.", number)
+				number++
+			}
+			b.listItem(item, depth+1, marker, quote)
+		}
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		var code strings.Builder
+		lines := node.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			segment := lines.At(i)
+			code.Write(segment.Value(b.source))
+		}
+		b.blocks = append(b.blocks, notesBlock{
+			Kind: notesBlockCode,
+			Runs: []notesRun{{Text: strings.TrimRight(code.String(), "\n"), Code: true}},
+		})
+
+	case *ast.Blockquote:
+		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+			b.block(child, depth, true)
+		}
+
+	case *ast.ThematicBreak:
+		b.blocks = append(b.blocks, notesBlock{Kind: notesBlockRule})
+
+	case *east.Table:
+		table := notesBlock{Kind: notesBlockTable}
+		for row := node.FirstChild(); row != nil; row = row.NextSibling() {
+			var cells []string
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				cells = append(cells, runsText(b.inline(cell, notesRun{})))
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+		b.blocks = append(b.blocks, table)
+	}
+}
+
+// listItem converts a list item: its first paragraph carries the marker, the rest is nested
+func (b *notesBuilder) listItem(item ast.Node, depth int, marker string, quote bool) {
+	first := true
+	for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+		_, isParagraph := child.(*ast.Paragraph)
+		_, isTextBlock := child.(*ast.TextBlock)
+		if first && (isParagraph || isTextBlock) {
+			if checkbox, ok := child.FirstChild().(*east.TaskCheckBox); ok {
+				marker = taskOpenMarker
+				if checkbox.IsChecked {
+					marker = taskDoneMarker
+				}
+			}
+			b.blocks = append(b.blocks, notesBlock{Kind: notesBlockItem, Level: depth, Marker: marker, Runs: b.inline(child, notesRun{})})
+			first = false
+			continue
+		}
+		b.block(child, depth, quote)
+	}
+	if first {
+		b.blocks = append(b.blocks, notesBlock{Kind: notesBlockItem, Level: depth, Marker: marker})
+	}
+}
+
+// inline flattens the inline children of n into runs, starting from style
+func (b *notesBuilder) inline(n ast.Node, style notesRun) []notesRun {
+	var runs []notesRun
+	add := func(run notesRun) {
+		if run.Text == "" {
+			return
+		}
+		if last := len(runs) - 1; last >= 0 {
+			prev := runs[last]
+			if prev.Bold == run.Bold && prev.Italic == run.Italic && prev.Code == run.Code && prev.Link == run.Link {
+				runs[last].Text += run.Text
+				return
+			}
+		}
+		runs = append(runs, run)
+	}
+
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		switch node := child.(type) {
+		case *ast.Text:
+			run := style
+			run.Text = string(node.Segment.Value(b.source))
+			if node.HardLineBreak() {
+				run.Text += "\n"
+			} else if node.SoftLineBreak() {
+				run.Text += " "
+			}
+			add(run)
+		case *ast.String:
+			run := style
+			run.Text = string(node.Value)
+			add(run)
+		case *ast.Emphasis:
+			nested := style
+			if node.Level >= 2 {
+				nested.Bold = true
+			} else {
+				nested.Italic = true
+			}
+			for _, run := range b.inline(node, nested) {
+				add(run)
+			}
+		case *ast.CodeSpan:
+			nested := style
+			nested.Code = true
+			for _, run := range b.inline(node, nested) {
+				add(run)
+			}
+		case *ast.Link:
+			nested := style
+			nested.Link = string(node.Destination)
+			for _, run := range b.inline(node, nested) {
+				add(run)
+			}
+		case *ast.AutoLink:
+			run := style
+			run.Text = string(node.Label(b.source))
+			run.Link = string(node.URL(b.source))
+			add(run)
+		case *ast.Image, *east.Strikethrough:
+			for _, run := range b.inline(node, style) {
+				add(run)
+			}
+		}
+	}
+
+	// Trailing line breaks of a paragraph aren't content
+	if last := len(runs) - 1; last >= 0 {
+		runs[last].Text = strings.TrimRight(runs[last].Text, " \n")
+	}
+	return runs
+}
+
+// runsText joins the text of runs
+func runsText(runs []notesRun) string {
+	var b strings.Builder
+	for _, run := range runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// Status labels of action items in the appendix
+var actionItemStatusLabels = map[string]string{
+	ActionItemOpen:      "Open",
+	ActionItemDone:      "Done",
+	ActionItemCancelled: "Cancelled",
+}
+
+// actionItemsAppendix lists the action items tracked for a workspace, oldest first, as a table
+func actionItemsAppendix(workspaceID uint) ([]notesBlock, error) {
+	items, err := ListActionItems(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return []notesBlock{{Kind: notesBlockParagraph, Runs: []notesRun{{Text: "No action items were recorded.", Italic: true}}}}, nil
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+
+	table := notesBlock{Kind: notesBlockTable, Rows: [][]string{{"Action item", "Owner", "Due", "Status"}}}
+	for _, item := range items {
+		due := ""
+		if item.DueDate != nil {
+			// Due dates are calendar days, converting them to local time could move them
+			due = item.DueDate.Format("2 January 2006")
+		}
+		table.Rows = append(table.Rows, []string{item.Text, item.Owner, due, actionItemStatusLabels[item.Status]})
+	}
+	return []notesBlock{table}, nil
+}
+
+// buildNotesDocument loads meeting notes and the requested appendices
+func buildNotesDocument(id uint, options NotesExportOptions) (*notesDocument, error) {
+	notes, err := GetMeetingNotesByID(id)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := GetWorkspaceByID(notes.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &notesDocument{
+		Title:  workspace.Title,
+		Date:   notes.UpdatedAt,
+		Blocks: parseNotesMarkdown(notes.Text),
+	}
+
+	if options.IncludeActionItems {
+		items, err := actionItemsAppendix(notes.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		doc.Blocks = append(doc.Blocks, notesBlock{Kind: notesBlockHeading, Level: 1, Runs: []notesRun{{Text: "Appendix: Action Items"}}})
+		doc.Blocks = append(doc.Blocks, items...)
+	}
+
+	if options.IncludeTranscript {
+		transcript, err := buildTranscriptDocument(notes.WorkspaceID, TranscriptExportOptions{MergeConsecutive: true})
+		if err != nil {
+			return nil, err
+		}
+		doc.Blocks = append(doc.Blocks, notesBlock{Kind: notesBlockHeading, Level: 1, Runs: []notesRun{{Text: "Appendix: Transcript"}}})
+		for _, segment := range transcript.Segments {
+			doc.Blocks = append(doc.Blocks, notesBlock{Kind: notesBlockParagraph, Runs: []notesRun{
+				{Text: segment.Timestamp.Local().Format("15:04:05") + "  ", Code: true},
+				{Text: segment.Speaker + ": ", Bold: true},
+				{Text: segment.Text},
+			}})
+		}
+	}
+	return doc, nil
+}
+
+// RenderMeetingNotes renders meeting notes as a standalone HTML, PDF or DOCX document
+func RenderMeetingNotes(id uint, format string, options NotesExportOptions) ([]byte, error) {
+	doc, err := buildNotesDocument(id, options)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(format) {
+	case NotesFormatHTML:
+		return renderNotesHTML(doc), nil
+	case NotesFormatPDF:
+		return renderNotesPDF(doc)
+	case NotesFormatDOCX:
+		return renderNotesDOCX(doc)
+	default:
+		return nil, fmt.Errorf("unsupported meeting notes format %q", format)
+	}
+}
+
+// HTML
+
+const notesHTMLStyle = `body{font-family:-apple-system,"Segoe UI",Roboto,Helvetica,Arial,sans-serif;max-width:760px;margin:40px auto;padding:0 24px;color:#222;line-height:1.55}
+header{border-bottom:2px solid #ffd700;margin-bottom:24px}header h1{margin:0 0 4px}.meta{color:#777;margin:0 0 12px}
+.item{margin:4px 0}.marker{display:inline-block;min-width:1.6em;color:#555}
+pre{background:#f5f5f5;padding:12px;border-radius:6px;overflow-x:auto}code{font-family:Consolas,Menlo,monospace;font-size:.92em}
+blockquote{border-left:4px solid #ddd;margin:8px 0;padding:2px 12px;color:#555}
+table{border-collapse:collapse;margin:12px 0}th,td{border:1px solid #ccc;padding:4px 8px;text-align:left}th{background:#f5f5f5}`
+
+// renderNotesHTML writes a standalone HTML page
+func renderNotesHTML(doc *notesDocument) []byte {
+	var b strings.Builder
+	title := html.EscapeString(doc.Title)
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s — Meeting Notes</title>\n<style>%s</style>\n</head>\n<body>\n", title, notesHTMLStyle)
+	fmt.Fprintf(&b, "<header><h1>%s</h1><p class=\"meta\">Meeting notes · %s</p></header>\n", title, html.EscapeString(doc.Date.Local().Format("2 January 2006 15:04")))
+
+	for _, block := range doc.Blocks {
+		switch block.Kind {
+		case notesBlockHeading:
+			level := block.Level + 1 // the document title is the only h1
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, htmlRuns(block.Runs), level)
+		case notesBlockParagraph:
+			fmt.Fprintf(&b, "<p>%s</p>\n", htmlRuns(block.Runs))
+		case notesBlockItem:
+			fmt.Fprintf(&b, "<div class=\"item\" style=\"margin-left:%.1fem\"><span class=\"marker\">%s</span>%s</div>\n",
+				1.5*float64(block.Level-1), html.EscapeString(block.Marker), htmlRuns(block.Runs))
+		case notesBlockCode:
+			fmt.Fprintf(&b, "<pre><code>%s</code></pre>\n", html.EscapeString(runsText(block.Runs)))
+		case notesBlockQuote:
+			fmt.Fprintf(&b, "<blockquote>%s</blockquote>\n", htmlRuns(block.Runs))
+		case notesBlockRule:
+			b.WriteString("<hr>\n")
+		case notesBlockTable:
+			b.WriteString("<table>\n")
+			for i, row := range block.Rows {
+				cell := "td"
+				if i == 0 {
+					cell = "th"
+				}
+				b.WriteString("<tr>")
+				for _, value := range row {
+					fmt.Fprintf(&b, "<%s>%s</%s>", cell, html.EscapeString(value), cell)
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</table>\n")
+		}
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return []byte(b.String())
+}
+
+// htmlRuns renders runs as escaped inline HTML
+func htmlRuns(runs []notesRun) string {
+	var b strings.Builder
+	for _, run := range runs {
+		content := strings.ReplaceAll(html.EscapeString(run.Text), "\n", "<br>")
+		if run.Code {
+			content = "<code>" + content + "</code>"
+		}
+		if run.Italic {
+			content = "<em>" + content + "</em>"
+		}
+		if run.Bold {
+			content = "<strong>" + content + "</strong>"
+		}
+		if run.Link != "" {
+			content = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(run.Link), content)
+		}
+		b.WriteString(content)
+	}
+	return b.String()
+}
+
+// PDF
+
+// Layout of exported PDFs, in millimetres and points
+const (
+	pdfMargin     = 20.0
+	pdfIndent     = 6.0
+	pdfLineHeight = 5.5
+	pdfFontSize   = 11.0
+)
+
+// Fonts embedded in exported PDFs. The built-in PDF fonts only cover Windows-1252.
+const (
+	pdfSansFont = "DejaVuSans"
+	pdfMonoFont = "DejaVuSansMono"
+)
+
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	pdfSansRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	pdfSansBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	pdfSansItalic []byte
+	//go:embed fonts/DejaVuSansCondensed-BoldOblique.ttf
+	pdfSansBoldItalic []byte
+	//go:embed fonts/DejaVuSansMono.ttf
+	pdfMono []byte
+)
+
+// pdfFontCoverage parses the regular sans and mono fonts, to find characters they can't draw
+var pdfFontCoverage = sync.OnceValues(func() ([2]*sfnt.Font, error) {
+	sans, err := sfnt.Parse(pdfSansRegular)
+	if err != nil {
+		return [2]*sfnt.Font{}, err
+	}
+	mono, err := sfnt.Parse(pdfMono)
+	return [2]*sfnt.Font{sans, mono}, err
+})
+
+// missingPDFGlyphs returns the characters of doc the PDF fonts have no glyph for
+func missingPDFGlyphs(doc *notesDocument) ([]string, error) {
+	fonts, err := pdfFontCoverage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PDF fonts: %v", err)
+	}
+
+	var buf sfnt.Buffer
+	seen := make(map[rune]bool)
+	var missing []string
+	check := func(text string, mono bool) {
+		font := fonts[0]
+		if mono {
+			font = fonts[1]
+		}
+		for _, r := range text {
+			if seen[r] || unicode.IsSpace(r) || unicode.IsControl(r) {
+				continue
+			}
+			seen[r] = true
+			if glyph, err := font.GlyphIndex(&buf, r); err != nil || glyph == 0 {
+				missing = append(missing, string(r))
+			}
+		}
+	}
+
+	check(doc.Title, false)
+	for _, block := range doc.Blocks {
+		check(block.Marker, false)
+		for _, run := range block.Runs {
+			check(run.Text, run.Code || block.Kind == notesBlockCode)
+		}
+		for _, row := range block.Rows {
+			for _, cell := range row {
+				check(cell, false)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// renderNotesPDF lays the document out on A4 pages with the embedded DejaVu fonts. Notes
+// using characters the fonts don't cover (CJK, emoji) are refused rather than exported with
+// the characters missing.
+func renderNotesPDF(doc *notesDocument) ([]byte, error) {
+	missing, err := missingPDFGlyphs(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		if len(missing) > 10 {
+			missing = append(missing[:10], "…")
+		}
+		return nil, fmt.Errorf("the PDF font cannot display %s, export the notes as HTML or DOCX instead", strings.Join(missing, " "))
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfSansFont, "", pdfSansRegular)
+	pdf.AddUTF8FontFromBytes(pdfSansFont, "B", pdfSansBold)
+	pdf.AddUTF8FontFromBytes(pdfSansFont, "I", pdfSansItalic)
+	pdf.AddUTF8FontFromBytes(pdfSansFont, "BI", pdfSansBoldItalic)
+	// Code is set in the regular mono face whatever the surrounding style
+	for _, style := range []string{"", "B", "I", "BI"} {
+		pdf.AddUTF8FontFromBytes(pdfMonoFont, style, pdfMono)
+	}
+	pdf.SetTitle(doc.Title+" — Meeting Notes", true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont(pdfSansFont, "I", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 10, fmt.Sprintf("%s · page %d", doc.Title, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont(pdfSansFont, "B", 20)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.MultiCell(0, 9, doc.Title, "", "L", false)
+	pdf.SetFont(pdfSansFont, "", 10)
+	pdf.SetTextColor(120, 120, 120)
+	pdf.CellFormat(0, 6, "Meeting notes · "+doc.Date.Local().Format("2 January 2006 15:04"), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	writeRuns := func(runs []notesRun, size, lineHeight float64, baseStyle string) {
+		for _, run := range runs {
+			family, style := pdfSansFont, baseStyle
+			if run.Code {
+				family = pdfMonoFont
+			}
+			if run.Bold && !strings.Contains(style, "B") {
+				style += "B"
+			}
+			if run.Italic && !strings.Contains(style, "I") {
+				style += "I"
+			}
+			pdf.SetFont(family, style, size)
+			if run.Link != "" {
+				pdf.SetTextColor(30, 90, 200)
+				pdf.WriteLinkString(lineHeight, run.Text, run.Link)
+				pdf.SetTextColor(0, 0, 0)
+			} else {
+				pdf.Write(lineHeight, run.Text)
+			}
+		}
+	}
+
+	for _, block := range doc.Blocks {
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetLeftMargin(pdfMargin)
+		pdf.SetX(pdfMargin)
+
+		switch block.Kind {
+		case notesBlockHeading:
+			size := map[int]float64{1: 16, 2: 14, 3: 12.5}[block.Level]
+			if size == 0 {
+				size = 11.5
+			}
+			pdf.Ln(3)
+			writeRuns(block.Runs, size, size*0.5, "B")
+			pdf.Ln(size*0.5 + 2)
+
+		case notesBlockParagraph:
+			writeRuns(block.Runs, pdfFontSize, pdfLineHeight, "")
+			pdf.Ln(pdfLineHeight + 2)
+
+		case notesBlockItem:
+			indent := pdfMargin + pdfIndent*float64(block.Level-1)
+			pdf.SetX(indent)
+			pdf.SetFont(pdfSansFont, "", pdfFontSize)
+			pdf.CellFormat(pdfIndent, pdfLineHeight, block.Marker, "", 0, "L", false, 0, "")
+			pdf.SetLeftMargin(indent + pdfIndent)
+			writeRuns(block.Runs, pdfFontSize, pdfLineHeight, "")
+			pdf.Ln(pdfLineHeight + 1)
+
+		case notesBlockCode:
+			pdf.SetFont(pdfMonoFont, "", 9)
+			pdf.SetFillColor(245, 245, 245)
+			pdf.MultiCell(0, 4.5, runsText(block.Runs), "", "L", true)
+			pdf.Ln(2)
+
+		case notesBlockQuote:
+			pdf.SetLeftMargin(pdfMargin + pdfIndent)
+			pdf.SetX(pdfMargin + pdfIndent)
+			pdf.SetTextColor(90, 90, 90)
+			writeRuns(block.Runs, pdfFontSize, pdfLineHeight, "I")
+			pdf.Ln(pdfLineHeight + 2)
+
+		case notesBlockRule:
+			width, _ := pdf.GetPageSize()
+			y := pdf.GetY() + 2
+			pdf.SetDrawColor(200, 200, 200)
+			pdf.Line(pdfMargin, y, width-pdfMargin, y)
+			pdf.Ln(5)
+
+		case notesBlockTable:
+			renderPDFTable(pdf, block.Rows)
+			pdf.Ln(3)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPDFTable draws a table with equal column widths, wrapping cell text
+func renderPDFTable(pdf *fpdf.Fpdf, rows [][]string) {
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return
+	}
+
+	const cellLineHeight = 5.0
+	width, _ := pdf.GetPageSize()
+	cellWidth := (width - 2*pdfMargin) / float64(columns)
+	pdf.SetDrawColor(180, 180, 180)
+
+	for i, row := range rows {
+		style := ""
+		if i == 0 {
+			style = "B"
+		}
+		pdf.SetFont(pdfSansFont, style, 10)
+
+		lines := make([][]string, columns)
+		height := cellLineHeight
+		for c := 0; c < columns; c++ {
+			value := ""
+			if c < len(row) {
+				value = row[c]
+			}
+			lines[c] = pdf.SplitText(value, cellWidth-2)
+			if h := float64(len(lines[c])) * cellLineHeight; h > height {
+				height = h
+			}
+		}
+
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+height > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		x, y := pdfMargin, pdf.GetY()
+		for c := 0; c < columns; c++ {
+			pdf.Rect(x, y, cellWidth, height, "D")
+			pdf.SetXY(x+1, y)
+			pdf.MultiCell(cellWidth-2, cellLineHeight, strings.Join(lines[c], "\n"), "", "L", false)
+			x += cellWidth
+		}
+		pdf.SetXY(pdfMargin, y+height)
+	}
+}
+
+// DOCX
+
+// renderNotesDOCX writes a minimal WordprocessingML package
+func renderNotesDOCX(doc *notesDocument) ([]byte, error) {
+	var body strings.Builder
+	var links []string
+
+	docxParagraph(&body, "Title", "", []notesRun{{Text: doc.Title}}, &links)
+	docxParagraph(&body, "Subtitle", "", []notesRun{{Text: "Meeting notes · " + doc.Date.Local().Format("2 January 2006 15:04")}}, &links)
+
+	for _, block := range doc.Blocks {
+		switch block.Kind {
+		case notesBlockHeading:
+			level := block.Level
+			if level > 3 {
+				level = 3
+			}
+			docxParagraph(&body, fmt.Sprintf("Heading%d", level), "", block.Runs, &links)
+		case notesBlockParagraph:
+			docxParagraph(&body, "", "", block.Runs, &links)
+		case notesBlockItem:
+			indent := fmt.Sprintf(`<w:ind w:left="%d" w:hanging="360"/>`, 360*block.Level)
+			runs := append([]notesRun{{Text: block.Marker + "\t"}}, block.Runs...)
+			docxParagraph(&body, "ListParagraph", indent, runs, &links)
+		case notesBlockCode:
+			docxParagraph(&body, "Code", "", block.Runs, &links)
+		case notesBlockQuote:
+			docxParagraph(&body, "Quote", "", block.Runs, &links)
+		case notesBlockRule:
+			body.WriteString(`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="BBBBBB"/></w:pBdr></w:pPr></w:p>`)
+		case notesBlockTable:
+			docxTable(&body, block.Rows)
+		}
+	}
+
+	var relationships strings.Builder
+	relationships.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	for i, link := range links {
+		fmt.Fprintf(&relationships, `<Relationship Id="rIdLink%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`, i+1, xmlEscape(link))
+	}
+	relationships.WriteString(`</Relationships>`)
+
+	document := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<w:body>` + body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="709" w:footer="709" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxPackageRelationships},
+		{"word/document.xml", document},
+		{"word/styles.xml", docxStyles},
+		{"word/_rels/document.xml.rels", relationships.String()},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// xmlEscape escapes text for use in XML content and attributes
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// docxParagraph writes a paragraph, registering its hyperlinks in links
+func docxParagraph(body *strings.Builder, style, properties string, runs []notesRun, links *[]string) {
+	body.WriteString("<w:p>")
+	if style != "" || properties != "" {
+		body.WriteString("<w:pPr>")
+		if style != "" {
+			fmt.Fprintf(body, `<w:pStyle w:val="%s"/>`, style)
+		}
+		body.WriteString(properties)
+		body.WriteString("</w:pPr>")
+	}
+
+	for _, run := range runs {
+		var props strings.Builder
+		if run.Link != "" {
+			props.WriteString(`<w:rStyle w:val="Hyperlink"/>`)
+		}
+		if run.Bold {
+			props.WriteString("<w:b/>")
+		}
+		if run.Italic {
+			props.WriteString("<w:i/>")
+		}
+		if run.Code {
+			props.WriteString(`<w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/>`)
+		}
+
+		var content strings.Builder
+		for i, line := range strings.Split(run.Text, "\n") {
+			if i > 0 {
+				content.WriteString("<w:br/>")
+			}
+			for j, part := range strings.Split(line, "\t") {
+				if j > 0 {
+					content.WriteString("<w:tab/>")
+				}
+				if part != "" {
+					fmt.Fprintf(&content, `<w:t xml:space="preserve">%s</w:t>`, xmlEscape(part))
+				}
+			}
+		}
+		r := fmt.Sprintf("<w:r><w:rPr>%s</w:rPr>%s</w:r>", props.String(), content.String())
+
+		if run.Link != "" {
+			*links = append(*links, run.Link)
+			fmt.Fprintf(body, `<w:hyperlink r:id="rIdLink%d">%s</w:hyperlink>`, len(*links), r)
+		} else {
+			body.WriteString(r)
+		}
+	}
+	body.WriteString("</w:p>")
+}
+
+// docxTable writes a bordered table whose first row is bold
+func docxTable(body *strings.Builder, rows [][]string) {
+	body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/>` +
+		`<w:tblBorders><w:top w:val="single" w:sz="4" w:color="BBBBBB"/><w:left w:val="single" w:sz="4" w:color="BBBBBB"/>` +
+		`<w:bottom w:val="single" w:sz="4" w:color="BBBBBB"/><w:right w:val="single" w:sz="4" w:color="BBBBBB"/>` +
+		`<w:insideH w:val="single" w:sz="4" w:color="BBBBBB"/><w:insideV w:val="single" w:sz="4" w:color="BBBBBB"/></w:tblBorders></w:tblPr>`)
+	for i, row := range rows {
+		body.WriteString("<w:tr>")
+		for _, value := range row {
+			body.WriteString("<w:tc><w:tcPr><w:tcW w:w=\"0\" w:type=\"auto\"/></w:tcPr>")
+			var unused []string
+			docxParagraph(body, "", "", []notesRun{{Text: value, Bold: i == 0}}, &unused)
+			body.WriteString("</w:tc>")
+		}
+		body.WriteString("</w:tr>")
+	}
+	body.WriteString("</w:tbl><w:p/>")
+}
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`</Types>`
+
+const docxPackageRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:cs="Calibri"/><w:sz w:val="22"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="60"/></w:pPr><w:rPr><w:b/><w:sz w:val="44"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="12" w:space="4" w:color="FFD700"/></w:pBdr><w:spacing w:after="240"/></w:pPr><w:rPr><w:color w:val="777777"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="80"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="32"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="28"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="160" w:after="60"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:sz w:val="24"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="40"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/><w:basedOn w:val="Normal"/><w:pPr><w:shd w:val="clear" w:color="auto" w:fill="F5F5F5"/></w:pPr><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:sz w:val="19"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:pPr><w:ind w:left="567"/><w:pBdr><w:left w:val="single" w:sz="18" w:space="8" w:color="DDDDDD"/></w:pBdr></w:pPr><w:rPr><w:i/><w:color w:val="555555"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:rPr><w:color w:val="1E5AC8"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblCellMar><w:left w:w="108" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+	`</w:styles>`
+
+// ExportMeetingNotes saves meeting notes as an HTML, PDF or DOCX document, asking where to
+// save it unless options.Path is set. It returns the path written, or an empty string if
+// the dialog was cancelled.
+func (a *App) ExportMeetingNotes(id uint, format string, options NotesExportOptions) (string, error) {
+	content, err := RenderMeetingNotes(id, format, options)
+	if err != nil {
+		log.Printf("Failed to export meeting notes %d: %v", id, err)
+		return "", err
+	}
+
+	path := options.Path
+	if path == "" {
+		name := "Meeting Notes"
+		if notes, err := GetMeetingNotesByID(id); err == nil {
+			if workspace, err := GetWorkspaceByID(notes.WorkspaceID); err == nil {
+				name = workspace.Title + " - Meeting Notes"
+			}
+		}
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Export Meeting Notes",
+			DefaultFilename: name + "." + strings.ToLower(format),
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		log.Printf("Failed to write meeting notes to %s: %v", path, err)
+		return "", err
+	}
+	return path, nil
+}
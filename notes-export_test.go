@@ -0,0 +1,166 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const exportTestNotes = "# Budget\n\nWe agreed on **40%** for *marketing*, see `plan.md` and [the sheet](https://example.com/sheet).\n\n" +
+	"- Hiring\n  - Two engineers\n3. First\n4. Second\n\n- [ ] Send the deck\n- [x] Book the room\n\n" +
+	"> Keep it simple\n\n---\n\n```\ngo test ./...\n```\n\n| Team | Share |\n|---|---|\n| Marketing | 40% |\n"
+
+func TestParseNotesMarkdown(t *testing.T) {
+	want := []notesBlock{
+		{Kind: notesBlockHeading, Level: 1, Runs: []notesRun{{Text: "Budget"}}},
+		{Kind: notesBlockParagraph, Runs: []notesRun{
+			{Text: "We agreed on "}, {Text: "40%", Bold: true}, {Text: " for "}, {Text: "marketing", Italic: true},
+			{Text: ", see "}, {Text: "plan.md", Code: true}, {Text: " and "},
+			{Text: "the sheet", Link: "https://example.com/sheet"}, {Text: "."},
+		}},
+		{Kind: notesBlockItem, Level: 1, Marker: "•", Runs: []notesRun{{Text: "Hiring"}}},
+		{Kind: notesBlockItem, Level: 2, Marker: "•", Runs: []notesRun{{Text: "Two engineers"}}},
+		{Kind: notesBlockItem, Level: 1, Marker: "3.", Runs: []notesRun{{Text: "First"}}},
+		{Kind: notesBlockItem, Level: 1, Marker: "4.", Runs: []notesRun{{Text: "Second"}}},
+		{Kind: notesBlockItem, Level: 1, Marker: taskOpenMarker, Runs: []notesRun{{Text: "Send the deck"}}},
+		{Kind: notesBlockItem, Level: 1, Marker: taskDoneMarker, Runs: []notesRun{{Text: "Book the room"}}},
+		{Kind: notesBlockQuote, Runs: []notesRun{{Text: "Keep it simple"}}},
+		{Kind: notesBlockRule},
+		{Kind: notesBlockCode, Runs: []notesRun{{Text: "go test ./...", Code: true}}},
+		{Kind: notesBlockTable, Rows: [][]string{{"Team", "Share"}, {"Marketing", "40%"}}},
+	}
+
+	got := parseNotesMarkdown(exportTestNotes)
+	if len(got) != len(want) {
+		t.Fatalf("got %d blocks, want %d:\n%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("block %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuildNotesDocumentActionItems(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Budget", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := CreateMeetingNotes(workspace.ID, "- [ ] Send the deck")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := buildNotesDocument(notes.ID, NotesExportOptions{IncludeActionItems: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := doc.Blocks[len(doc.Blocks)-1]; last.Kind != notesBlockParagraph || runsText(last.Runs) != "No action items were recorded." {
+		t.Errorf("appendix without action items = %+v", last)
+	}
+
+	due := time.Date(2026, 11, 2, 0, 0, 0, 0, time.Local)
+	start := time.Now()
+	items := []ActionItem{
+		{WorkspaceID: workspace.ID, Text: "Share the budget sheet", Owner: "Alice", DueDate: &due, Status: ActionItemOpen, CreatedAt: start},
+		{WorkspaceID: workspace.ID, Text: "Book the room", Status: ActionItemDone, CreatedAt: start.Add(time.Minute)},
+	}
+	if err := DB.Create(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err = buildNotesDocument(notes.ID, NotesExportOptions{IncludeActionItems: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Blocks) != 3 {
+		t.Fatalf("blocks = %+v, want the checkbox, the appendix heading and its table", doc.Blocks)
+	}
+	if heading := doc.Blocks[1]; heading.Kind != notesBlockHeading || runsText(heading.Runs) != "Appendix: Action Items" {
+		t.Errorf("appendix heading = %+v", heading)
+	}
+	wantRows := [][]string{
+		{"Action item", "Owner", "Due", "Status"},
+		{"Share the budget sheet", "Alice", "2 November 2026", "Open"},
+		{"Book the room", "", "", "Done"},
+	}
+	if table := doc.Blocks[2]; table.Kind != notesBlockTable || !reflect.DeepEqual(table.Rows, wantRows) {
+		t.Errorf("appendix table = %+v, want rows %q", table, wantRows)
+	}
+}
+
+func TestRenderNotes(t *testing.T) {
+	doc := &notesDocument{
+		Title:  "Budget <Q3>",
+		Date:   time.Date(2026, 10, 17, 9, 30, 0, 0, time.Local),
+		Blocks: parseNotesMarkdown(exportTestNotes),
+	}
+
+	page := string(renderNotesHTML(doc))
+	for _, want := range []string{
+		"<title>Budget &lt;Q3&gt; — Meeting Notes</title>",
+		"<h2>Budget</h2>",
+		"<strong>40%</strong>",
+		"<em>marketing</em>",
+		`<a href="https://example.com/sheet">the sheet</a>`,
+		`<span class="marker">[x]</span>Book the room`,
+		"<pre><code>go test ./...</code></pre>",
+		"<tr><th>Team</th><th>Share</th></tr>",
+		"17 October 2026 09:30",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML is missing %q", want)
+		}
+	}
+
+	pdf, err := renderNotesPDF(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("PDF starts with %q", pdf[:min(len(pdf), 8)])
+	}
+	cjk := &notesDocument{Title: "予算", Blocks: parseNotesMarkdown("会議")}
+	if _, err := renderNotesPDF(cjk); err == nil || !strings.Contains(err.Error(), "予") {
+		t.Errorf("PDF with characters the font lacks: err = %v", err)
+	}
+
+	docx, err := renderNotesDOCX(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(docx), int64(len(docx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = string(content)
+	}
+	document := files["word/document.xml"]
+	for _, want := range []string{"Budget &lt;Q3&gt;", "Heading1", "Send the deck", "<w:tbl>", "Marketing"} {
+		if !strings.Contains(document, want) {
+			t.Errorf("document.xml is missing %q", want)
+		}
+	}
+	if !strings.Contains(files["word/_rels/document.xml.rels"], `Target="https://example.com/sheet"`) {
+		t.Errorf("the link has no relationship:\n%s", files["word/_rels/document.xml.rels"])
+	}
+	if _, ok := files["[Content_Types].xml"]; !ok {
+		t.Error("DOCX has no [Content_Types].xml")
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// NotesHunk is one change the markdown agent proposes to meeting notes
+type NotesHunk struct {
+	Index    int      `json:"index"`
+	Start    int      `json:"start"`    // first line of the current notes it replaces, 0-based
+	End      int      `json:"end"`      // line after the last one it replaces
+	Current  []string `json:"current"`  // lines of the current notes it replaces
+	Proposed []string `json:"proposed"` // lines the agent proposes instead
+	Conflict bool     `json:"conflict"` // the user changed these lines while the agent was generating
+}
+
+// NotesPatch is the output of the markdown agent for meeting notes, as changes to review
+type NotesPatch struct {
+	ID        string      `json:"id"` // request ID of the agent generation
+	NotesID   uint        `json:"notesId"`
+	Hunks     []NotesHunk `json:"hunks"`
+	Conflicts int         `json:"conflicts"`
+	CreatedAt time.Time   `json:"createdAt"`
+
+	text string // notes the hunks apply to
+}
+
+// notesPatchStore keeps the patches waiting for review, the latest one per meeting notes
+type notesPatchStore struct {
+	mutex   sync.Mutex
+	patches map[uint]*NotesPatch
+}
+
+var pendingNotesPatches = &notesPatchStore{patches: make(map[uint]*NotesPatch)}
+
+// put stores a patch, replacing the one pending for the same notes
+func (s *notesPatchStore) put(patch *NotesPatch) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.patches[patch.NotesID] = patch
+}
+
+// get returns the patch pending for meeting notes, or nil
+func (s *notesPatchStore) get(notesID uint) *NotesPatch {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.patches[notesID]
+}
+
+// find returns the pending patch with the given ID, or nil
+func (s *notesPatchStore) find(patchID string) *NotesPatch {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, patch := range s.patches {
+		if patch.ID == patchID {
+			return patch
+		}
+	}
+	return nil
+}
+
+// take removes and returns the pending patch with the given ID, or nil
+func (s *notesPatchStore) take(patchID string) *NotesPatch {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for notesID, patch := range s.patches {
+		if patch.ID == patchID {
+			delete(s.patches, notesID)
+			return patch
+		}
+	}
+	return nil
+}
+
+// notesAgentRequest is a meeting notes generation in progress
+type notesAgentRequest struct {
+	NotesID uint
+	Base    string // notes sent to the agent
+	Live    bool   // sent by the live note-taker, clean changes are applied without review
+	Output  strings.Builder
+	Saved   bool // the live note-taker output was saved
+
+	done chan struct{} // closed once the generation is over, if set
+}
+
+// finish reports that the generation is over, however it ended
+func (r *notesAgentRequest) finish() {
+	if r != nil && r.done != nil {
+		close(r.done)
+	}
+}
+
+// lineHunk replaces lines [start, end) of a base text with lines
+type lineHunk struct {
+	start, end int
+	lines      []string
+}
+
+// lineHunks groups the diff from base to other into hunks
+func lineHunks(base, other []string) []lineHunk {
+	var hunks []lineHunk
+	var current *lineHunk
+	pos := 0
+	for _, line := range diffLines(base, other) {
+		if line.Op == DiffEqual {
+			if current != nil {
+				hunks = append(hunks, *current)
+				current = nil
+			}
+			pos++
+			continue
+		}
+		if current == nil {
+			current = &lineHunk{start: pos, end: pos}
+		}
+		if line.Op == DiffDelete {
+			pos++
+			current.end = pos
+		} else {
+			current.lines = append(current.lines, line.Text)
+		}
+	}
+	if current != nil {
+		hunks = append(hunks, *current)
+	}
+	return hunks
+}
+
+// mergeRegion is a stretch of the base changed on at least one side of a three-way merge,
+// with the matching line ranges of both sides
+type mergeRegion struct {
+	baseStart, baseEnd         int
+	oursStart, oursEnd         int
+	theirsStart, theirsEnd     int
+	oursChanged, theirsChanged bool
+}
+
+// mergeRegions lines up the changes from base to ours and from base to theirs. Changes that
+// overlap or touch end up in the same region.
+func mergeRegions(base, ours, theirs []string) []mergeRegion {
+	oursHunks, theirsHunks := lineHunks(base, ours), lineHunks(base, theirs)
+	var regions []mergeRegion
+	oursOffset, theirsOffset := 0, 0
+
+	i, j := 0, 0
+	for i < len(oursHunks) || j < len(theirsHunks) {
+		region := mergeRegion{}
+		oursDelta, theirsDelta := 0, 0
+		first := true
+
+		for {
+			takeOurs := i < len(oursHunks) && (j == len(theirsHunks) || oursHunks[i].start <= theirsHunks[j].start)
+			var hunk lineHunk
+			switch {
+			case takeOurs:
+				hunk = oursHunks[i]
+			case j < len(theirsHunks):
+				hunk = theirsHunks[j]
+			default:
+				hunk = lineHunk{start: -1}
+			}
+			if hunk.start < 0 || (!first && hunk.start > region.baseEnd) {
+				break
+			}
+
+			if first {
+				region.baseStart, region.baseEnd = hunk.start, hunk.end
+				first = false
+			} else if hunk.end > region.baseEnd {
+				region.baseEnd = hunk.end
+			}
+			delta := len(hunk.lines) - (hunk.end - hunk.start)
+			if takeOurs {
+				region.oursChanged = true
+				oursDelta += delta
+				i++
+			} else {
+				region.theirsChanged = true
+				theirsDelta += delta
+				j++
+			}
+		}
+
+		region.oursStart = region.baseStart + oursOffset
+		region.oursEnd = region.baseEnd + oursOffset + oursDelta
+		region.theirsStart = region.baseStart + theirsOffset
+		region.theirsEnd = region.baseEnd + theirsOffset + theirsDelta
+		oursOffset += oursDelta
+		theirsOffset += theirsDelta
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// mergeLines applies the changes from base to theirs on top of ours. Where both sides changed
+// the same lines differently ours is kept, and the place is counted in conflicts.
+func mergeLines(base, ours, theirs []string) (merged []string, conflicts int) {
+	pos := 0
+	for _, region := range mergeRegions(base, ours, theirs) {
+		merged = append(merged, base[pos:region.baseStart]...)
+		oursLines := ours[region.oursStart:region.oursEnd]
+		theirsLines := theirs[region.theirsStart:region.theirsEnd]
+		switch {
+		case !region.oursChanged:
+			merged = append(merged, theirsLines...)
+		case region.theirsChanged && !equalLines(oursLines, theirsLines):
+			conflicts++
+			merged = append(merged, oursLines...)
+		default:
+			merged = append(merged, oursLines...)
+		}
+		pos = region.baseEnd
+	}
+	return append(merged, base[pos:]...), conflicts
+}
+
+// equalLines reports whether a and b hold the same lines
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// trimMarkdownFence removes a code fence the agent sometimes wraps around its whole output
+func trimMarkdownFence(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if len(trimmed) < 6 || !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") {
+		return trimmed
+	}
+	newline := strings.Index(trimmed, "\n")
+	if newline < 0 || newline > len(trimmed)-3 {
+		return trimmed
+	}
+	return strings.TrimSpace(trimmed[newline+1 : len(trimmed)-3])
+}
+
+// buildNotesPatch turns the agent output into hunks against the current notes. base is what
+// the agent was given; lines the user changed since then are left out of the patch unless the
+// agent changed them too, in which case the hunk is marked as a conflict.
+func buildNotesPatch(id string, notesID uint, base, current, output string) *NotesPatch {
+	proposed := trimMarkdownFence(output)
+	if strings.HasSuffix(current, "\n") {
+		proposed += "\n"
+	}
+
+	baseLines, currentLines, proposedLines := splitNotesLines(base), splitNotesLines(current), splitNotesLines(proposed)
+	patch := &NotesPatch{ID: id, NotesID: notesID, Hunks: []NotesHunk{}, CreatedAt: time.Now(), text: current}
+	for _, region := range mergeRegions(baseLines, currentLines, proposedLines) {
+		if !region.theirsChanged {
+			continue
+		}
+		currentPart := currentLines[region.oursStart:region.oursEnd]
+		proposedPart := proposedLines[region.theirsStart:region.theirsEnd]
+		if equalLines(currentPart, proposedPart) {
+			continue
+		}
+
+		hunk := NotesHunk{
+			Index:    len(patch.Hunks),
+			Start:    region.oursStart,
+			End:      region.oursEnd,
+			Current:  append([]string{}, currentPart...),
+			Proposed: append([]string{}, proposedPart...),
+			Conflict: region.oursChanged,
+		}
+		if hunk.Conflict {
+			patch.Conflicts++
+		}
+		patch.Hunks = append(patch.Hunks, hunk)
+	}
+	return patch
+}
+
+// applyNotesHunks replaces the lines of each hunk in text
+func applyNotesHunks(text string, hunks []NotesHunk) string {
+	sorted := append([]NotesHunk{}, hunks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start > sorted[j].Start })
+
+	lines := splitNotesLines(text)
+	for _, hunk := range sorted {
+		replaced := append(append(append([]string{}, lines[:hunk.Start]...), hunk.Proposed...), lines[hunk.End:]...)
+		lines = replaced
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ProposeNotesPatch compares the agent output with the current meeting notes and keeps the
+// resulting patch for review. A patch without hunks is returned but not kept.
+func ProposeNotesPatch(id string, notesID uint, base, output string) (*NotesPatch, error) {
+	notes, err := GetMeetingNotesByID(notesID)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := buildNotesPatch(id, notesID, base, notes.Text, output)
+	if len(patch.Hunks) > 0 {
+		pendingNotesPatches.put(patch)
+	}
+	log.Printf("Markdown agent proposed %d changes to meeting notes %d (%d conflicts)", len(patch.Hunks), notesID, patch.Conflicts)
+	return patch, nil
+}
+
+// GetPendingNotesPatch returns the patch waiting for review for meeting notes, or nil
+func GetPendingNotesPatch(notesID uint) *NotesPatch {
+	return pendingNotesPatches.get(notesID)
+}
+
+// AcceptNotesPatch applies the chosen hunks of a pending patch and discards the rest. Edits
+// the user made since the patch was proposed are kept; a hunk touching the same lines is
+// skipped. The patch stays pending when the hunk indexes are invalid.
+func AcceptNotesPatch(patchID string, hunkIndexes []int) (*MeetingNotes, error) {
+	patch := pendingNotesPatches.find(patchID)
+	if patch == nil {
+		return nil, fmt.Errorf("no pending meeting notes patch %s", patchID)
+	}
+
+	var accepted []NotesHunk
+	seen := make(map[int]bool)
+	for _, index := range hunkIndexes {
+		if index < 0 || index >= len(patch.Hunks) {
+			return nil, fmt.Errorf("patch %s has no hunk %d", patchID, index)
+		}
+		if !seen[index] {
+			seen[index] = true
+			accepted = append(accepted, patch.Hunks[index])
+		}
+	}
+
+	// Another call may have accepted or rejected the patch in the meantime
+	if pendingNotesPatches.take(patchID) == nil {
+		return nil, fmt.Errorf("no pending meeting notes patch %s", patchID)
+	}
+
+	notes, err := GetMeetingNotesByID(patch.NotesID)
+	if err != nil {
+		return nil, err
+	}
+	if len(accepted) == 0 {
+		return notes, nil
+	}
+
+	text := applyNotesHunks(patch.text, accepted)
+	if notes.Text != patch.text {
+		merged, conflicts := mergeLines(splitNotesLines(patch.text), splitNotesLines(notes.Text), splitNotesLines(text))
+		if conflicts > 0 {
+			log.Printf("Skipped %d accepted changes to meeting notes %d edited since they were proposed", conflicts, patch.NotesID)
+		}
+		text = strings.Join(merged, "\n")
+	}
+	return saveMeetingNotes(patch.NotesID, text, NotesAuthorAgent, false)
+}
+
+// RejectNotesPatch discards a pending patch
+func RejectNotesPatch(patchID string) error {
+	if pendingNotesPatches.take(patchID) == nil {
+		return fmt.Errorf("no pending meeting notes patch %s", patchID)
+	}
+	return nil
+}
+
+// proposeNotesPatch reports the outcome of a meeting notes generation to the frontend
+func (a *App) proposeNotesPatch(requestID string, request *notesAgentRequest) {
+	defer request.finish()
+	if request.Live {
+		a.applyLiveNotes(requestID, request)
+		return
+	}
+
+	patch, err := ProposeNotesPatch(requestID, request.NotesID, request.Base, request.Output.String())
+	if err != nil {
+		log.Printf("Failed to build meeting notes patch for request %s: %v", requestID, err)
+		runtime.EventsEmit(a.ctx, "markdownAgentError", map[string]interface{}{
+			"error":     fmt.Sprintf("Failed to compare the generated notes: %v", err),
+			"requestId": requestID,
+		})
+		return
+	}
+
+	runtime.EventsEmit(a.ctx, "markdownAgentProposedPatch", map[string]interface{}{
+		"requestId": requestID,
+		"notesId":   request.NotesID,
+		"patch":     patch,
+	})
+}
+
+// GetPendingNotesPatch returns the markdown agent changes waiting for review for meeting notes, or nil
+func (a *App) GetPendingNotesPatch(notesID uint) *NotesPatch {
+	return GetPendingNotesPatch(notesID)
+}
+
+// AcceptNotesPatch applies the chosen hunks of a markdown agent patch and discards the rest
+func (a *App) AcceptNotesPatch(patchID string, hunkIndexes []int) (*MeetingNotes, error) {
+	return AcceptNotesPatch(patchID, hunkIndexes)
+}
+
+// RejectNotesPatch discards a markdown agent patch
+func (a *App) RejectNotesPatch(patchID string) error {
+	return RejectNotesPatch(patchID)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeLines(t *testing.T) {
+	cases := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          int
+	}{
+		{
+			name:   "changes on different lines",
+			base:   "a\nb\nc\nd",
+			ours:   "a\nB\nc\nd",
+			theirs: "a\nb\nc\nD\ne",
+			want:   "a\nB\nc\nD\ne",
+		},
+		{
+			name:   "only theirs changed",
+			base:   "a\nb",
+			ours:   "a\nb",
+			theirs: "x\na\nb",
+			want:   "x\na\nb",
+		},
+		{
+			name:   "same change on both sides",
+			base:   "a\nb\nc",
+			ours:   "a\nB\nc",
+			theirs: "a\nB\nc",
+			want:   "a\nB\nc",
+		},
+		{
+			name:      "conflict keeps ours",
+			base:      "a\nb\nc",
+			ours:      "a\nmine\nc",
+			theirs:    "a\ntheirs\nc\nd",
+			want:      "a\nmine\nc\nd",
+			conflicts: 1,
+		},
+		{
+			name:   "deletion next to an insertion elsewhere",
+			base:   "a\nb\nc\nd\ne",
+			ours:   "a\nc\nd\ne",
+			theirs: "a\nb\nc\nd\nx\ne",
+			want:   "a\nc\nd\nx\ne",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			merged, conflicts := mergeLines(splitNotesLines(c.base), splitNotesLines(c.ours), splitNotesLines(c.theirs))
+			if got := strings.Join(merged, "\n"); got != c.want || conflicts != c.conflicts {
+				t.Errorf("merged =\n%s\n(%d conflicts), want\n%s\n(%d conflicts)", got, conflicts, c.want, c.conflicts)
+			}
+		})
+	}
+}
+
+func TestTrimMarkdownFence(t *testing.T) {
+	cases := map[string]string{
+		"```markdown\n# Notes\n- a\n```": "# Notes\n- a",
+		"  # Notes\n":                    "# Notes",
+		"```\n```":                       "",
+		"# Notes\n```go\nx\n```":         "# Notes\n```go\nx\n```",
+	}
+	for input, want := range cases {
+		if got := trimMarkdownFence(input); got != want {
+			t.Errorf("trimMarkdownFence(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestBuildNotesPatch(t *testing.T) {
+	base := "# Notes\n- budget\n- hiring\n- travel\n"
+	// The user edited the hiring line while the agent was generating
+	current := "# Notes\n- budget\n- hiring: 2 people\n- travel\n"
+	output := "```markdown\n# Notes\n- budget: approved\n- hiring: 1 person\n- travel\n- next: Friday\n```"
+
+	patch := buildNotesPatch("req-1", 7, base, current, output)
+	if patch.ID != "req-1" || patch.NotesID != 7 || patch.text != current {
+		t.Errorf("patch = %+v", patch)
+	}
+	if len(patch.Hunks) != 2 || patch.Conflicts != 1 {
+		t.Fatalf("hunks = %+v, %d conflicts, want 2 hunks and 1 conflict", patch.Hunks, patch.Conflicts)
+	}
+
+	changed := patch.Hunks[0]
+	if changed.Index != 0 || changed.Start != 1 || changed.End != 3 || !changed.Conflict ||
+		!equalLines(changed.Current, []string{"- budget", "- hiring: 2 people"}) ||
+		!equalLines(changed.Proposed, []string{"- budget: approved", "- hiring: 1 person"}) {
+		t.Errorf("first hunk = %+v", changed)
+	}
+	added := patch.Hunks[1]
+	if added.Index != 1 || added.Start != 4 || added.End != 4 || added.Conflict ||
+		len(added.Current) != 0 || !equalLines(added.Proposed, []string{"- next: Friday"}) {
+		t.Errorf("second hunk = %+v", added)
+	}
+
+	if got, want := applyNotesHunks(current, patch.Hunks[1:]), current[:len(current)-1]+"\n- next: Friday\n"; got != want {
+		t.Errorf("applying the second hunk gives %q, want %q", got, want)
+	}
+	if got, want := applyNotesHunks(current, patch.Hunks), "# Notes\n- budget: approved\n- hiring: 1 person\n- travel\n- next: Friday\n"; got != want {
+		t.Errorf("applying all hunks gives %q, want %q", got, want)
+	}
+}
+
+func TestBuildNotesPatchUnchanged(t *testing.T) {
+	notes := "# Notes\n- a\n"
+	if patch := buildNotesPatch("req-1", 1, notes, notes, "# Notes\n- a"); len(patch.Hunks) != 0 {
+		t.Errorf("hunks = %+v, want none for the same notes", patch.Hunks)
+	}
+}
+
+func TestAcceptNotesPatch(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Planning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := CreateMeetingNotes(workspace.ID, "# Notes\n- a\n- b\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := ProposeNotesPatch("req-1", notes.ID, notes.Text, "# Notes\n- A\n- b\n- c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pendingNotesPatches.take(patch.ID) })
+	if len(patch.Hunks) != 2 {
+		t.Fatalf("hunks = %+v, want 2", patch.Hunks)
+	}
+
+	if _, err := AcceptNotesPatch(patch.ID, []int{0, 5}); err == nil {
+		t.Fatal("expected an error for a hunk the patch does not have")
+	}
+	if GetPendingNotesPatch(notes.ID) != patch {
+		t.Fatal("the patch is no longer pending after an invalid accept")
+	}
+
+	// The user edits a line the patch does not touch before accepting
+	if _, err := UpdateMeetingNotes(notes.ID, "# Meeting\n- a\n- b\n"); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := AcceptNotesPatch(patch.ID, []int{1, 1})
+	if err != nil {
+		t.Fatalf("AcceptNotesPatch failed: %v", err)
+	}
+	if want := "# Meeting\n- a\n- b\n- c\n"; saved.Text != want {
+		t.Errorf("notes = %q, want %q", saved.Text, want)
+	}
+	if GetPendingNotesPatch(notes.ID) != nil {
+		t.Error("the patch is still pending after it was accepted")
+	}
+	if _, err := AcceptNotesPatch(patch.ID, nil); err == nil {
+		t.Error("expected an error accepting the patch twice")
+	}
+}
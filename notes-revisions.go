@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Authors of a meeting notes revision
+const (
+	NotesAuthorUser  = "user"
+	NotesAuthorAgent = "agent"
+)
+
+// Consecutive user saves within this window are folded into one revision, so typing
+// doesn't record a revision per keystroke
+const notesRevisionMergeWindow = 2 * time.Minute
+
+// MeetingNotesRevision is a saved version of meeting notes
+type MeetingNotesRevision struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	NotesID     uint      `gorm:"not null;index" json:"notesId"`
+	WorkspaceID uint      `gorm:"not null;index" json:"workspaceId"`
+	SessionID   *uint     `gorm:"index" json:"sessionId"` // Session open when the notes were saved, if any
+	Author      string    `gorm:"not null" json:"author"` // "user" or "agent"
+	Text        string    `gorm:"type:text" json:"text"`  // Markdown format
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// Foreign key relationship
+	Notes MeetingNotes `gorm:"foreignKey:NotesID" json:"-"`
+}
+
+// recordNotesRevision records the current text of notes as a revision by author. Nothing is
+// recorded when the text didn't change since the latest revision. With merge set, a user edit
+// shortly after another one in the same session updates that revision instead.
+func recordNotesRevision(tx *gorm.DB, notes *MeetingNotes, author string, merge bool) error {
+	sessionID := openSessionID(notes.WorkspaceID)
+
+	var latest MeetingNotesRevision
+	err := tx.Where("notes_id = ?", notes.ID).Order("id DESC").Limit(1).Find(&latest).Error
+	if err != nil {
+		return err
+	}
+	if latest.ID != 0 {
+		if latest.Text == notes.Text {
+			return nil
+		}
+		sameSession := (latest.SessionID == nil && sessionID == nil) ||
+			(latest.SessionID != nil && sessionID != nil && *latest.SessionID == *sessionID)
+		if merge && author == NotesAuthorUser && latest.Author == NotesAuthorUser && sameSession &&
+			time.Since(latest.UpdatedAt) < notesRevisionMergeWindow {
+			return tx.Model(&latest).Update("text", notes.Text).Error
+		}
+	}
+
+	return tx.Create(&MeetingNotesRevision{
+		NotesID:     notes.ID,
+		WorkspaceID: notes.WorkspaceID,
+		SessionID:   sessionID,
+		Author:      author,
+		Text:        notes.Text,
+	}).Error
+}
+
+// saveMeetingNotes replaces the text of meeting notes and records the revision
+func saveMeetingNotes(id uint, text, author string, merge bool) (*MeetingNotes, error) {
+	var notes MeetingNotes
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&notes, id).Error; err != nil {
+			return err
+		}
+		notes.Text = text
+		if err := tx.Save(&notes).Error; err != nil {
+			return err
+		}
+		return recordNotesRevision(tx, &notes, author, merge)
+	})
+	if err != nil {
+		log.Printf("Failed to update meeting notes %d: %v", id, err)
+		return nil, err
+	}
+
+	log.Printf("Updated meeting notes ID %d (%s)", id, author)
+	return &notes, nil
+}
+
+// ListNotesRevisions returns the revisions of meeting notes, newest first
+func ListNotesRevisions(notesID uint) ([]MeetingNotesRevision, error) {
+	var revisions []MeetingNotesRevision
+	result := DB.Where("notes_id = ?", notesID).Order("id DESC").Find(&revisions)
+	if result.Error != nil {
+		log.Printf("Failed to list revisions of meeting notes %d: %v", notesID, result.Error)
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+// GetNotesRevisionByID retrieves a meeting notes revision by ID
+func GetNotesRevisionByID(id uint) (*MeetingNotesRevision, error) {
+	var revision MeetingNotesRevision
+	result := DB.First(&revision, id)
+	if result.Error != nil {
+		log.Printf("Failed to get meeting notes revision %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &revision, nil
+}
+
+// Operations of a DiffLine
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine is a line of a line-based diff. Line numbers are 1-based, 0 when the line
+// doesn't exist on that side.
+type DiffLine struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine"`
+	NewLine int    `json:"newLine"`
+}
+
+// NotesDiff is the difference between two meeting notes revisions
+type NotesDiff struct {
+	FromID  uint       `json:"fromId"`
+	ToID    uint       `json:"toId"`
+	Added   int        `json:"added"`
+	Removed int        `json:"removed"`
+	Lines   []DiffLine `json:"lines"`
+}
+
+// diffLines computes a line-based diff turning a into b, using the longest common subsequence
+// of the lines between their common prefix and suffix
+func diffLines(a, b []string) []DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: midA[i], OldLine: prefix + i + 1, NewLine: prefix + j + 1})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: midA[i], OldLine: prefix + i + 1})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: midB[j], NewLine: prefix + j + 1})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		oldLine, newLine := len(a)-suffix+k, len(b)-suffix+k
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: a[oldLine], OldLine: oldLine + 1, NewLine: newLine + 1})
+	}
+	return lines
+}
+
+// splitNotesLines splits Markdown into lines, normalising line endings
+func splitNotesLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// DiffNotesRevisions returns the line-based diff from revision fromID to revision toID
+func DiffNotesRevisions(fromID, toID uint) (*NotesDiff, error) {
+	from, err := GetNotesRevisionByID(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := GetNotesRevisionByID(toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.NotesID != to.NotesID {
+		return nil, fmt.Errorf("revisions %d and %d belong to different meeting notes", fromID, toID)
+	}
+
+	diff := &NotesDiff{FromID: fromID, ToID: toID, Lines: diffLines(splitNotesLines(from.Text), splitNotesLines(to.Text))}
+	for _, line := range diff.Lines {
+		switch line.Op {
+		case DiffInsert:
+			diff.Added++
+		case DiffDelete:
+			diff.Removed++
+		}
+	}
+	return diff, nil
+}
+
+// RestoreNotesRevision puts the text of a revision back into its meeting notes. The restore
+// is itself recorded as a revision, so it can be undone.
+func RestoreNotesRevision(id uint) (*MeetingNotes, error) {
+	revision, err := GetNotesRevisionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Restoring meeting notes %d to revision %d", revision.NotesID, id)
+	return saveMeetingNotes(revision.NotesID, revision.Text, NotesAuthorUser, false)
+}
+
+// SaveAgentMeetingNotes saves meeting notes written by the markdown agent
+func (a *App) SaveAgentMeetingNotes(id uint, text string) (*MeetingNotes, error) {
+	return saveMeetingNotes(id, text, NotesAuthorAgent, false)
+}
+
+// ListNotesRevisions returns the revisions of meeting notes, newest first
+func (a *App) ListNotesRevisions(notesID uint) ([]MeetingNotesRevision, error) {
+	return ListNotesRevisions(notesID)
+}
+
+// DiffNotesRevisions returns the line-based diff between two revisions of the same meeting notes
+func (a *App) DiffNotesRevisions(fromID, toID uint) (*NotesDiff, error) {
+	return DiffNotesRevisions(fromID, toID)
+}
+
+// RestoreNotesRevision puts the text of a revision back into its meeting notes
+func (a *App) RestoreNotesRevision(id uint) (*MeetingNotes, error) {
+	return RestoreNotesRevision(id)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatDiff writes a diff as one "op old new text" entry per line, for comparing in tests
+func formatDiff(lines []DiffLine) string {
+	marks := map[string]string{DiffEqual: " ", DiffInsert: "+", DiffDelete: "-"}
+	entries := make([]string, len(lines))
+	for i, line := range lines {
+		entries[i] = fmt.Sprintf("%s%d,%d %s", marks[line.Op], line.OldLine, line.NewLine, line.Text)
+	}
+	return strings.Join(entries, "\n")
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{
+			name: "unchanged",
+			a:    "# Notes\n- one",
+			b:    "# Notes\n- one",
+			want: []string{" 1,1 # Notes", " 2,2 - one"},
+		},
+		{
+			name: "insert in the middle",
+			a:    "a\nc",
+			b:    "a\nb\nc",
+			want: []string{" 1,1 a", "+0,2 b", " 2,3 c"},
+		},
+		{
+			name: "delete",
+			a:    "a\nb\nc",
+			b:    "a\nc",
+			want: []string{" 1,1 a", "-2,0 b", " 3,2 c"},
+		},
+		{
+			name: "replace keeps the common lines",
+			a:    "# Notes\n- old\n- kept\n- gone",
+			b:    "# Notes\n- new\n- kept",
+			want: []string{" 1,1 # Notes", "-2,0 - old", "+0,2 - new", " 3,3 - kept", "-4,0 - gone"},
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\nb",
+			want: []string{"+0,1 a", "+0,2 b"},
+		},
+		{
+			name: "to empty",
+			a:    "a",
+			b:    "",
+			want: []string{"-1,0 a"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := formatDiff(diffLines(splitNotesLines(c.a), splitNotesLines(c.b)))
+			if want := strings.Join(c.want, "\n"); got != want {
+				t.Errorf("diff =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesRebuildsBothSides(t *testing.T) {
+	a := splitNotesLines("x\na\nb\nc\nx\ny\nz\nb\na")
+	b := splitNotesLines("a\nb\nx\nc\ny\nx\nz\nq")
+
+	var oldSide, newSide []string
+	for _, line := range diffLines(a, b) {
+		if line.Op != DiffInsert {
+			oldSide = append(oldSide, line.Text)
+		}
+		if line.Op != DiffDelete {
+			newSide = append(newSide, line.Text)
+		}
+	}
+	if !equalLines(oldSide, a) || !equalLines(newSide, b) {
+		t.Errorf("diff rebuilds %q and %q, want %q and %q", oldSide, newSide, a, b)
+	}
+}
+
+func TestSplitNotesLines(t *testing.T) {
+	if got := splitNotesLines("a\r\nb\n"); !equalLines(got, []string{"a", "b", ""}) {
+		t.Errorf("splitNotesLines = %q", got)
+	}
+	if got := splitNotesLines(""); len(got) != 0 {
+		t.Errorf("splitNotesLines(\"\") = %q, want no lines", got)
+	}
+}
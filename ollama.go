@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOllamaURL = "http://localhost:11434"
+	defaultChatModel = "granite3.3:8b"
+)
+
+// OllamaClient talks to the Ollama HTTP API directly, without the Python bridge
+type OllamaClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewOllamaClient creates a client for the Ollama server at baseURL
+func NewOllamaClient(baseURL string) *OllamaClient {
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	return &OllamaClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{},
+	}
+}
+
+// ollamaChatRequest represents the request payload for /api/chat
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+// OllamaChatChunk represents one line of the /api/chat NDJSON stream
+type OllamaChatChunk struct {
+	Model           string      `json:"model"`
+	Message         ChatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	DoneReason      string      `json:"done_reason"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error"`
+}
+
+// StreamChat sends messages to /api/chat and calls onToken for every streamed token.
+// It returns the final chunk, which carries the token counts.
+func (c *OllamaClient) StreamChat(ctx context.Context, model string, messages []ChatMessage, onToken func(token string)) (*OllamaChatChunk, error) {
+	payload, err := json.Marshal(ollamaChatRequest{
+		Model:    model,
+		Messages: messages,
+		Stream:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Ollama chat request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama not accessible at %s: %v", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Ollama API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk OllamaChatChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			log.Printf("Failed to parse Ollama stream line: %v", err)
+			continue
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("Ollama error: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			onToken(chunk.Message.Content)
+		}
+		if chunk.Done {
+			return &chunk, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Ollama stream: %v", err)
+	}
+	return nil, fmt.Errorf("Ollama stream ended unexpectedly")
+}
+
+// ListModels returns the names of the models installed in Ollama (/api/tags)
+func (c *OllamaClient) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama not accessible at %s: %v", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse Ollama model list: %v", err)
+	}
+
+	models := make([]string, 0, len(result.Models))
+	for _, model := range result.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+// OllamaProvider is a ChatProvider backed by Ollama's /api/chat
+type OllamaProvider struct{}
+
+func (p *OllamaProvider) Name() string {
+	return ChatBackendOllama
+}
+
+func (p *OllamaProvider) client() *OllamaClient {
+	return NewOllamaClient(GetSetting(SettingOllamaURL))
+}
+
+func (p *OllamaProvider) StreamChat(ctx context.Context, req ChatStreamRequest, onToken func(token string)) (*ChatCompletionStats, error) {
+	model := req.Model
+	if model == "" {
+		model = GetSetting(SettingOllamaModel)
+	}
+
+	final, err := p.client().StreamChat(ctx, model, req.Messages, onToken)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return &ChatCompletionStats{
+		Model:            model,
+		PromptTokens:     final.PromptEvalCount,
+		CompletionTokens: final.EvalCount,
+	}, nil
+}
+
+func (p *OllamaProvider) Complete(ctx context.Context, messages []ChatMessage) (string, *ChatCompletionStats, error) {
+	return completeByStreaming(ctx, p, messages)
+}
+
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	return p.client().ListModels(ctx)
+}
+
+func (p *OllamaProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:    true,
+		Cancellation: true,
+		ModelListing: true,
+		TokenCounts:  true,
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ollamaStub serves /api/chat with handler, standing in for an Ollama server
+func ollamaStub(t *testing.T, handler http.HandlerFunc) *OllamaClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewOllamaClient(server.URL)
+}
+
+func TestOllamaStreamChat(t *testing.T) {
+	client := ollamaStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var request ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if request.Model != "test-model" || !request.Stream || len(request.Messages) != 2 {
+			t.Errorf("unexpected request %+v", request)
+		}
+
+		for _, token := range []string{"Hel", "lo", ""} {
+			fmt.Fprintf(w, `{"model":"test-model","message":{"role":"assistant","content":%q},"done":false}`+"\n", token)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprintln(w, `{"model":"test-model","message":{"role":"assistant","content":"!"},"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":3}`)
+	})
+
+	var tokens []string
+	final, err := client.StreamChat(context.Background(), "test-model", []ChatMessage{
+		{Role: "system", Content: "Be brief."},
+		{Role: "user", Content: "Hi"},
+	}, func(token string) {
+		tokens = append(tokens, token)
+	})
+	if err != nil {
+		t.Fatalf("StreamChat failed: %v", err)
+	}
+	if got := strings.Join(tokens, "|"); got != "Hel|lo|!" {
+		t.Errorf("tokens = %q, want %q", got, "Hel|lo|!")
+	}
+	if final.PromptEvalCount != 12 || final.EvalCount != 3 || final.DoneReason != "stop" {
+		t.Errorf("final chunk = %+v", final)
+	}
+}
+
+func TestOllamaStreamChatErrorStatus(t *testing.T) {
+	client := ollamaStub(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model \"missing\" not found"}`, http.StatusNotFound)
+	})
+
+	_, err := client.StreamChat(context.Background(), "missing", nil, func(string) {
+		t.Error("no token expected")
+	})
+	if err == nil || !strings.Contains(err.Error(), "status 404") || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("err = %v, want the status and the server's message", err)
+	}
+}
+
+func TestOllamaStreamChatErrorLine(t *testing.T) {
+	client := ollamaStub(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"a"},"done":false}`)
+		fmt.Fprintln(w, `{"error":"out of memory"}`)
+	})
+
+	_, err := client.StreamChat(context.Background(), "m", nil, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "out of memory") {
+		t.Errorf("err = %v, want the streamed error", err)
+	}
+}
+
+func TestOllamaStreamChatUnexpectedEnd(t *testing.T) {
+	client := ollamaStub(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"a"},"done":false}`)
+	})
+
+	if _, err := client.StreamChat(context.Background(), "m", nil, func(string) {}); err == nil {
+		t.Error("expected an error for a stream without a done chunk")
+	}
+}
+
+func TestOllamaStreamChatCancel(t *testing.T) {
+	client := ollamaStub(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":"first"},"done":false}`)
+		w.(http.Flusher).Flush()
+		// Keep generating until the client goes away
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var tokens []string
+	_, err := client.StreamChat(ctx, "m", nil, func(token string) {
+		tokens = append(tokens, token)
+		cancel()
+	})
+	if !errors.Is(contextError(ctx, err), context.Canceled) {
+		t.Errorf("err = %v, want a cancelled generation", err)
+	}
+	if len(tokens) != 1 {
+		t.Errorf("tokens = %q, want only the one before cancelling", tokens)
+	}
+}
+
+func TestOllamaListModels(t *testing.T) {
+	client := ollamaStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"models":[{"name":"llama3:8b"},{"name":"granite3.3:8b"}]}`)
+	})
+
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if strings.Join(models, ",") != "llama3:8b,granite3.3:8b" {
+		t.Errorf("models = %v", models)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// OpenAICompatibleProvider is a ChatProvider for any server implementing the OpenAI
+// chat completions API (llama.cpp server, LM Studio, vLLM, ...)
+type OpenAICompatibleProvider struct{}
+
+// openAIChatRequest represents the request payload for /chat/completions
+type openAIChatRequest struct {
+	Model         string              `json:"model"`
+	Messages      []ChatMessage       `json:"messages"`
+	Stream        bool                `json:"stream"`
+	StreamOptions *openAIStreamOption `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOption struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIChatChunk represents one server-sent event of a streamed completion
+type openAIChatChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *OpenAICompatibleProvider) Name() string {
+	return ChatBackendOpenAI
+}
+
+func (p *OpenAICompatibleProvider) baseURL() string {
+	return strings.TrimRight(GetSetting(SettingOpenAIURL), "/")
+}
+
+func (p *OpenAICompatibleProvider) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL()+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey := GetSetting(SettingOpenAIAPIKey); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+func (p *OpenAICompatibleProvider) StreamChat(ctx context.Context, req ChatStreamRequest, onToken func(token string)) (*ChatCompletionStats, error) {
+	model := req.Model
+	if model == "" {
+		model = GetSetting(SettingOpenAIModel)
+	}
+
+	payload, err := json.Marshal(openAIChatRequest{
+		Model:         model,
+		Messages:      req.Messages,
+		Stream:        true,
+		StreamOptions: &openAIStreamOption{IncludeUsage: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat completion request: %v", err)
+	}
+
+	stats, err := p.streamCompletion(ctx, model, payload, onToken)
+	return stats, contextError(ctx, err)
+}
+
+// streamCompletion posts payload to /chat/completions and reads the server-sent events
+func (p *OpenAICompatibleProvider) streamCompletion(ctx context.Context, model string, payload []byte, onToken func(token string)) (*ChatCompletionStats, error) {
+	httpReq, err := p.newRequest(ctx, http.MethodPost, "/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI-compatible server not accessible at %s: %v", p.baseURL(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("chat completions API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	stats := &ChatCompletionStats{Model: model}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return stats, nil
+		}
+
+		var chunk openAIChatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			log.Printf("Failed to parse chat completion chunk: %v", err)
+			continue
+		}
+		if chunk.Error != nil {
+			return nil, fmt.Errorf("chat completions error: %s", chunk.Error.Message)
+		}
+		if chunk.Model != "" {
+			stats.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			stats.PromptTokens = chunk.Usage.PromptTokens
+			stats.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				onToken(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat completion stream: %v", err)
+	}
+	// Some servers close the stream without sending [DONE]
+	return stats, nil
+}
+
+func (p *OpenAICompatibleProvider) Complete(ctx context.Context, messages []ChatMessage) (string, *ChatCompletionStats, error) {
+	return completeByStreaming(ctx, p, messages)
+}
+
+func (p *OpenAICompatibleProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := p.newRequest(ctx, http.MethodGet, "/models", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI-compatible server not accessible at %s: %v", p.baseURL(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("models API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse model list: %v", err)
+	}
+
+	models := make([]string, 0, len(result.Data))
+	for _, model := range result.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+func (p *OpenAICompatibleProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Streaming:    true,
+		Cancellation: true,
+		ModelListing: true,
+		TokenCounts:  true,
+	}
+}
@@ -0,0 +1,682 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+// Kinds of prompt template, one per place the app builds a prompt
+const (
+	PromptKindChat         = "chat"    // system prompt of assistant chat messages
+	PromptKindMeetingNotes = "notes"   // message asking the markdown agent to update the meeting notes
+	PromptKindSummary      = "summary" // instructions for writing the summary of a session's transcript
+)
+
+var promptKinds = map[string]bool{
+	PromptKindChat:         true,
+	PromptKindMeetingNotes: true,
+	PromptKindSummary:      true,
+}
+
+// promptTemplateFileVersion identifies the format written by ExportPromptTemplates
+const promptTemplateFileVersion = 1
+
+// promptTranscriptShare is the share of the context budget the transcript window may use
+const promptTranscriptShare = 4
+
+// PromptTemplate is a Go text/template rendered into a prompt, see PromptVariables for
+// what it can refer to
+type PromptTemplate struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"uniqueIndex;not null" json:"name"`
+	Kind        string    `gorm:"not null;index" json:"kind"` // one of the PromptKind constants
+	Description string    `json:"description"`
+	Body        string    `gorm:"type:text;not null" json:"body"`
+	BuiltIn     bool      `gorm:"not null" json:"builtIn"`           // shipped with the app, can't be deleted
+	Version     int       `gorm:"not null;default:1" json:"version"` // incremented on every edit
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WorkspacePromptTemplate selects the template a workspace uses for a kind of prompt.
+// Workspaces without one use the built-in template of that kind.
+type WorkspacePromptTemplate struct {
+	WorkspaceID uint   `gorm:"primaryKey;autoIncrement:false" json:"workspaceId"`
+	Kind        string `gorm:"primaryKey" json:"kind"`
+	TemplateID  uint   `gorm:"not null;index" json:"templateId"`
+
+	// Foreign key relationships
+	Workspace Workspace      `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+	Template  PromptTemplate `gorm:"foreignKey:TemplateID" json:"template,omitempty"`
+}
+
+// builtInPromptTemplates are created by the migration that adds prompt templates. They
+// reproduce the prompts the app used before templates existed. The summary ones lived in
+// their own table at first, version 10 moves them over.
+var builtInPromptTemplates = []PromptTemplate{
+	{
+		Name:        "Assistant",
+		Kind:        PromptKindChat,
+		Description: "General purpose assistant, the transcript, notes and knowledge base are added to each message",
+		Body:        "You are a helpful AI assistant.",
+	},
+	{
+		Name:        "Meeting notes",
+		Kind:        PromptKindMeetingNotes,
+		Description: "Sends the new transcript lines and the current notes to the markdown agent",
+		Body:        "[transcriptions]\n\n{{.Transcript}}\n\n[current markdown notes]\n\n{{.Notes}}",
+	},
+	{
+		Name:        "Stand-up",
+		Kind:        PromptKindSummary,
+		Description: "Daily stand-up: progress, plans and blockers per person",
+		Body: `Write a stand-up summary in Markdown with one "### <name>" section per participant, each with the bullet lists "Done", "Next" and "Blockers" (omit empty lists).
+End with a "## Follow-ups" section listing anything that needs a conversation after the stand-up.`,
+	},
+	{
+		Name:        "Sales call",
+		Kind:        PromptKindSummary,
+		Description: "Customer needs, objections, pricing and next steps",
+		Body: `Write a sales call summary in Markdown with the sections "## Customer", "## Needs and pain points", "## Objections", "## Pricing and terms discussed", "## Competitors mentioned" and "## Next steps" (with owners and dates).
+Quote the customer where their wording matters.`,
+	},
+	{
+		Name:        "Interview",
+		Kind:        PromptKindSummary,
+		Description: "Candidate interview: topics covered, strengths and concerns",
+		Body: `Write an interview summary in Markdown with the sections "## Candidate", "## Topics covered", "## Strengths", "## Concerns" and "## Open questions for the next round".
+Stick to what was said; don't make a hiring recommendation.`,
+	},
+	{
+		Name:        "1:1",
+		Kind:        PromptKindSummary,
+		Description: "One-on-one: updates, feedback, career topics and agreements",
+		Body: `Write a 1:1 summary in Markdown with the sections "## Updates", "## Feedback", "## Growth and career", "## Agreements" and "## Action items" (with owners).
+Keep a neutral tone and leave out small talk.`,
+	},
+}
+
+// builtInPromptBody returns the shipped body of the built-in template of a kind
+func builtInPromptBody(kind string) string {
+	for _, template := range builtInPromptTemplates {
+		if template.Kind == kind {
+			return template.Body
+		}
+	}
+	return ""
+}
+
+// PromptVariable documents a value prompt templates can refer to
+type PromptVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PromptVariables lists the values available to prompt templates, in the order the editor shows them
+var PromptVariables = []PromptVariable{
+	{"{{.Workspace}}", "Title of the workspace"},
+	{"{{.WorkspaceDescription}}", "Description of the workspace"},
+	{"{{.Transcript}}", "Transcript of the chat context, older parts summarised, or the new lines for meeting notes prompts. Empty for summary prompts, the transcript is sent after them"},
+	{"{{.Notes}}", "Current meeting notes of the workspace"},
+	{"{{.Knowledge}}", "Knowledge base excerpts relevant to the message or transcript"},
+	{"{{.Message}}", "Message the user sent, empty for meeting notes prompts"},
+	{"{{.Date}}", "Today's date"},
+}
+
+// promptData is what prompt templates are rendered with. Values are looked up the first time
+// a template uses them, so templates only pay for what they refer to.
+type promptData struct {
+	workspaceID uint
+	message     string
+	transcript  []string     // lines to use instead of the recent transcript window
+	notes       *string      // notes to use instead of the workspace's meeting notes
+	context     *ChatContext // chat context to take the transcript, notes and knowledge from
+	sample      bool         // render placeholders instead of reading the database
+
+	workspace *Workspace
+	knowledge *string
+	used      map[string]bool // variables the template referred to
+}
+
+// use records that the template referred to a variable
+func (d *promptData) use(name string) {
+	if d.used == nil {
+		d.used = make(map[string]bool)
+	}
+	d.used[name] = true
+}
+
+func (d *promptData) loadWorkspace() *Workspace {
+	if d.workspace == nil {
+		d.workspace = &Workspace{}
+		if workspace, err := GetWorkspaceByID(d.workspaceID); err == nil {
+			d.workspace = workspace
+		}
+	}
+	return d.workspace
+}
+
+func (d *promptData) Workspace() string {
+	if d.sample {
+		return "<workspace>"
+	}
+	return d.loadWorkspace().Title
+}
+
+func (d *promptData) WorkspaceDescription() string {
+	if d.sample {
+		return "<workspace description>"
+	}
+	return d.loadWorkspace().Description
+}
+
+// Transcript returns the transcript of the chat context, or else the newest transcript lines
+// that fit in a share of the context budget
+func (d *promptData) Transcript() string {
+	if d.sample {
+		return "<transcript>"
+	}
+	d.use("Transcript")
+	if d.context != nil {
+		return strings.Join(d.context.Transcription, "\n")
+	}
+	if d.transcript == nil {
+		records, _ := GetTranscriptionMessagesByWorkspace(d.workspaceID)
+		budget := contextTokenBudget() / promptTranscriptShare
+		start := len(records)
+		for start > 0 {
+			cost := estimateTokens(formatTranscriptLine(records[start-1]))
+			if cost > budget {
+				break
+			}
+			budget -= cost
+			start--
+		}
+		d.transcript = make([]string, 0, len(records)-start)
+		for _, record := range records[start:] {
+			d.transcript = append(d.transcript, formatTranscriptLine(record))
+		}
+	}
+	return strings.Join(d.transcript, "\n")
+}
+
+func (d *promptData) Notes() string {
+	if d.sample {
+		return "<meeting notes>"
+	}
+	d.use("Notes")
+	if d.context != nil {
+		return d.context.MeetingNotes
+	}
+	if d.notes == nil {
+		text := ""
+		if notes, err := GetMeetingNotesByWorkspace(d.workspaceID); err == nil && len(notes) > 0 {
+			text = notes[0].Text
+		}
+		d.notes = &text
+	}
+	return *d.notes
+}
+
+// Knowledge returns the knowledge base chunks of the chat context, or the ones most relevant
+// to the message, or to the transcript when there is no message
+func (d *promptData) Knowledge() string {
+	if d.sample {
+		return "<knowledge base excerpts>"
+	}
+	d.use("Knowledge")
+	if d.knowledge == nil {
+		var chunks []RetrievedChunk
+		if d.context != nil {
+			chunks = d.context.Knowledge
+		} else {
+			query := d.message
+			if query == "" {
+				query = d.Transcript()
+			}
+			if strings.TrimSpace(query) != "" {
+				chunks = retrieveKnowledge(query, retrievalTopK())
+			}
+		}
+		text := formatKnowledgeExcerpts(chunks)
+		d.knowledge = &text
+	}
+	return *d.knowledge
+}
+
+func (d *promptData) Message() string {
+	if d.sample {
+		return "<message>"
+	}
+	return d.message
+}
+
+func (d *promptData) Date() string {
+	return time.Now().Format("Monday, 2 January 2006")
+}
+
+// parsePromptTemplate parses a template body, rejecting references to unknown variables
+func parsePromptTemplate(name, body string) (*template.Template, error) {
+	parsed, err := template.New(name).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsed.Execute(&strings.Builder{}, &promptData{sample: true}); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// renderPromptTemplate renders a template body with data
+func renderPromptTemplate(name, body string, data *promptData) (string, error) {
+	parsed, err := template.New(name).Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var prompt strings.Builder
+	if err := parsed.Execute(&prompt, data); err != nil {
+		return "", err
+	}
+	return prompt.String(), nil
+}
+
+// renderWorkspacePrompt renders the template a workspace uses for kind, falling back to the
+// shipped built-in template when it can't be loaded or rendered
+func renderWorkspacePrompt(workspaceID uint, kind string, data *promptData) string {
+	data.workspaceID = workspaceID
+	if template, err := GetWorkspacePromptTemplate(workspaceID, kind); err == nil {
+		prompt, err := renderPromptTemplate(template.Name, template.Body, data)
+		if err == nil {
+			return prompt
+		}
+		log.Printf("Failed to render prompt template %q for workspace %d: %v", template.Name, workspaceID, err)
+	}
+	data.used = nil
+	prompt, _ := renderPromptTemplate(kind, builtInPromptBody(kind), data)
+	return prompt
+}
+
+// renderChatSystemPrompt renders the chat template of a workspace from the context built for
+// the message, so the template shows the same transcript, notes and numbered knowledge
+// excerpts as the citations. Sections the template includes are left out of the user prompt.
+func renderChatSystemPrompt(workspaceID uint, message string, chatContext *ChatContext) string {
+	data := &promptData{message: message, context: chatContext}
+	prompt := renderWorkspacePrompt(workspaceID, PromptKindChat, data)
+	chatContext.transcriptInSystem = data.used["Transcript"]
+	chatContext.notesInSystem = data.used["Notes"]
+	chatContext.knowledgeInSystem = data.used["Knowledge"]
+	chatContext.Info.EstimatedTokens = estimateTokens(prompt) + estimateTokens(buildChatPrompt(*chatContext, message))
+	return prompt
+}
+
+// workspaceChatPromptBody returns the unrendered chat template of a workspace, to budget the
+// context before the template is rendered from it
+func workspaceChatPromptBody(workspaceID uint) string {
+	if template, err := GetWorkspacePromptTemplate(workspaceID, PromptKindChat); err == nil {
+		return template.Body
+	}
+	return builtInPromptBody(PromptKindChat)
+}
+
+// ListPromptTemplates retrieves the prompt templates of a kind, or of every kind when kind is empty
+func ListPromptTemplates(kind string) ([]PromptTemplate, error) {
+	var templates []PromptTemplate
+	query := DB.Order("kind ASC, built_in DESC, name ASC")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	result := query.Find(&templates)
+	if result.Error != nil {
+		log.Printf("Failed to get prompt templates: %v", result.Error)
+		return nil, result.Error
+	}
+	return templates, nil
+}
+
+// GetPromptTemplateByID retrieves a prompt template by ID
+func GetPromptTemplateByID(id uint) (*PromptTemplate, error) {
+	var template PromptTemplate
+	result := DB.First(&template, id)
+	if result.Error != nil {
+		log.Printf("Failed to get prompt template by ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &template, nil
+}
+
+// validatePromptTemplate checks the fields of a template before it is saved
+func validatePromptTemplate(name, kind, body string) error {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(body) == "" {
+		return fmt.Errorf("a prompt template needs a name and a body")
+	}
+	if !promptKinds[kind] {
+		return fmt.Errorf("unknown prompt template kind %q", kind)
+	}
+	if _, err := parsePromptTemplate(name, body); err != nil {
+		return fmt.Errorf("invalid prompt template: %v", err)
+	}
+	return nil
+}
+
+// CreatePromptTemplate creates a user-defined prompt template
+func CreatePromptTemplate(name, kind, description, body string) (*PromptTemplate, error) {
+	if err := validatePromptTemplate(name, kind, body); err != nil {
+		return nil, err
+	}
+
+	template := &PromptTemplate{
+		Name:        strings.TrimSpace(name),
+		Kind:        kind,
+		Description: description,
+		Body:        body,
+		Version:     1,
+	}
+	result := DB.Create(template)
+	if result.Error != nil {
+		log.Printf("Failed to create prompt template: %v", result.Error)
+		return nil, result.Error
+	}
+	return template, nil
+}
+
+// UpdatePromptTemplate updates a prompt template and increments its version. The kind of
+// a template can't change, workspaces may be using it.
+func UpdatePromptTemplate(id uint, name, description, body string) (*PromptTemplate, error) {
+	template, err := GetPromptTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePromptTemplate(name, template.Kind, body); err != nil {
+		return nil, err
+	}
+
+	result := DB.Model(template).Updates(map[string]interface{}{
+		"name":        strings.TrimSpace(name),
+		"description": description,
+		"body":        body,
+		"version":     gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		log.Printf("Failed to update prompt template %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return GetPromptTemplateByID(id)
+}
+
+// DeletePromptTemplate deletes a user-defined prompt template. Workspaces using it go back
+// to the built-in template, and meeting summaries written with it keep its name and version.
+func DeletePromptTemplate(id uint) error {
+	template, err := GetPromptTemplateByID(id)
+	if err != nil {
+		return err
+	}
+	if template.BuiltIn {
+		return fmt.Errorf("built-in prompt template %q can't be deleted", template.Name)
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&WorkspacePromptTemplate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&MeetingSummary{}).Where("template_id = ?", id).Update("template_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&PromptTemplate{}, id).Error
+	})
+	if err != nil {
+		log.Printf("Failed to delete prompt template %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// GetWorkspacePromptTemplate retrieves the template a workspace uses for a kind of prompt
+func GetWorkspacePromptTemplate(workspaceID uint, kind string) (*PromptTemplate, error) {
+	var template PromptTemplate
+	result := DB.Joins("JOIN workspace_prompt_templates ON workspace_prompt_templates.template_id = prompt_templates.id").
+		Where("workspace_prompt_templates.workspace_id = ? AND workspace_prompt_templates.kind = ?", workspaceID, kind).
+		Limit(1).Find(&template)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result = DB.Where("kind = ? AND built_in = ?", kind, true).Order("id ASC").First(&template)
+	}
+	if result.Error != nil {
+		log.Printf("Failed to get %s prompt template for workspace %d: %v", kind, workspaceID, result.Error)
+		return nil, result.Error
+	}
+	return &template, nil
+}
+
+// SetWorkspacePromptTemplate makes a workspace use a template for its kind of prompt.
+// A templateID of 0 puts the workspace back on the built-in template of kind.
+func SetWorkspacePromptTemplate(workspaceID uint, kind string, templateID uint) error {
+	if !promptKinds[kind] {
+		return fmt.Errorf("unknown prompt template kind %q", kind)
+	}
+	if templateID != 0 {
+		template, err := GetPromptTemplateByID(templateID)
+		if err != nil {
+			return err
+		}
+		if template.Kind != kind {
+			return fmt.Errorf("prompt template %q is a %s template, not %s", template.Name, template.Kind, kind)
+		}
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ? AND kind = ?", workspaceID, kind).Delete(&WorkspacePromptTemplate{}).Error; err != nil {
+			return err
+		}
+		if templateID == 0 {
+			return nil
+		}
+		return tx.Create(&WorkspacePromptTemplate{WorkspaceID: workspaceID, Kind: kind, TemplateID: templateID}).Error
+	})
+	if err != nil {
+		log.Printf("Failed to set %s prompt template for workspace %d: %v", kind, workspaceID, err)
+		return err
+	}
+	return nil
+}
+
+// PreviewPromptTemplate renders a template body with the current data of a workspace
+func PreviewPromptTemplate(workspaceID uint, body, message string) (string, error) {
+	return renderPromptTemplate("preview", body, &promptData{workspaceID: workspaceID, message: message})
+}
+
+// promptTemplateFile is the JSON format prompt templates are shared in
+type promptTemplateFile struct {
+	Version   int                    `json:"version"`
+	Templates []sharedPromptTemplate `json:"templates"`
+}
+
+type sharedPromptTemplate struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Body        string `json:"body"`
+}
+
+// ExportPromptTemplates writes prompt templates to a JSON file at path, every template when
+// ids is empty
+func ExportPromptTemplates(ids []uint, path string) error {
+	var templates []PromptTemplate
+	query := DB.Order("kind ASC, name ASC")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	if err := query.Find(&templates).Error; err != nil {
+		return err
+	}
+
+	file := promptTemplateFile{Version: promptTemplateFileVersion}
+	for _, template := range templates {
+		file.Templates = append(file.Templates, sharedPromptTemplate{
+			Name:        template.Name,
+			Kind:        template.Kind,
+			Description: template.Description,
+			Body:        template.Body,
+		})
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// importedTemplateName returns the name to import a template under, numbering it when the
+// name is taken, or an empty string when an identical template already exists
+func importedTemplateName(tx *gorm.DB, shared sharedPromptTemplate) (string, error) {
+	base := strings.TrimSpace(shared.Name)
+	name := base
+	for i := 2; ; i++ {
+		var existing PromptTemplate
+		result := tx.Where("name = ?", name).Limit(1).Find(&existing)
+		if result.Error != nil {
+			return "", result.Error
+		}
+		if result.RowsAffected == 0 {
+			return name, nil
+		}
+		if existing.Kind == shared.Kind && existing.Body == shared.Body {
+			return "", nil
+		}
+		name = fmt.Sprintf("%s (%d)", base, i)
+	}
+}
+
+// ImportPromptTemplates creates the templates of a file written by ExportPromptTemplates.
+// Templates identical to an existing one are skipped, and a template whose name is taken
+// is imported under a new name. It returns the templates created.
+func ImportPromptTemplates(path string) ([]PromptTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file promptTemplateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("not a prompt template file: %v", err)
+	}
+	if file.Version > promptTemplateFileVersion {
+		return nil, fmt.Errorf("prompt template file version %d is newer than this app supports (%d), please update YumeSession", file.Version, promptTemplateFileVersion)
+	}
+
+	// Validate everything first so a bad file imports nothing
+	for _, shared := range file.Templates {
+		if err := validatePromptTemplate(shared.Name, shared.Kind, shared.Body); err != nil {
+			return nil, fmt.Errorf("template %q: %v", shared.Name, err)
+		}
+	}
+
+	var imported []PromptTemplate
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		for _, shared := range file.Templates {
+			name, err := importedTemplateName(tx, shared)
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				continue
+			}
+
+			template := PromptTemplate{
+				Name:        name,
+				Kind:        shared.Kind,
+				Description: shared.Description,
+				Body:        shared.Body,
+				Version:     1,
+			}
+			if err := tx.Create(&template).Error; err != nil {
+				return err
+			}
+			imported = append(imported, template)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to import prompt templates from %s: %v", path, err)
+		return nil, err
+	}
+	log.Printf("Imported %d of %d prompt templates from %s", len(imported), len(file.Templates), path)
+	return imported, nil
+}
+
+// Prompt template methods exposed to the frontend
+
+func (a *App) ListPromptTemplates(kind string) ([]PromptTemplate, error) {
+	return ListPromptTemplates(kind)
+}
+
+func (a *App) ListPromptVariables() []PromptVariable {
+	return PromptVariables
+}
+
+func (a *App) CreatePromptTemplate(name, kind, description, body string) (*PromptTemplate, error) {
+	return CreatePromptTemplate(name, kind, description, body)
+}
+
+func (a *App) UpdatePromptTemplate(id uint, name, description, body string) (*PromptTemplate, error) {
+	return UpdatePromptTemplate(id, name, description, body)
+}
+
+func (a *App) DeletePromptTemplate(id uint) error {
+	return DeletePromptTemplate(id)
+}
+
+func (a *App) GetWorkspacePromptTemplate(workspaceID uint, kind string) (*PromptTemplate, error) {
+	return GetWorkspacePromptTemplate(workspaceID, kind)
+}
+
+func (a *App) SetWorkspacePromptTemplate(workspaceID uint, kind string, templateID uint) error {
+	return SetWorkspacePromptTemplate(workspaceID, kind, templateID)
+}
+
+func (a *App) PreviewPromptTemplate(workspaceID uint, body, message string) (string, error) {
+	return PreviewPromptTemplate(workspaceID, body, message)
+}
+
+// ExportPromptTemplates saves prompt templates to path, asking where to save them when path is empty.
+// It returns the path written, or an empty string if the dialog was cancelled.
+func (a *App) ExportPromptTemplates(ids []uint, path string) (string, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Export Prompt Templates",
+			DefaultFilename: "prompt-templates.json",
+			Filters:         []runtime.FileFilter{{DisplayName: "Prompt Templates", Pattern: "*.json"}},
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	if err := ExportPromptTemplates(ids, path); err != nil {
+		log.Printf("Failed to export prompt templates: %v", err)
+		return "", err
+	}
+	return path, nil
+}
+
+// ImportPromptTemplates imports prompt templates from path, asking for the file when path is empty.
+// It returns nil without an error if the dialog was cancelled.
+func (a *App) ImportPromptTemplates(path string) ([]PromptTemplate, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "Import Prompt Templates",
+			Filters: []runtime.FileFilter{{DisplayName: "Prompt Templates", Pattern: "*.json"}},
+		})
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+	return ImportPromptTemplates(path)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderChatSystemPromptUsesChatContext(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Planning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := CreatePromptTemplate("Grounded", PromptKindChat, "", "Answer from these sources only.\n{{.Knowledge}}\nTranscript:\n{{.Transcript}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetWorkspacePromptTemplate(workspace.ID, PromptKindChat, template.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	chatContext := ChatContext{
+		Transcription: []string{"[Summary 09:00–09:10] Budget review", "Alice: marketing gets 40%"},
+		MeetingNotes:  "# Budget",
+		Knowledge: []RetrievedChunk{
+			{Item: KnowledgeBase{ID: 3, UniqueFileName: "budget.txt"}, Chunk: KnowledgeChunk{ID: 9, Offset: 120, Text: "Marketing: 40%"}},
+		},
+	}
+	systemPrompt := renderChatSystemPrompt(workspace.ID, "How much for marketing?", &chatContext)
+
+	want := "Answer from these sources only.\n[1] budget.txt, offset 120:\nMarketing: 40%\nTranscript:\n[Summary 09:00–09:10] Budget review\nAlice: marketing gets 40%"
+	if systemPrompt != want {
+		t.Errorf("system prompt =\n%s\nwant\n%s", systemPrompt, want)
+	}
+	if citations := chatContext.Citations(); len(citations) != 1 || citations[0].Index != 1 || citations[0].KnowledgeBaseID != 3 {
+		t.Errorf("citations = %+v, want [1] for the excerpt in the system prompt", citations)
+	}
+
+	// The user prompt only keeps what the template left out
+	userPrompt := buildChatPrompt(chatContext, "How much for marketing?")
+	if strings.Contains(userPrompt, "Transcription") || strings.Contains(userPrompt, "Knowledge Base Excerpts") {
+		t.Errorf("user prompt repeats the template sections:\n%s", userPrompt)
+	}
+	if !strings.Contains(userPrompt, "Meeting Notes:\n# Budget") || !strings.Contains(userPrompt, "User Message:\nHow much for marketing?") {
+		t.Errorf("user prompt =\n%s", userPrompt)
+	}
+	if chatContext.Info.EstimatedTokens != estimateTokens(systemPrompt)+estimateTokens(userPrompt) {
+		t.Errorf("estimated tokens = %d", chatContext.Info.EstimatedTokens)
+	}
+}
+
+func TestRenderChatSystemPromptBuiltIn(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Planning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chatContext := ChatContext{Transcription: []string{"Alice: hello"}}
+	if systemPrompt := renderChatSystemPrompt(workspace.ID, "Hi", &chatContext); systemPrompt != builtInPromptBody(PromptKindChat) {
+		t.Errorf("system prompt = %q", systemPrompt)
+	}
+	if userPrompt := buildChatPrompt(chatContext, "Hi"); !strings.Contains(userPrompt, "Transcription (oldest first):\nAlice: hello") {
+		t.Errorf("user prompt =\n%s", userPrompt)
+	}
+}
+
+func TestPromptNotesDoNotCreateMeetingNotes(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Planning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := PreviewPromptTemplate(workspace.ID, "Notes: {{.Notes}}", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview != "Notes: " {
+		t.Errorf("preview = %q", preview)
+	}
+	if notes, err := GetMeetingNotesByWorkspace(workspace.ID); err != nil || len(notes) != 0 {
+		t.Errorf("rendering a prompt left meeting notes %v, %v", notes, err)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ChatProvider is an LLM backend that can answer chat messages
+type ChatProvider interface {
+	// Name returns the identifier used in settings and on workspaces ("ollama", "openai", "python")
+	Name() string
+	// StreamChat generates a reply, calling onToken for every streamed token. It blocks until
+	// the reply is complete or the context is cancelled, which is how a generation is stopped.
+	StreamChat(ctx context.Context, req ChatStreamRequest, onToken func(token string)) (*ChatCompletionStats, error)
+	// Complete generates a reply without streaming, with the same stats as StreamChat
+	Complete(ctx context.Context, messages []ChatMessage) (string, *ChatCompletionStats, error)
+	// ListModels returns the models the backend can serve
+	ListModels(ctx context.Context) ([]string, error)
+	// Capabilities describes what the backend supports
+	Capabilities() ProviderCapabilities
+}
+
+// ChatStreamRequest represents a single chat generation request
+type ChatStreamRequest struct {
+	RequestID   string
+	WorkspaceID uint
+	Model       string // empty = provider's configured model
+	Messages    []ChatMessage
+	ReplyToID   *uint      // stored user message being answered, if any
+	Citations   []Citation // knowledge base chunks included in the prompt
+}
+
+// ChatCompletionStats describes a finished generation
+type ChatCompletionStats struct {
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"promptTokens"`
+	CompletionTokens int    `json:"completionTokens"`
+}
+
+// ProviderCapabilities describes the features supported by a chat provider
+type ProviderCapabilities struct {
+	Streaming    bool `json:"streaming"`
+	Cancellation bool `json:"cancellation"`
+	ModelListing bool `json:"modelListing"`
+	TokenCounts  bool `json:"tokenCounts"`
+}
+
+// ChatProviderInfo is the frontend view of a registered provider
+type ChatProviderInfo struct {
+	Name         string               `json:"name"`
+	Capabilities ProviderCapabilities `json:"capabilities"`
+}
+
+// chatProviders holds every available provider by name
+var chatProviders = map[string]ChatProvider{
+	ChatBackendPython: &PythonBridgeProvider{},
+	ChatBackendOllama: &OllamaProvider{},
+	ChatBackendOpenAI: &OpenAICompatibleProvider{},
+}
+
+// getChatProvider returns the provider registered under name
+func getChatProvider(name string) (ChatProvider, error) {
+	provider, ok := chatProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown chat provider: %s", name)
+	}
+	return provider, nil
+}
+
+// chatProviderForWorkspace returns the provider selected for a workspace,
+// falling back to the default provider from settings
+func chatProviderForWorkspace(workspaceID uint) (ChatProvider, error) {
+	name := GetSetting(SettingChatProvider)
+	if workspaceID != 0 {
+		workspace, err := GetWorkspaceByID(workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if workspace.ChatBackend != "" {
+			name = workspace.ChatBackend
+		}
+	}
+	return getChatProvider(name)
+}
+
+// activeGenerations maps the request ID of every in-flight chat generation to the
+// CancelFunc of its context
+var activeGenerations sync.Map
+
+// trackGeneration registers requestID as in flight and returns the context its generation
+// must run with. It is registered before the generation starts, so a cancel arriving right
+// after the request ID was handed out still stops it.
+func trackGeneration(requestID string) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	activeGenerations.Store(requestID, cancel)
+	return ctx
+}
+
+// finishGeneration unregisters requestID and releases its context
+func finishGeneration(requestID string) {
+	if value, ok := activeGenerations.LoadAndDelete(requestID); ok {
+		value.(context.CancelFunc)()
+	}
+}
+
+// contextError replaces err with the context's error once ctx is done, so that callers
+// can tell a cancelled generation from a failed one with errors.Is
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// completeByStreaming implements Complete on top of StreamChat
+func completeByStreaming(ctx context.Context, provider ChatProvider, messages []ChatMessage) (string, *ChatCompletionStats, error) {
+	var reply strings.Builder
+	stats, err := provider.StreamChat(ctx, ChatStreamRequest{Messages: messages}, func(token string) {
+		reply.WriteString(token)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return reply.String(), stats, nil
+}
+
+// runChatGeneration streams a reply from provider with the context trackGeneration returned,
+// and forwards it to the frontend as chatStreamStart/chatStreamChunk/chatStreamDone events.
+// The reply is stored as an AIChatMessage before chatStreamDone is emitted.
+func (a *App) runChatGeneration(ctx context.Context, provider ChatProvider, req ChatStreamRequest) {
+	defer finishGeneration(req.RequestID)
+
+	startedAt := time.Now()
+	var reply strings.Builder
+
+	runtime.EventsEmit(a.ctx, "chatStreamStart", map[string]interface{}{
+		"message":     "Starting response...",
+		"provider":    provider.Name(),
+		"requestId":   req.RequestID,
+		"workspaceId": req.WorkspaceID,
+	})
+
+	stats, err := provider.StreamChat(ctx, req, func(token string) {
+		reply.WriteString(token)
+		runtime.EventsEmit(a.ctx, "chatStreamChunk", map[string]interface{}{
+			"token":       token,
+			"done":        false,
+			"requestId":   req.RequestID,
+			"workspaceId": req.WorkspaceID,
+		})
+	})
+	if errors.Is(err, context.Canceled) {
+		log.Printf("Chat generation %s cancelled", req.RequestID)
+		cancelled := map[string]interface{}{
+			"requestId":   req.RequestID,
+			"workspaceId": req.WorkspaceID,
+			"replyToId":   req.ReplyToID,
+		}
+		// Keep the part of the reply the user has already seen
+		if reply.Len() > 0 {
+			citations := markCitedSources(req.Citations, reply.String())
+			if msg, err := CreateAssistantReply(req.WorkspaceID, req.ReplyToID, reply.String(), nil, time.Since(startedAt), citations); err == nil {
+				cancelled["messageId"] = msg.ID
+			}
+		}
+		runtime.EventsEmit(a.ctx, "chatStreamCancelled", cancelled)
+		return
+	}
+	if err != nil {
+		log.Printf("Chat generation via %s failed: %v", provider.Name(), err)
+		runtime.EventsEmit(a.ctx, "chatStreamError", map[string]interface{}{
+			"error":       err.Error(),
+			"requestId":   req.RequestID,
+			"workspaceId": req.WorkspaceID,
+		})
+		return
+	}
+
+	done := map[string]interface{}{
+		"done":        true,
+		"message":     "Response complete",
+		"requestId":   req.RequestID,
+		"workspaceId": req.WorkspaceID,
+		"replyToId":   req.ReplyToID,
+	}
+	if stats != nil {
+		done["model"] = stats.Model
+		done["promptTokens"] = stats.PromptTokens
+		done["completionTokens"] = stats.CompletionTokens
+	}
+	citations := markCitedSources(req.Citations, reply.String())
+	done["citations"] = citations
+	if req.WorkspaceID != 0 {
+		latency := time.Since(startedAt)
+		done["latencyMs"] = latency.Milliseconds()
+		if msg, err := CreateAssistantReply(req.WorkspaceID, req.ReplyToID, reply.String(), stats, latency, citations); err == nil {
+			done["messageId"] = msg.ID
+		}
+	}
+	runtime.EventsEmit(a.ctx, "chatStreamDone", done)
+}
+
+// CancelChatGeneration stops an in-flight chat generation started by SendChatMessage.
+// The frontend receives chatStreamCancelled once the generation has stopped.
+func (a *App) CancelChatGeneration(requestID string) error {
+	value, ok := activeGenerations.Load(requestID)
+	if !ok {
+		return fmt.Errorf("no generation in progress for request %s", requestID)
+	}
+	value.(context.CancelFunc)()
+	return nil
+}
+
+// GetChatProviders lists the available chat providers and their capabilities
+func (a *App) GetChatProviders() []ChatProviderInfo {
+	names := make([]string, 0, len(chatProviders))
+	for name := range chatProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	providers := make([]ChatProviderInfo, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, ChatProviderInfo{
+			Name:         name,
+			Capabilities: chatProviders[name].Capabilities(),
+		})
+	}
+	return providers
+}
+
+// ListChatModels lists the models served by a chat provider
+func (a *App) ListChatModels(providerName string) ([]string, error) {
+	provider, err := getChatProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return provider.ListModels(ctx)
+}
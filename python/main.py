@@ -1,7 +1,7 @@
 # pip install fastapi uvicorn playwright websockets
 # playwright install chromium
 
-from fastapi import FastAPI, Response, Request, WebSocket, WebSocketDisconnect
+from fastapi import FastAPI, HTTPException, Response, Request, WebSocket, WebSocketDisconnect
 from fastapi.responses import StreamingResponse, HTMLResponse
 from fastapi.middleware.cors import CORSMiddleware
 from playwright.async_api import async_playwright
@@ -519,6 +519,7 @@ async def get_mjpeg_stream_fast():
 class ChatRequest(BaseModel):
     message: str
     system_prompt: str = "You are a helpful AI assistant."
+    model: str = ""
 
 class ChatResponse(BaseModel):
     response: str
@@ -533,12 +534,13 @@ async def chat_endpoint(request: ChatRequest):
     try:
         # Create a simple chat workflow
         workflow = AgentWorkflow(name="Chat Assistant")
+        model_name = f"ollama:{request.model}" if request.model else "ollama:granite3.3:8b"
         
         workflow.add_agent(
             name="Assistant",
             role="A helpful AI assistant",
             instructions=request.system_prompt,
-            llm=llm,
+            llm=ChatModel.from_name(model_name) if request.model else llm,
         )
         
         # Run the chat workflow
@@ -553,14 +555,11 @@ async def chat_endpoint(request: ChatRequest):
         
         return ChatResponse(
             response=response.result.final_answer,
-            model="ollama:granite3.3:8b"
+            model=model_name
         )
     
     except Exception as e:
-        return ChatResponse(
-            response=f"Error: {str(e)}",
-            model="ollama:granite3.3:8b"
-        )
+        raise HTTPException(status_code=500, detail=str(e))
 
 @app.post("/chat/stream")
 async def chat_stream_endpoint(request: ChatRequest):
@@ -618,174 +617,206 @@ async def chat_stream_endpoint(request: ChatRequest):
         }
     )
 
+async def run_markdown_agent_request(websocket: WebSocket, message_data: dict):
+    """Generate meeting notes for one markdown agent request, streaming the result over the WebSocket"""
+    request_id = message_data.get("request_id", "")
+
+    async def send(payload: dict):
+        payload["request_id"] = request_id
+        await websocket.send_text(json.dumps(payload))
+
+    try:
+        user_message = message_data.get("message", "")
+        system_prompt = (
+            """
+            You are a live markdown meeting note assistant that generates live and concise notes as the events get unfolded to provide the user live context about the current meeting so you need to make sure your notes emcompasses everything with as much less words as possible.\n
+            In fact, you are not receiving a full transcript, you are receiving a list of recent transcripts and current meeting notes, so you need to make sure your notes emcompasses everything with less than 100 words as possible, atmost 150\n
+            Do not add any place holders, and do not explain. Summary for keypoint should be less than 10 words,
+            You will receive two types of content from the user: Transcription List and Current Meeting Markdown\n
+                Instructions:\n
+                1. Analyze the recent transcript and current notes\n
+                2. Update the meeting notes to incorporate new information from the transcript and keep it concise ideally just with tit\n
+                3. Maintain the existing structure and formatting\n
+                4. Add new key points, decisions, or action items as needed\n
+                5. Update the timestamp at the bottom\n
+                6. Keep the notes professional and concise, don't make too much changes if there's already an existing markdown structure, just update the part that needs to be updated\n
+                7. Return ONLY the updated notes in markdown format, no explanations\n
+
+                It should be very concise and to the point, you should not make it too long, just update the part that needs to be updated, do not make too much changes if there's already an existing markdown structure, just update the part that needs to be updated\n
+                Follow this structure:
+                # Meeting Notes\n
+
+                Timestamp: 2022-01-01 10:00 AM\n
+                The current status of the meeting application is under review. While it functions currently, there are concerns about its reliability and stability that need to be addressed. \n
+
+                ## Action Items:\n
+                - Investigate further into the functionality and reliability issues of the meeting application.\n
+                You must ensure the notes is concise and less than 100 words, atmost 150 words, and you should not make too much changes if there's already an existing markdown structure, just update the part that needs to be updated\n
+
+                These are the two types of content you will receive:\n
+                {user_message}\n
+            """
+                       )
+        
+        if not user_message:
+            await send({
+                "type": "error",
+                "message": "No message provided"
+            })
+            return
+        
+        try:
+            # Try direct Ollama streaming first for real-time markdown generation
+            ollama_payload = {
+                "model": "granite3.3:8b",
+                "messages": [
+                    {"role": "system", "content": system_prompt},
+                    {"role": "user", "content": user_message}
+                ],
+                "stream": True
+            }
+            
+            async with aiohttp.ClientSession() as session:
+                try:
+                    async with session.post(
+                        "http://localhost:11434/api/chat",
+                        json=ollama_payload,
+                        headers={"Content-Type": "application/json"}
+                    ) as response:
+                        if response.status == 200:
+                            # Send start signal
+                            await send({
+                                "type": "start",
+                                "message": "Starting markdown generation..."
+                            })
+                            
+                            async for line in response.content:
+                                if line:
+                                    try:
+                                        # Parse each JSON line from Ollama stream
+                                        ollama_data = json.loads(line.decode('utf-8'))
+                                        
+                                        if 'message' in ollama_data and 'content' in ollama_data['message']:
+                                            token = ollama_data['message']['content']
+                                            is_done = ollama_data.get('done', False)
+                                            
+                                            if token:
+                                                # Send token immediately via WebSocket
+                                                await send({
+                                                    "type": "token",
+                                                    "content": token,
+                                                    "done": is_done,
+                                                    "model": "ollama:granite3.3:8b"
+                                                })
+                                            
+                                            if is_done:
+                                                await send({
+                                                    "type": "complete",
+                                                    "message": "Markdown generation complete"
+                                                })
+                                                break
+                                                
+                                    except json.JSONDecodeError:
+                                        continue
+                        else:
+                            raise Exception(f"Ollama API returned status {response.status}")
+                            
+                except Exception as ollama_error:
+                    # Fallback to BeeAI workflow with streaming
+                    await send({
+                        "type": "info",
+                        "message": "Using BeeAI workflow for markdown generation..."
+                    })
+                    
+                    workflow = AgentWorkflow(name="Streaming Markdown Agent")
+                    
+                    workflow.add_agent(
+                        name="Markdown Assistant",
+                        role="You are a helpful meeting note assistant that takes notes as the event unfolds",
+                        instructions=system_prompt,
+                        llm=llm,
+                    )
+                    
+                    # Run the workflow
+                    response = await workflow.run(
+                        inputs=[
+                            AgentWorkflowInput(
+                                prompt=user_message,
+                                expected_output=("\\n<GOTO>\\n - generates this first with them on a separate line. This command would move the cursor to that particular line. Signifies the end with </GOTO>\n"
+                                               "\\n```\\n - always makes sure to wrap the content inside the three backticks to indicate a code block which will be used by the actual editor to add your content. Signifies the end also with it\n")
+                            )
+                        ]
+                    )
+                    
+                    # Stream the response character by character for real-time effect
+                    full_response = response.result.final_answer
+                    current_chunk = ""
+                    
+                    # Send start signal for BeeAI fallback
+                    await send({
+                        "type": "start",
+                        "message": "Starting markdown generation..."
+                    })
+                    
+                    for i, char in enumerate(full_response):
+                        current_chunk += char
+                        
+                        # Send token on word boundaries or special markdown characters
+                        if char in [' ', '.', ',', '!', '?', '\n', ';', ':', '`', '#', '*', '-', '>', '|'] or i == len(full_response) - 1:
+                            await send({
+                                "type": "token",
+                                "content": current_chunk,
+                                "done": i == len(full_response) - 1,
+                                "model": "ollama:granite3.3:8b"
+                            })
+                            current_chunk = ""
+                            await asyncio.sleep(0.01)  # Very fast streaming for markdown
+                    
+                    await send({
+                        "type": "complete",
+                        "message": "Markdown generation complete"
+                    })
+                    
+        except Exception as e:
+            await send({
+                "type": "error",
+                "message": f"Error: {str(e)}"
+            })
+    except asyncio.CancelledError:
+        # Cancelled by a "cancel" frame from the client
+        try:
+            await send({
+                "type": "cancelled",
+                "message": "Generation cancelled"
+            })
+        except Exception:
+            pass
+
 @app.websocket("/ws/markdown_agent")
 async def websocket_markdown_agent(websocket: WebSocket):
     """WebSocket endpoint for real-time Markdown agent interaction with streaming"""
     await websocket.accept()
     
+    # Generations run as tasks so that "cancel" frames can be received while they stream
+    tasks = {}
+
     try:
         while True:
             # Receive message from client
             data = await websocket.receive_text()
             message_data = json.loads(data)
-            
-            user_message = message_data.get("message", "")
-            system_prompt = (
-                """
-                You are a live markdown meeting note assistant that generates live and concise notes as the events get unfolded to provide the user live context about the current meeting so you need to make sure your notes emcompasses everything with as much less words as possible.\n
-                In fact, you are not receiving a full transcript, you are receiving a list of recent transcripts and current meeting notes, so you need to make sure your notes emcompasses everything with less than 100 words as possible, atmost 150\n
-                Do not add any place holders, and do not explain. Summary for keypoint should be less than 10 words,
-                You will receive two types of content from the user: Transcription List and Current Meeting Markdown\n
-                    Instructions:\n
-                    1. Analyze the recent transcript and current notes\n
-                    2. Update the meeting notes to incorporate new information from the transcript and keep it concise ideally just with tit\n
-                    3. Maintain the existing structure and formatting\n
-                    4. Add new key points, decisions, or action items as needed\n
-                    5. Update the timestamp at the bottom\n
-                    6. Keep the notes professional and concise, don't make too much changes if there's already an existing markdown structure, just update the part that needs to be updated\n
-                    7. Return ONLY the updated notes in markdown format, no explanations\n
-
-                    It should be very concise and to the point, you should not make it too long, just update the part that needs to be updated, do not make too much changes if there's already an existing markdown structure, just update the part that needs to be updated\n
-                    Follow this structure:
-                    # Meeting Notes\n
-
-                    Timestamp: 2022-01-01 10:00 AM\n
-                    The current status of the meeting application is under review. While it functions currently, there are concerns about its reliability and stability that need to be addressed. \n
-
-                    ## Action Items:\n
-                    - Investigate further into the functionality and reliability issues of the meeting application.\n
-                    You must ensure the notes is concise and less than 100 words, atmost 150 words, and you should not make too much changes if there's already an existing markdown structure, just update the part that needs to be updated\n
-
-                    These are the two types of content you will receive:\n
-                    {user_message}\n
-                """
-                           )
-            
-            if not user_message:
-                await websocket.send_text(json.dumps({
-                    "type": "error",
-                    "message": "No message provided"
-                }))
+            request_id = message_data.get("request_id", "")
+
+            if message_data.get("type") == "cancel":
+                task = tasks.get(request_id)
+                if task:
+                    task.cancel()
                 continue
-            
-            try:
-                # Try direct Ollama streaming first for real-time markdown generation
-                ollama_payload = {
-                    "model": "granite3.3:8b",
-                    "messages": [
-                        {"role": "system", "content": system_prompt},
-                        {"role": "user", "content": user_message}
-                    ],
-                    "stream": True
-                }
-                
-                async with aiohttp.ClientSession() as session:
-                    try:
-                        async with session.post(
-                            "http://localhost:11434/api/chat",
-                            json=ollama_payload,
-                            headers={"Content-Type": "application/json"}
-                        ) as response:
-                            if response.status == 200:
-                                # Send start signal
-                                await websocket.send_text(json.dumps({
-                                    "type": "start",
-                                    "message": "Starting markdown generation..."
-                                }))
-                                
-                                async for line in response.content:
-                                    if line:
-                                        try:
-                                            # Parse each JSON line from Ollama stream
-                                            ollama_data = json.loads(line.decode('utf-8'))
-                                            
-                                            if 'message' in ollama_data and 'content' in ollama_data['message']:
-                                                token = ollama_data['message']['content']
-                                                is_done = ollama_data.get('done', False)
-                                                
-                                                if token:
-                                                    # Send token immediately via WebSocket
-                                                    await websocket.send_text(json.dumps({
-                                                        "type": "token",
-                                                        "content": token,
-                                                        "done": is_done,
-                                                        "model": "ollama:granite3.3:8b"
-                                                    }))
-                                                
-                                                if is_done:
-                                                    await websocket.send_text(json.dumps({
-                                                        "type": "complete",
-                                                        "message": "Markdown generation complete"
-                                                    }))
-                                                    break
-                                                    
-                                        except json.JSONDecodeError:
-                                            continue
-                            else:
-                                raise Exception(f"Ollama API returned status {response.status}")
-                                
-                    except Exception as ollama_error:
-                        # Fallback to BeeAI workflow with streaming
-                        await websocket.send_text(json.dumps({
-                            "type": "info",
-                            "message": "Using BeeAI workflow for markdown generation..."
-                        }))
-                        
-                        workflow = AgentWorkflow(name="Streaming Markdown Agent")
-                        
-                        workflow.add_agent(
-                            name="Markdown Assistant",
-                            role="You are a helpful meeting note assistant that takes notes as the event unfolds",
-                            instructions=system_prompt,
-                            llm=llm,
-                        )
-                        
-                        # Run the workflow
-                        response = await workflow.run(
-                            inputs=[
-                                AgentWorkflowInput(
-                                    prompt=user_message,
-                                    expected_output=("\\n<GOTO>\\n - generates this first with them on a separate line. This command would move the cursor to that particular line. Signifies the end with </GOTO>\n"
-                                                   "\\n```\\n - always makes sure to wrap the content inside the three backticks to indicate a code block which will be used by the actual editor to add your content. Signifies the end also with it\n")
-                                )
-                            ]
-                        )
-                        
-                        # Stream the response character by character for real-time effect
-                        full_response = response.result.final_answer
-                        current_chunk = ""
-                        
-                        # Send start signal for BeeAI fallback
-                        await websocket.send_text(json.dumps({
-                            "type": "start",
-                            "message": "Starting markdown generation..."
-                        }))
-                        
-                        for i, char in enumerate(full_response):
-                            current_chunk += char
-                            
-                            # Send token on word boundaries or special markdown characters
-                            if char in [' ', '.', ',', '!', '?', '\n', ';', ':', '`', '#', '*', '-', '>', '|'] or i == len(full_response) - 1:
-                                await websocket.send_text(json.dumps({
-                                    "type": "token",
-                                    "content": current_chunk,
-                                    "done": i == len(full_response) - 1,
-                                    "model": "ollama:granite3.3:8b"
-                                }))
-                                current_chunk = ""
-                                await asyncio.sleep(0.01)  # Very fast streaming for markdown
-                        
-                        await websocket.send_text(json.dumps({
-                            "type": "complete",
-                            "message": "Markdown generation complete"
-                        }))
-                        
-            except Exception as e:
-                await websocket.send_text(json.dumps({
-                    "type": "error",
-                    "message": f"Error: {str(e)}"
-                }))
-                
+
+            task = asyncio.create_task(run_markdown_agent_request(websocket, message_data))
+            tasks[request_id] = task
+            task.add_done_callback(lambda done, rid=request_id: tasks.pop(rid, None) if tasks.get(rid) is done else None)
+
     except WebSocketDisconnect:
         print("Markdown agent WebSocket client disconnected")
     except Exception as e:
@@ -797,163 +828,205 @@ async def websocket_markdown_agent(websocket: WebSocket):
             }))
         except:
             pass
+    finally:
+        for task in tasks.values():
+            task.cancel()
+
+async def run_chat_request(websocket: WebSocket, message_data: dict):
+    """Answer one chat request, streaming the reply over the WebSocket"""
+    request_id = message_data.get("request_id", "")
+    workspace_id = message_data.get("workspace_id", 0)
+
+    async def send(payload: dict):
+        # Several requests may be streaming at once; the ids let the client route each response
+        payload["request_id"] = request_id
+        payload["workspace_id"] = workspace_id
+        await websocket.send_text(json.dumps(payload))
+
+    try:
+        # Support multiple message types
+        user_message = message_data.get("message", "")
+        transcription = message_data.get("transcription", [])
+        chat_history = message_data.get("chat-history", [])
+        meeting_notes = message_data.get("meeting-notes", "")
+        system_prompt = message_data.get("system_prompt", "You are a helpful AI assistant.")
+        model = message_data.get("model") or "granite3.3:8b"
+
+        # Build a combined prompt if any of the new fields are present
+        if transcription or chat_history or meeting_notes:
+            combined_prompt = ""
+            if transcription:
+                combined_prompt += "Transcription (latest first):\n" + "\n".join(transcription) + "\n\n"
+            if chat_history:
+                # Extract 'text' from each dict in chat_history, fallback to str if not dict
+                chat_history_lines = []
+                for entry in chat_history:
+                    if isinstance(entry, dict) and "text" in entry:
+                        chat_history_lines.append(entry["text"])
+                    else:
+                        chat_history_lines.append(str(entry))
+                combined_prompt += "Chat History (latest first):\n" + "\n".join(chat_history_lines) + "\n\n"
+            if meeting_notes:
+                combined_prompt += f"Meeting Notes:\n{meeting_notes}\n\n"
+            if user_message:
+                combined_prompt += f"User Message:\n{user_message}\n"
+            user_message_final = combined_prompt.strip()
+        else:
+            user_message_final = user_message
+
+        if not user_message_final:
+            await send({
+                "type": "error",
+                "message": "No message provided"
+            })
+            return
+        
+        try:
+            # Try direct Ollama streaming first
+            ollama_payload = {
+                "model": model,
+                "messages": [
+                    {"role": "system", "content": system_prompt},
+                    {"role": "user", "content": user_message_final}
+                ],
+                "stream": True
+            }
+            
+            async with aiohttp.ClientSession() as session:
+                try:
+                    async with session.post(
+                        "http://localhost:11434/api/chat",
+                        json=ollama_payload,
+                        headers={"Content-Type": "application/json"}
+                    ) as response:
+                        if response.status == 200:
+                            # Send start signal
+                            await send({
+                                "type": "start",
+                                "message": "Starting response..."
+                            })
+                            
+                            async for line in response.content:
+                                if line:
+                                    try:
+                                        # Parse each JSON line from Ollama stream
+                                        ollama_data = json.loads(line.decode('utf-8'))
+                                        
+                                        if 'message' in ollama_data and 'content' in ollama_data['message']:
+                                            token = ollama_data['message']['content']
+                                            is_done = ollama_data.get('done', False)
+                                            
+                                            if token:
+                                                # Send token immediately via WebSocket
+                                                await send({
+                                                    "type": "token",
+                                                    "content": token,
+                                                    "done": is_done
+                                                })
+                                            
+                                            if is_done:
+                                                await send({
+                                                    "type": "complete",
+                                                    "message": "Response complete",
+                                                    "model": model,
+                                                    "prompt_tokens": ollama_data.get("prompt_eval_count", 0),
+                                                    "completion_tokens": ollama_data.get("eval_count", 0)
+                                                })
+                                                break
+                                            
+                                    except json.JSONDecodeError:
+                                        continue
+                        else:
+                            raise Exception(f"Ollama API returned status {response.status}")
+                            
+                except Exception as ollama_error:
+                    # Fallback to BeeAI workflow
+                    await send({
+                        "type": "info",
+                        "message": "Using BeeAI workflow..."
+                    })
+                    
+                    workflow = AgentWorkflow(name="WebSocket Chat Assistant")
+                    
+                    workflow.add_agent(
+                        name="Assistant",
+                        role="A helpful AI assistant",
+                        instructions=system_prompt,
+                        llm=llm,
+                    )
+                    
+                    # Run the workflow
+                    response = await workflow.run(
+                        inputs=[
+                            AgentWorkflowInput(
+                                prompt=user_message_final,
+                                expected_output="A helpful and accurate response to the user's question or request."
+                            )
+                        ]
+                    )
+                    
+                    # Stream the response character by character for real-time effect
+                    full_response = response.result.final_answer
+                    current_word = ""
+                    
+                    for i, char in enumerate(full_response):
+                        current_word += char
+                        
+                        # Send token on word boundaries or end of text
+                        if char in [' ', '.', ',', '!', '?', '\n', ';', ':'] or i == len(full_response) - 1:
+                            await send({
+                                "type": "token",
+                                "content": current_word,
+                                "done": i == len(full_response) - 1
+                            })
+                            current_word = ""
+                            await asyncio.sleep(0.02)  # Small delay for streaming effect
+                    
+                    await send({
+                        "type": "complete",
+                        "message": "Response complete"
+                    })
+                    
+        except Exception as e:
+            await send({
+                "type": "error",
+                "message": f"Error: {str(e)}"
+            })
+    except asyncio.CancelledError:
+        # Cancelled by a "cancel" frame from the client
+        try:
+            await send({
+                "type": "cancelled",
+                "message": "Generation cancelled"
+            })
+        except Exception:
+            pass
 
 @app.websocket("/ws/chat")
 async def websocket_chat_endpoint(websocket: WebSocket):
     """WebSocket endpoint for real-time chat streaming supporting transcription, chat-history, and meeting-notes"""
     await websocket.accept()
     
+    # Generations run as tasks so that "cancel" frames can be received while they stream
+    tasks = {}
+
     try:
         while True:
             # Receive message from client
             data = await websocket.receive_text()
             message_data = json.loads(data)
+            request_id = message_data.get("request_id", "")
 
-            # Support multiple message types
-            user_message = message_data.get("message", "")
-            transcription = message_data.get("transcription", [])
-            chat_history = message_data.get("chat-history", [])
-            meeting_notes = message_data.get("meeting-notes", "")
-            system_prompt = message_data.get("system_prompt", "You are a helpful AI assistant.")
-
-            # Build a combined prompt if any of the new fields are present
-            if transcription or chat_history or meeting_notes:
-                combined_prompt = ""
-                if transcription:
-                    combined_prompt += "Transcription (latest first):\n" + "\n".join(transcription) + "\n\n"
-                if chat_history:
-                    # Extract 'text' from each dict in chat_history, fallback to str if not dict
-                    chat_history_lines = []
-                    for entry in chat_history:
-                        if isinstance(entry, dict) and "text" in entry:
-                            chat_history_lines.append(entry["text"])
-                        else:
-                            chat_history_lines.append(str(entry))
-                    combined_prompt += "Chat History (latest first):\n" + "\n".join(chat_history_lines) + "\n\n"
-                if meeting_notes:
-                    combined_prompt += f"Meeting Notes:\n{meeting_notes}\n\n"
-                if user_message:
-                    combined_prompt += f"User Message:\n{user_message}\n"
-                user_message_final = combined_prompt.strip()
-            else:
-                user_message_final = user_message
-
-            if not user_message_final:
-                await websocket.send_text(json.dumps({
-                    "type": "error",
-                    "message": "No message provided"
-                }))
+            if message_data.get("type") == "cancel":
+                task = tasks.get(request_id)
+                if task:
+                    task.cancel()
                 continue
-            
-            try:
-                # Try direct Ollama streaming first
-                ollama_payload = {
-                    "model": "granite3.3:8b",
-                    "messages": [
-                        {"role": "system", "content": system_prompt},
-                        {"role": "user", "content": user_message_final}
-                    ],
-                    "stream": True
-                }
-                
-                async with aiohttp.ClientSession() as session:
-                    try:
-                        async with session.post(
-                            "http://localhost:11434/api/chat",
-                            json=ollama_payload,
-                            headers={"Content-Type": "application/json"}
-                        ) as response:
-                            if response.status == 200:
-                                # Send start signal
-                                await websocket.send_text(json.dumps({
-                                    "type": "start",
-                                    "message": "Starting response..."
-                                }))
-                                
-                                async for line in response.content:
-                                    if line:
-                                        try:
-                                            # Parse each JSON line from Ollama stream
-                                            ollama_data = json.loads(line.decode('utf-8'))
-                                            
-                                            if 'message' in ollama_data and 'content' in ollama_data['message']:
-                                                token = ollama_data['message']['content']
-                                                is_done = ollama_data.get('done', False)
-                                                
-                                                if token:
-                                                    # Send token immediately via WebSocket
-                                                    await websocket.send_text(json.dumps({
-                                                        "type": "token",
-                                                        "content": token,
-                                                        "done": is_done
-                                                    }))
-                                                
-                                                if is_done:
-                                                    await websocket.send_text(json.dumps({
-                                                        "type": "complete",
-                                                        "message": "Response complete"
-                                                    }))
-                                                    break
-                                                
-                                        except json.JSONDecodeError:
-                                            continue
-                            else:
-                                raise Exception(f"Ollama API returned status {response.status}")
-                                
-                    except Exception as ollama_error:
-                        # Fallback to BeeAI workflow
-                        await websocket.send_text(json.dumps({
-                            "type": "info",
-                            "message": "Using BeeAI workflow..."
-                        }))
-                        
-                        workflow = AgentWorkflow(name="WebSocket Chat Assistant")
-                        
-                        workflow.add_agent(
-                            name="Assistant",
-                            role="A helpful AI assistant",
-                            instructions=system_prompt,
-                            llm=llm,
-                        )
-                        
-                        # Run the workflow
-                        response = await workflow.run(
-                            inputs=[
-                                AgentWorkflowInput(
-                                    prompt=user_message_final,
-                                    expected_output="A helpful and accurate response to the user's question or request."
-                                )
-                            ]
-                        )
-                        
-                        # Stream the response character by character for real-time effect
-                        full_response = response.result.final_answer
-                        current_word = ""
-                        
-                        for i, char in enumerate(full_response):
-                            current_word += char
-                            
-                            # Send token on word boundaries or end of text
-                            if char in [' ', '.', ',', '!', '?', '\n', ';', ':'] or i == len(full_response) - 1:
-                                await websocket.send_text(json.dumps({
-                                    "type": "token",
-                                    "content": current_word,
-                                    "done": i == len(full_response) - 1
-                                }))
-                                current_word = ""
-                                await asyncio.sleep(0.02)  # Small delay for streaming effect
-                        
-                        await websocket.send_text(json.dumps({
-                            "type": "complete",
-                            "message": "Response complete"
-                        }))
-                        
-            except Exception as e:
-                await websocket.send_text(json.dumps({
-                    "type": "error",
-                    "message": f"Error: {str(e)}"
-                }))
-                
+
+            task = asyncio.create_task(run_chat_request(websocket, message_data))
+            tasks[request_id] = task
+            task.add_done_callback(lambda done, rid=request_id: tasks.pop(rid, None) if tasks.get(rid) is done else None)
+
     except WebSocketDisconnect:
         print("WebSocket client disconnected")
     except Exception as e:
@@ -965,6 +1038,9 @@ async def websocket_chat_endpoint(websocket: WebSocket):
             }))
         except:
             pass
+    finally:
+        for task in tasks.values():
+            task.cancel()
 
 @app.get("/chat/models")
 async def get_available_models():
@@ -1019,6 +1095,46 @@ async def test_chat():
 class FilePathPayload(BaseModel):
     file_path: str
 
+def convert_to_markdown(path: str) -> str:
+    """Convert a document to markdown using Docling"""
+    pipeline_options = PdfPipelineOptions()
+    pipeline_options.do_ocr = True
+    pipeline_options.do_table_structure = True
+    pipeline_options.table_structure_options.do_cell_matching = True
+
+    doc_converter = DocumentConverter(
+        allowed_formats=[
+            InputFormat.PDF,
+            InputFormat.IMAGE,
+            InputFormat.DOCX,
+            InputFormat.HTML,
+            InputFormat.PPTX,
+            InputFormat.ASCIIDOC,
+            InputFormat.MD,
+        ],
+        format_options={
+            InputFormat.PDF: PdfFormatOption(pipeline_options=pipeline_options),
+        },
+    )
+
+    conv_result = doc_converter.convert(path)
+    return conv_result.document.export_to_markdown()
+
+@app.post("/extract")
+async def extract_endpoint(file_path: FilePathPayload):
+    """
+    Extract the text of a document as markdown, used to index the knowledge base.
+    Returns:
+        dict: {"text": ...}
+    """
+    try:
+        # Docling is CPU bound, keep the event loop free for streaming chats
+        markdown = await asyncio.to_thread(convert_to_markdown, file_path.file_path)
+        return {"text": markdown}
+    except Exception as e:
+        logging.exception("Error in /extract endpoint")
+        return {"error": str(e)}
+
 @app.post("/summarize")
 async def summarize_endpoint(file_path: FilePathPayload):
     """
@@ -1030,28 +1146,7 @@ async def summarize_endpoint(file_path: FilePathPayload):
     """
     try:
         # Step 1: Extract markdown using Docling
-        pipeline_options = PdfPipelineOptions()
-        pipeline_options.do_ocr = True
-        pipeline_options.do_table_structure = True
-        pipeline_options.table_structure_options.do_cell_matching = True
-
-        doc_converter = DocumentConverter(
-            allowed_formats=[
-                InputFormat.PDF,
-                InputFormat.IMAGE,
-                InputFormat.DOCX,
-                InputFormat.HTML,
-                InputFormat.PPTX,
-                InputFormat.ASCIIDOC,
-                InputFormat.MD,
-            ],
-            format_options={
-                InputFormat.PDF: PdfFormatOption(pipeline_options=pipeline_options),
-            },
-        )
-
-        conv_result = doc_converter.convert(file_path.file_path)
-        markdown = conv_result.document.export_to_markdown()
+        markdown = convert_to_markdown(file_path.file_path)
 
         # Step 2: Summarize with Ollama Granite
         ollama_payload = {
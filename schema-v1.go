@@ -0,0 +1,150 @@
+package main
+
+import "time"
+
+// The tables of the first migration, frozen as they were when versioned migrations were
+// introduced. AutoMigrate of these structs creates the tables of a new database, and adds the
+// missing columns to databases from before versioning. Never change them, later schema
+// changes are separate migrations written as SQL.
+
+type workspaceV1 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Title        string `gorm:"not null"`
+	Description  string
+	ChatBackend  string `gorm:"default:python"`
+	LastOpenTime time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (workspaceV1) TableName() string { return "workspaces" }
+
+type transcriptionRecordV1 struct {
+	ID          uint      `gorm:"primaryKey"`
+	MessageID   string    `gorm:"uniqueIndex:idx_transcription_records_message_id;not null"`
+	WorkspaceID uint      `gorm:"not null"`
+	SessionID   *uint     `gorm:"index:idx_transcription_records_session_id"`
+	Text        string    `gorm:"not null"`
+	Speaker     string    `gorm:"not null"`
+	Timestamp   time.Time `gorm:"not null"`
+	Source      string
+	MessageType string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Workspace workspaceV1 `gorm:"foreignKey:WorkspaceID"`
+}
+
+func (transcriptionRecordV1) TableName() string { return "transcription_records" }
+
+type knowledgeBaseV1 struct {
+	ID             uint `gorm:"primaryKey"`
+	UniqueFileName string
+	Type           string `gorm:"not null"`
+	OneLineSummary string `gorm:"not null"`
+	FullSummary    string `gorm:"type:text"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (knowledgeBaseV1) TableName() string { return "knowledge_bases" }
+
+type meetingNotesV1 struct {
+	ID          uint   `gorm:"primaryKey"`
+	WorkspaceID uint   `gorm:"uniqueIndex:idx_meeting_notes_workspace_id;not null"`
+	Text        string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Workspace workspaceV1 `gorm:"foreignKey:WorkspaceID"`
+}
+
+func (meetingNotesV1) TableName() string { return "meeting_notes" }
+
+type aiChatMessageV1 struct {
+	ID               uint   `gorm:"primaryKey"`
+	WorkspaceID      uint   `gorm:"not null"`
+	SessionID        *uint  `gorm:"index:idx_ai_chat_messages_session_id"`
+	By               string `gorm:"not null"`
+	Text             string `gorm:"type:text"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Model            string
+	LatencyMs        int64
+	PromptTokens     int
+	CompletionTokens int
+	ReplyToID        *uint  `gorm:"index:idx_ai_chat_messages_reply_to_id"`
+	Citations        string `gorm:"type:text"` // JSON
+
+	Workspace workspaceV1 `gorm:"foreignKey:WorkspaceID"`
+}
+
+func (aiChatMessageV1) TableName() string { return "ai_chat_messages" }
+
+type sessionV1 struct {
+	ID           uint `gorm:"primaryKey"`
+	WorkspaceID  uint `gorm:"not null;index:idx_sessions_workspace_id"`
+	Title        string
+	Source       string
+	Status       string    `gorm:"not null;index:idx_sessions_status"`
+	StartTime    time.Time `gorm:"not null"`
+	EndTime      *time.Time
+	Participants string `gorm:"type:text"` // JSON
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Pauses    []sessionPauseV1 `gorm:"foreignKey:SessionID"`
+	Workspace workspaceV1      `gorm:"foreignKey:WorkspaceID"`
+}
+
+func (sessionV1) TableName() string { return "sessions" }
+
+type sessionPauseV1 struct {
+	ID        uint      `gorm:"primaryKey"`
+	SessionID uint      `gorm:"not null;index:idx_session_pauses_session_id"`
+	PausedAt  time.Time `gorm:"not null"`
+	ResumedAt *time.Time
+}
+
+func (sessionPauseV1) TableName() string { return "session_pauses" }
+
+type settingV1 struct {
+	Key       string `gorm:"primaryKey"`
+	Value     string `gorm:"type:text"`
+	UpdatedAt time.Time
+}
+
+func (settingV1) TableName() string { return "settings" }
+
+type transcriptSummaryV1 struct {
+	ID            uint `gorm:"primaryKey"`
+	WorkspaceID   uint `gorm:"not null;uniqueIndex:idx_transcript_summary_segment"`
+	FirstRecordID uint `gorm:"not null;uniqueIndex:idx_transcript_summary_segment"`
+	LastRecordID  uint `gorm:"not null;uniqueIndex:idx_transcript_summary_segment"`
+	StartTime     time.Time
+	EndTime       time.Time
+	Text          string `gorm:"type:text"`
+	Model         string
+	CreatedAt     time.Time
+}
+
+func (transcriptSummaryV1) TableName() string { return "transcript_summaries" }
+
+type knowledgeChunkV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	KnowledgeBaseID uint   `gorm:"not null;index:idx_knowledge_chunks_knowledge_base_id"`
+	Ordinal         int    `gorm:"not null"`
+	Offset          int    `gorm:"not null"`
+	Text            string `gorm:"type:text"`
+	Embedding       []byte
+	EmbeddingModel  string `gorm:"index:idx_knowledge_chunks_embedding_model"`
+	CreatedAt       time.Time
+}
+
+func (knowledgeChunkV1) TableName() string { return "knowledge_chunks" }
+
+// schemaV1 lists the frozen tables in creation order
+var schemaV1 = []interface{}{
+	&workspaceV1{}, &transcriptionRecordV1{}, &knowledgeBaseV1{}, &meetingNotesV1{}, &aiChatMessageV1{},
+	&sessionV1{}, &sessionPauseV1{}, &settingV1{}, &transcriptSummaryV1{}, &knowledgeChunkV1{},
+}
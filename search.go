@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Types of records returned by GlobalSearch
+const (
+	SearchTypeTranscript = "transcript"
+	SearchTypeNotes      = "notes"
+	SearchTypeChat       = "chat"
+	SearchTypeKnowledge  = "knowledge"
+)
+
+// Default and maximum number of results returned by GlobalSearch
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// Markers put around matches by the database, replaced by <mark> once the snippet is escaped
+const (
+	highlightStart = "\x02"
+	highlightEnd   = "\x03"
+)
+
+// SearchFilters narrows down a GlobalSearch
+type SearchFilters struct {
+	WorkspaceID uint      `json:"workspaceId"` // 0 searches every workspace; knowledge base items are shared and always searched
+	Types       []string  `json:"types"`       // record types to search, all of them when empty
+	From        time.Time `json:"from"`        // only records at or after this time, if set
+	To          time.Time `json:"to"`          // only records at or before this time, if set
+	Limit       int       `json:"limit"`
+}
+
+// SearchResult is a record matching a GlobalSearch, best matches first
+type SearchResult struct {
+	Type           string    `json:"type"`
+	ID             uint      `json:"id"`
+	WorkspaceID    uint      `json:"workspaceId"`
+	WorkspaceTitle string    `json:"workspaceTitle"`
+	Title          string    `json:"title"`   // speaker, message author or knowledge base item summary
+	Snippet        string    `json:"snippet"` // HTML-escaped, matches wrapped in <mark>
+	Timestamp      time.Time `json:"timestamp"`
+	Score          float64   `json:"score"` // 1 for the best match of each type, lower further down its ranking
+}
+
+// searchSource describes how one table is searched
+type searchSource struct {
+	Type            string
+	Table           string
+	Columns         []string // columns indexed for full-text search
+	SnippetColumn   int      // index into Columns the snippet is taken from, -1 for the best one
+	TitleExpr       string
+	TimeColumn      string
+	WorkspaceColumn string // empty for tables not tied to a workspace
+	SoftDelete      bool   // rows have a deleted_at column, set while their workspace is in the trash
+}
+
+var searchSources = []searchSource{
+	{
+		Type:            SearchTypeTranscript,
+		Table:           "transcription_records",
+		Columns:         []string{"text", "speaker"},
+		SnippetColumn:   0,
+		TitleExpr:       "t.speaker",
+		TimeColumn:      "timestamp",
+		WorkspaceColumn: "workspace_id",
+		SoftDelete:      true,
+	},
+	{
+		Type:            SearchTypeNotes,
+		Table:           "meeting_notes",
+		Columns:         []string{"text"},
+		SnippetColumn:   0,
+		TitleExpr:       "'Meeting notes'",
+		TimeColumn:      "updated_at",
+		WorkspaceColumn: "workspace_id",
+		SoftDelete:      true,
+	},
+	{
+		Type:            SearchTypeChat,
+		Table:           "ai_chat_messages",
+		Columns:         []string{"text"},
+		SnippetColumn:   0,
+		TitleExpr:       "t.by",
+		TimeColumn:      "created_at",
+		WorkspaceColumn: "workspace_id",
+		SoftDelete:      true,
+	},
+	{
+		Type:          SearchTypeKnowledge,
+		Table:         "knowledge_bases",
+		Columns:       []string{"one_line_summary", "full_summary"},
+		SnippetColumn: -1,
+		TitleExpr:     "t.one_line_summary",
+		TimeColumn:    "updated_at",
+	},
+}
+
+// ftsTable is the name of the FTS5 index of a source table
+func (s searchSource) ftsTable() string {
+	return s.Table + "_fts"
+}
+
+// ftsAvailable is set when the SQLite library was built with FTS5 (the sqlite_fts5 build tag)
+// and the search index exists. Without it GlobalSearch falls back to LIKE matching.
+var ftsAvailable bool
+
+// searchIndexStatements create the FTS5 index of every search source, the triggers keeping
+// them in sync with their tables, and fill them with the existing rows
+var searchIndexStatements = []string{
+	"CREATE VIRTUAL TABLE IF NOT EXISTS transcription_records_fts USING fts5(text, speaker, content='transcription_records', content_rowid='id', tokenize='porter unicode61')",
+	"CREATE TRIGGER IF NOT EXISTS transcription_records_fts_ai AFTER INSERT ON transcription_records BEGIN INSERT INTO transcription_records_fts(rowid, text, speaker) VALUES (new.id, new.text, new.speaker); END",
+	"CREATE TRIGGER IF NOT EXISTS transcription_records_fts_ad AFTER DELETE ON transcription_records BEGIN INSERT INTO transcription_records_fts(transcription_records_fts, rowid, text, speaker) VALUES ('delete', old.id, old.text, old.speaker); END",
+	"CREATE TRIGGER IF NOT EXISTS transcription_records_fts_au AFTER UPDATE ON transcription_records BEGIN INSERT INTO transcription_records_fts(transcription_records_fts, rowid, text, speaker) VALUES ('delete', old.id, old.text, old.speaker); INSERT INTO transcription_records_fts(rowid, text, speaker) VALUES (new.id, new.text, new.speaker); END",
+	"INSERT INTO transcription_records_fts(transcription_records_fts) VALUES ('rebuild')",
+
+	"CREATE VIRTUAL TABLE IF NOT EXISTS meeting_notes_fts USING fts5(text, content='meeting_notes', content_rowid='id', tokenize='porter unicode61')",
+	"CREATE TRIGGER IF NOT EXISTS meeting_notes_fts_ai AFTER INSERT ON meeting_notes BEGIN INSERT INTO meeting_notes_fts(rowid, text) VALUES (new.id, new.text); END",
+	"CREATE TRIGGER IF NOT EXISTS meeting_notes_fts_ad AFTER DELETE ON meeting_notes BEGIN INSERT INTO meeting_notes_fts(meeting_notes_fts, rowid, text) VALUES ('delete', old.id, old.text); END",
+	"CREATE TRIGGER IF NOT EXISTS meeting_notes_fts_au AFTER UPDATE ON meeting_notes BEGIN INSERT INTO meeting_notes_fts(meeting_notes_fts, rowid, text) VALUES ('delete', old.id, old.text); INSERT INTO meeting_notes_fts(rowid, text) VALUES (new.id, new.text); END",
+	"INSERT INTO meeting_notes_fts(meeting_notes_fts) VALUES ('rebuild')",
+
+	"CREATE VIRTUAL TABLE IF NOT EXISTS ai_chat_messages_fts USING fts5(text, content='ai_chat_messages', content_rowid='id', tokenize='porter unicode61')",
+	"CREATE TRIGGER IF NOT EXISTS ai_chat_messages_fts_ai AFTER INSERT ON ai_chat_messages BEGIN INSERT INTO ai_chat_messages_fts(rowid, text) VALUES (new.id, new.text); END",
+	"CREATE TRIGGER IF NOT EXISTS ai_chat_messages_fts_ad AFTER DELETE ON ai_chat_messages BEGIN INSERT INTO ai_chat_messages_fts(ai_chat_messages_fts, rowid, text) VALUES ('delete', old.id, old.text); END",
+	"CREATE TRIGGER IF NOT EXISTS ai_chat_messages_fts_au AFTER UPDATE ON ai_chat_messages BEGIN INSERT INTO ai_chat_messages_fts(ai_chat_messages_fts, rowid, text) VALUES ('delete', old.id, old.text); INSERT INTO ai_chat_messages_fts(rowid, text) VALUES (new.id, new.text); END",
+	"INSERT INTO ai_chat_messages_fts(ai_chat_messages_fts) VALUES ('rebuild')",
+
+	"CREATE VIRTUAL TABLE IF NOT EXISTS knowledge_bases_fts USING fts5(one_line_summary, full_summary, content='knowledge_bases', content_rowid='id', tokenize='porter unicode61')",
+	"CREATE TRIGGER IF NOT EXISTS knowledge_bases_fts_ai AFTER INSERT ON knowledge_bases BEGIN INSERT INTO knowledge_bases_fts(rowid, one_line_summary, full_summary) VALUES (new.id, new.one_line_summary, new.full_summary); END",
+	"CREATE TRIGGER IF NOT EXISTS knowledge_bases_fts_ad AFTER DELETE ON knowledge_bases BEGIN INSERT INTO knowledge_bases_fts(knowledge_bases_fts, rowid, one_line_summary, full_summary) VALUES ('delete', old.id, old.one_line_summary, old.full_summary); END",
+	"CREATE TRIGGER IF NOT EXISTS knowledge_bases_fts_au AFTER UPDATE ON knowledge_bases BEGIN INSERT INTO knowledge_bases_fts(knowledge_bases_fts, rowid, one_line_summary, full_summary) VALUES ('delete', old.id, old.one_line_summary, old.full_summary); INSERT INTO knowledge_bases_fts(rowid, one_line_summary, full_summary) VALUES (new.id, new.one_line_summary, new.full_summary); END",
+	"INSERT INTO knowledge_bases_fts(knowledge_bases_fts) VALUES ('rebuild')",
+}
+
+// fts5Enabled reports whether the SQLite library was built with FTS5
+func fts5Enabled(tx *gorm.DB) (bool, error) {
+	var enabled int
+	if err := tx.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled).Error; err != nil {
+		return false, err
+	}
+	return enabled == 1, nil
+}
+
+// searchTriggers lists the names of the triggers keeping the search index in sync
+func searchTriggers() []string {
+	var names []string
+	for _, source := range searchSources {
+		for _, suffix := range []string{"_ai", "_ad", "_au"} {
+			names = append(names, source.ftsTable()+suffix)
+		}
+	}
+	return names
+}
+
+// setupSearchIndex checks that the search index created by the migrations can be used. A
+// database migrated by a build without FTS5 gets its index on the first start with FTS5.
+func setupSearchIndex() error {
+	enabled, err := fts5Enabled(DB)
+	if err != nil {
+		return err
+	}
+
+	var triggers int64
+	if err := DB.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ?", searchTriggers()).Scan(&triggers).Error; err != nil {
+		return err
+	}
+
+	if !enabled {
+		// Triggers left by a build with FTS5 would make every write fail
+		if triggers > 0 {
+			for _, name := range searchTriggers() {
+				if err := DB.Exec("DROP TRIGGER IF EXISTS " + name).Error; err != nil {
+					return err
+				}
+			}
+		}
+		log.Printf("SQLite was built without FTS5, search falls back to LIKE matching")
+		return nil
+	}
+
+	// The index is missing, or rows were written while it wasn't kept in sync
+	if triggers < int64(len(searchTriggers())) {
+		if err := DB.Transaction(func(tx *gorm.DB) error {
+			return execStatements(tx, searchIndexStatements...)
+		}); err != nil {
+			return fmt.Errorf("failed to build search index: %v", err)
+		}
+		log.Printf("Built search index")
+	}
+	ftsAvailable = true
+	return nil
+}
+
+// searchRow is a raw search hit, before highlighting
+type searchRow struct {
+	ID             uint
+	WorkspaceID    uint
+	WorkspaceTitle string
+	Title          string
+	Timestamp      time.Time
+	Snippet        string
+	Body           string
+	Rank           float64
+}
+
+// GlobalSearch finds transcripts, meeting notes, chat messages and knowledge base items matching query
+func GlobalSearch(query string, filters SearchFilters) ([]SearchResult, error) {
+	terms := tokenizeForSearch(query)
+	if len(terms) == 0 {
+		return []SearchResult{}, nil
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	results := []SearchResult{}
+	for _, source := range searchSources {
+		if !searchesType(filters.Types, source.Type) {
+			continue
+		}
+
+		var rows []searchRow
+		var err error
+		if ftsAvailable {
+			rows, err = searchFullText(source, terms, filters, limit)
+		} else {
+			rows, err = searchLike(source, terms, filters, limit)
+		}
+		if err != nil {
+			log.Printf("Failed to search %s: %v", source.Table, err)
+			return nil, err
+		}
+
+		// bm25 scores of different tables are not comparable, results are merged by their
+		// rank within their own source instead. Rows scored the same share a rank.
+		position := 0
+		for i, row := range rows {
+			if i > 0 && row.Rank != rows[i-1].Rank {
+				position = i
+			}
+			results = append(results, SearchResult{
+				Type:           source.Type,
+				ID:             row.ID,
+				WorkspaceID:    row.WorkspaceID,
+				WorkspaceTitle: row.WorkspaceTitle,
+				Title:          row.Title,
+				Snippet:        highlightSnippet(row.Snippet),
+				Timestamp:      row.Timestamp,
+				Score:          1 / float64(position+1),
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchesType reports whether a search restricted to types includes searchType
+func searchesType(types []string, searchType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == searchType {
+			return true
+		}
+	}
+	return false
+}
+
+// searchQuery holds the parts of a search statement shared by both search strategies
+type searchQuery struct {
+	Columns    string // selected columns, aliased to the searchRow fields
+	Joins      string
+	Conditions string // filters, each prefixed with AND
+	Args       []interface{}
+}
+
+// newSearchQuery selects the result columns of source and applies filters
+func newSearchQuery(source searchSource, filters SearchFilters) searchQuery {
+	query := searchQuery{
+		Columns: fmt.Sprintf("t.id AS id, %s AS title, t.%s AS timestamp", source.TitleExpr, source.TimeColumn),
+	}
+
+	if source.WorkspaceColumn != "" {
+		query.Columns += fmt.Sprintf(", t.%s AS workspace_id, COALESCE(w.title, '') AS workspace_title", source.WorkspaceColumn)
+		query.Joins = fmt.Sprintf(" LEFT JOIN workspaces w ON w.id = t.%s", source.WorkspaceColumn)
+		if filters.WorkspaceID != 0 {
+			query.Conditions += fmt.Sprintf(" AND t.%s = ?", source.WorkspaceColumn)
+			query.Args = append(query.Args, filters.WorkspaceID)
+		}
+	}
+	if source.SoftDelete {
+		query.Conditions += " AND t.deleted_at IS NULL"
+	}
+	// Timestamps are stored as text in the zone they were recorded in, comparing them as
+	// strings would shift the range by the zone's offset. julianday converts both to UTC.
+	if !filters.From.IsZero() {
+		query.Conditions += fmt.Sprintf(" AND julianday(t.%s) >= julianday(?)", source.TimeColumn)
+		query.Args = append(query.Args, filters.From)
+	}
+	if !filters.To.IsZero() {
+		query.Conditions += fmt.Sprintf(" AND julianday(t.%s) <= julianday(?)", source.TimeColumn)
+		query.Args = append(query.Args, filters.To)
+	}
+	return query
+}
+
+// searchFullText ranks the rows of source matching any of terms with FTS5's bm25
+func searchFullText(source searchSource, terms []string, filters SearchFilters, limit int) ([]searchRow, error) {
+	fts := source.ftsTable()
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+
+	query := newSearchQuery(source, filters)
+	sql := fmt.Sprintf("SELECT %s, snippet(%s, %d, '%s', '%s', '…', 16) AS snippet, bm25(%s) AS rank"+
+		" FROM %s JOIN %s t ON t.id = %s.rowid%s WHERE %s MATCH ?%s ORDER BY rank LIMIT ?",
+		query.Columns, fts, source.SnippetColumn, highlightStart, highlightEnd, fts,
+		fts, source.Table, fts, query.Joins, fts, query.Conditions)
+
+	args := append([]interface{}{strings.Join(quoted, " OR ")}, query.Args...)
+	var rows []searchRow
+	err := DB.Raw(sql, append(args, limit)...).Scan(&rows).Error
+	return rows, err
+}
+
+// searchLike is the fallback when FTS5 is not available: rows containing more of the terms rank higher
+func searchLike(source searchSource, terms []string, filters SearchFilters, limit int) ([]searchRow, error) {
+	body := make([]string, len(source.Columns))
+	for i, column := range source.Columns {
+		body[i] = fmt.Sprintf("COALESCE(t.%s, '')", column)
+	}
+	bodyExpr := strings.Join(body, " || ' ' || ")
+	snippetExpr := bodyExpr
+	if source.SnippetColumn >= 0 {
+		snippetExpr = body[source.SnippetColumn]
+	}
+
+	matches := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		matches[i] = bodyExpr + " LIKE ?"
+		args[i] = "%" + term + "%"
+	}
+
+	query := newSearchQuery(source, filters)
+	sql := fmt.Sprintf("SELECT %s, %s AS body, %s AS snippet FROM %s t%s WHERE (%s)%s ORDER BY julianday(t.%s) DESC LIMIT ?",
+		query.Columns, bodyExpr, snippetExpr, source.Table, query.Joins, strings.Join(matches, " OR "), query.Conditions, source.TimeColumn)
+
+	args = append(args, query.Args...)
+	var rows []searchRow
+	if err := DB.Raw(sql, append(args, maxSearchLimit)...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	pattern := termsPattern(terms)
+	for i := range rows {
+		hits := map[string]bool{}
+		for _, match := range pattern.FindAllString(rows[i].Body, -1) {
+			hits[strings.ToLower(match)] = true
+		}
+		rows[i].Rank = -float64(len(hits))
+		rows[i].Snippet = likeSnippet(rows[i].Snippet, pattern)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Rank < rows[j].Rank })
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// termsPattern matches any of terms, ignoring case
+func termsPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}
+
+// likeSnippet cuts about 120 bytes of text around the first match and marks every match in it
+func likeSnippet(text string, pattern *regexp.Regexp) string {
+	const radius = 60
+
+	first := pattern.FindStringIndex(text)
+	if first == nil {
+		first = []int{0, 0}
+	}
+	start, end := first[0]-radius, first[1]+radius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(text) {
+		end, suffix = len(text), ""
+	}
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	window := text[start:end]
+	return prefix + pattern.ReplaceAllStringFunc(window, func(match string) string {
+		return highlightStart + match + highlightEnd
+	}) + suffix
+}
+
+// highlightSnippet escapes a snippet for display and turns the match markers into <mark> tags
+func highlightSnippet(snippet string) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, highlightStart, "<mark>")
+	return strings.ReplaceAll(snippet, highlightEnd, "</mark>")
+}
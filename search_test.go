@@ -0,0 +1,111 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useSearchIndex searches with FTS5 when the build has it, like the app does after startup
+func useSearchIndex(t *testing.T) {
+	t.Helper()
+	if err := setupSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ftsAvailable = false })
+}
+
+func TestGlobalSearchRanking(t *testing.T) {
+	useTestDatabase(t)
+	useSearchIndex(t)
+	workspace, err := CreateWorkspace("Pricing review", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	addCaption(t, workspace.ID, "a", "Alice", "The pricing page needs work", start)
+	addCaption(t, workspace.ID, "b", "Bob", "Pricing and the discount for <b>annual</b> plans", start.Add(time.Minute))
+	addCaption(t, workspace.ID, "c", "Carol", "Lunch is at noon", start.Add(2*time.Minute))
+	if _, err := CreateMeetingNotes(workspace.ID, "# Pricing\n- Discount for annual plans"); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := GlobalSearch("pricing discount", SearchFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transcripts []SearchResult
+	for _, result := range results {
+		if result.Type == SearchTypeTranscript {
+			transcripts = append(transcripts, result)
+		}
+		if result.WorkspaceTitle != "Pricing review" {
+			t.Errorf("result %+v has workspace title %q", result, result.WorkspaceTitle)
+		}
+	}
+	if len(transcripts) != 2 || len(results) != 3 {
+		t.Fatalf("results = %+v, want two transcript lines and the notes", results)
+	}
+	// Bob's line matches both terms
+	if transcripts[0].Title != "Bob" || transcripts[0].Score != 1 || transcripts[1].Score >= 1 {
+		t.Errorf("transcript results = %+v, want Bob's line first", transcripts)
+	}
+
+	snippet := transcripts[0].Snippet
+	if !strings.Contains(snippet, "<mark>Pricing</mark>") || !strings.Contains(snippet, "<mark>discount</mark>") {
+		t.Errorf("snippet %q does not mark the matches", snippet)
+	}
+	if !strings.Contains(snippet, "&lt;b&gt;annual&lt;/b&gt;") {
+		t.Errorf("snippet %q does not escape the text", snippet)
+	}
+
+	if results, err := GlobalSearch("pricing", SearchFilters{Types: []string{SearchTypeNotes}}); err != nil || len(results) != 1 || results[0].Type != SearchTypeNotes {
+		t.Errorf("notes search = %+v, %v", results, err)
+	}
+}
+
+func TestGlobalSearchTimeRange(t *testing.T) {
+	// Rows are stored in the local zone while the frontend sends UTC
+	local := time.Local
+	time.Local = time.FixedZone("PDT", -7*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	useTestDatabase(t)
+	useSearchIndex(t)
+	workspace, err := CreateWorkspace("Pricing review", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	addCaption(t, workspace.ID, "old", "Alice", "Old pricing discussion", now.Add(-3*time.Hour))
+	if _, err := CreateMeetingNotes(workspace.ID, "Pricing agreed"); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{"last hour", now.UTC().Add(-time.Hour), time.Time{}, []string{SearchTypeNotes}},
+		{"until two hours ago", time.Time{}, now.UTC().Add(-2 * time.Hour), []string{SearchTypeTranscript}},
+		{"around the old line", now.UTC().Add(-4 * time.Hour), now.UTC().Add(-2 * time.Hour), []string{SearchTypeTranscript}},
+		{"everything", time.Time{}, time.Time{}, []string{SearchTypeNotes, SearchTypeTranscript}},
+	}
+	for _, c := range cases {
+		results, err := GlobalSearch("pricing", SearchFilters{From: c.from, To: c.to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var types []string
+		for _, result := range results {
+			types = append(types, result.Type)
+		}
+		sort.Strings(types)
+		if strings.Join(types, ",") != strings.Join(c.want, ",") {
+			t.Errorf("%s: found %v, want %v", c.name, types, c.want)
+		}
+	}
+}
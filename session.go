@@ -0,0 +1,381 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+// Session states
+const (
+	SessionStatusActive  = "active"
+	SessionStatusPaused  = "paused"
+	SessionStatusStopped = "stopped"
+)
+
+// Session represents one recording (e.g. a single meeting) inside a workspace
+type Session struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	WorkspaceID  uint       `gorm:"not null;index" json:"workspaceId"`
+	Title        string     `json:"title"`
+	Source       string     `json:"source"`                       // "google-meet", "test", ...
+	Status       string     `gorm:"not null;index" json:"status"` // "active", "paused" or "stopped"
+	StartTime    time.Time  `gorm:"not null" json:"startTime"`
+	EndTime      *time.Time `json:"endTime"`
+	Participants []string   `gorm:"serializer:json" json:"participants"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set while the workspace is in the trash
+
+	// Paused intervals, in order
+	Pauses []SessionPause `gorm:"foreignKey:SessionID" json:"pauses"`
+
+	// Foreign key relationship
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// SessionPause represents an interval during which a session was paused
+type SessionPause struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SessionID uint       `gorm:"not null;index" json:"sessionId"`
+	PausedAt  time.Time  `gorm:"not null" json:"pausedAt"`
+	ResumedAt *time.Time `json:"resumedAt"` // nil while still paused
+}
+
+// ActiveDuration returns how long the session has been recording, excluding pauses
+func (s *Session) ActiveDuration(now time.Time) time.Duration {
+	end := now
+	if s.EndTime != nil {
+		end = *s.EndTime
+	}
+	total := end.Sub(s.StartTime)
+	for _, pause := range s.Pauses {
+		resumed := end
+		if pause.ResumedAt != nil {
+			resumed = *pause.ResumedAt
+		}
+		total -= resumed.Sub(pause.PausedAt)
+	}
+	if total < 0 {
+		return 0
+	}
+	return total
+}
+
+// StartSession creates a new active session for a workspace.
+// Only one session per workspace can be open (active or paused) at a time.
+func StartSession(workspaceID uint, title, source string) (*Session, error) {
+	if _, err := GetWorkspaceByID(workspaceID); err != nil {
+		return nil, err
+	}
+
+	if open, err := GetOpenSession(workspaceID); err != nil {
+		return nil, err
+	} else if open != nil {
+		return nil, fmt.Errorf("workspace %d already has an open session (ID %d)", workspaceID, open.ID)
+	}
+
+	if title == "" {
+		title = fmt.Sprintf("Session %s", time.Now().Format("2006-01-02 15:04"))
+	}
+
+	session := &Session{
+		WorkspaceID:  workspaceID,
+		Title:        title,
+		Source:       source,
+		Status:       SessionStatusActive,
+		StartTime:    time.Now(),
+		Participants: []string{},
+	}
+
+	result := DB.Create(session)
+	if result.Error != nil {
+		log.Printf("Failed to create session: %v", result.Error)
+		return nil, result.Error
+	}
+
+	log.Printf("Started session %d for workspace %d", session.ID, workspaceID)
+	return session, nil
+}
+
+// PauseSession pauses an active session
+func PauseSession(sessionID uint) (*Session, error) {
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != SessionStatusActive {
+		return nil, fmt.Errorf("session %d is not active (status: %s)", sessionID, session.Status)
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&SessionPause{SessionID: sessionID, PausedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Session{}).Where("id = ?", sessionID).Update("status", SessionStatusPaused).Error
+	})
+	if err != nil {
+		log.Printf("Failed to pause session %d: %v", sessionID, err)
+		return nil, err
+	}
+
+	log.Printf("Paused session %d", sessionID)
+	return GetSessionByID(sessionID)
+}
+
+// ResumeSession resumes a paused session
+func ResumeSession(sessionID uint) (*Session, error) {
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != SessionStatusPaused {
+		return nil, fmt.Errorf("session %d is not paused (status: %s)", sessionID, session.Status)
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := closeOpenPause(tx, sessionID, time.Now()); err != nil {
+			return err
+		}
+		return tx.Model(&Session{}).Where("id = ?", sessionID).Update("status", SessionStatusActive).Error
+	})
+	if err != nil {
+		log.Printf("Failed to resume session %d: %v", sessionID, err)
+		return nil, err
+	}
+
+	log.Printf("Resumed session %d", sessionID)
+	return GetSessionByID(sessionID)
+}
+
+// StopSession ends an active or paused session
+func StopSession(sessionID uint) (*Session, error) {
+	session, err := GetSessionByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == SessionStatusStopped {
+		return session, nil
+	}
+
+	now := time.Now()
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := closeOpenPause(tx, sessionID, now); err != nil {
+			return err
+		}
+		return tx.Model(&Session{}).Where("id = ?", sessionID).Updates(map[string]interface{}{
+			"status":   SessionStatusStopped,
+			"end_time": now,
+		}).Error
+	})
+	if err != nil {
+		log.Printf("Failed to stop session %d: %v", sessionID, err)
+		return nil, err
+	}
+
+	log.Printf("Stopped session %d", sessionID)
+	return GetSessionByID(sessionID)
+}
+
+// closeOpenPause sets ResumedAt on the session's open pause interval, if any
+func closeOpenPause(tx *gorm.DB, sessionID uint, at time.Time) error {
+	return tx.Model(&SessionPause{}).
+		Where("session_id = ? AND resumed_at IS NULL", sessionID).
+		Update("resumed_at", at).Error
+}
+
+// GetSessionByID retrieves a session (with its pauses) by ID
+func GetSessionByID(id uint) (*Session, error) {
+	var session Session
+	result := DB.Preload("Pauses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("paused_at ASC")
+	}).First(&session, id)
+	if result.Error != nil {
+		log.Printf("Failed to get session by ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// GetSessionsByWorkspace retrieves all sessions for a workspace, newest first
+func GetSessionsByWorkspace(workspaceID uint) ([]Session, error) {
+	var sessions []Session
+	result := DB.Preload("Pauses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("paused_at ASC")
+	}).Where("workspace_id = ?", workspaceID).Order("start_time DESC").Find(&sessions)
+	if result.Error != nil {
+		log.Printf("Failed to get sessions for workspace %d: %v", workspaceID, result.Error)
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// GetOpenSession returns the active or paused session of a workspace, or nil if there is none
+func GetOpenSession(workspaceID uint) (*Session, error) {
+	var session Session
+	result := DB.Where("workspace_id = ? AND status IN ?", workspaceID, []string{SessionStatusActive, SessionStatusPaused}).
+		Order("start_time DESC").First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		log.Printf("Failed to get open session for workspace %d: %v", workspaceID, result.Error)
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// GetRecordingSession returns the most recently started open session of any workspace, or nil
+// if no meeting is being recorded. Live captions are written to its workspace.
+func GetRecordingSession() (*Session, error) {
+	var session Session
+	result := DB.Where("status IN ?", []string{SessionStatusActive, SessionStatusPaused}).
+		Order("start_time DESC").First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		log.Printf("Failed to get recording session: %v", result.Error)
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// recordingWorkspaceID returns the workspace live captions are written to (0 = none)
+func recordingWorkspaceID() uint {
+	session, err := GetRecordingSession()
+	if err != nil || session == nil {
+		return 0
+	}
+	return session.WorkspaceID
+}
+
+// openSessionID returns the ID of the workspace's open session, or nil if there is none.
+// Used to link new records to the session that produced them.
+func openSessionID(workspaceID uint) *uint {
+	session, err := GetOpenSession(workspaceID)
+	if err != nil || session == nil {
+		return nil
+	}
+	return &session.ID
+}
+
+// AddSessionParticipant records a participant for a session if not already present
+func AddSessionParticipant(sessionID uint, participant string) error {
+	var session Session
+	if err := DB.First(&session, sessionID).Error; err != nil {
+		return err
+	}
+	for _, existing := range session.Participants {
+		if existing == participant {
+			return nil
+		}
+	}
+	session.Participants = append(session.Participants, participant)
+	return DB.Model(&session).Select("Participants").Updates(&Session{Participants: session.Participants}).Error
+}
+
+// UpdateSessionDetails updates a session's title and participant list
+func UpdateSessionDetails(sessionID uint, title string, participants []string) (*Session, error) {
+	var session Session
+	if err := DB.First(&session, sessionID).Error; err != nil {
+		return nil, err
+	}
+
+	if participants == nil {
+		participants = []string{}
+	}
+
+	result := DB.Model(&session).Select("Title", "Participants").Updates(&Session{Title: title, Participants: participants})
+	if result.Error != nil {
+		log.Printf("Failed to update session %d: %v", sessionID, result.Error)
+		return nil, result.Error
+	}
+	return GetSessionByID(sessionID)
+}
+
+// Session methods exposed to the frontend
+
+// StartSession starts recording a new session; live captions are written to its workspace until it stops.
+// Action items left open by earlier meetings are announced through the actionItemsCarriedOver
+// event and looked for in the transcript. Unless disabled in the settings, the meeting notes
+// are kept up to date while it records.
+func (a *App) StartSession(workspaceID uint, title, source string) (*Session, error) {
+	session, err := StartSession(workspaceID, title, source)
+	if err != nil {
+		return nil, err
+	}
+	a.announceCarriedOverActionItems(session)
+	a.startMentionTracker(session)
+	if liveNotesEnabled() {
+		if _, err := a.startLiveNotes(session); err != nil {
+			log.Printf("Failed to start live notes for session %d: %v", session.ID, err)
+		}
+	}
+	runtime.EventsEmit(a.ctx, "sessionStarted", session)
+	return session, nil
+}
+
+// PauseSession pauses a session; captions received while paused are not recorded
+func (a *App) PauseSession(sessionID uint) (*Session, error) {
+	session, err := PauseSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "sessionPaused", session)
+	return session, nil
+}
+
+// ResumeSession resumes a paused session
+func (a *App) ResumeSession(sessionID uint) (*Session, error) {
+	session, err := ResumeSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "sessionResumed", session)
+	return session, nil
+}
+
+// StopSession ends a session and extracts its action items, decisions and open questions
+// in the background
+func (a *App) StopSession(sessionID uint) (*Session, error) {
+	session, err := StopSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	stopLiveNotes(sessionID)
+	stopMentionTracker(sessionID)
+	a.extractMeetingItemsAsync(sessionID)
+	runtime.EventsEmit(a.ctx, "sessionStopped", session)
+	return session, nil
+}
+
+func (a *App) GetSessionByID(id uint) (*Session, error) {
+	return GetSessionByID(id)
+}
+
+func (a *App) GetSessionsByWorkspace(workspaceID uint) ([]Session, error) {
+	return GetSessionsByWorkspace(workspaceID)
+}
+
+// GetOpenSession returns the workspace's active or paused session, or null
+func (a *App) GetOpenSession(workspaceID uint) (*Session, error) {
+	return GetOpenSession(workspaceID)
+}
+
+func (a *App) UpdateSessionDetails(sessionID uint, title string, participants []string) (*Session, error) {
+	return UpdateSessionDetails(sessionID, title, participants)
+}
+
+func (a *App) GetTranscriptionMessagesBySession(sessionID uint) ([]TranscriptionRecord, error) {
+	return GetTranscriptionMessagesBySession(sessionID)
+}
+
+func (a *App) GetAIChatMessagesBySession(sessionID uint) ([]AIChatMessage, error) {
+	return GetAIChatMessagesBySession(sessionID)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Setting represents a single key/value application setting in the database
+type Setting struct {
+	Key       string    `gorm:"primaryKey" json:"key"`
+	Value     string    `gorm:"type:text" json:"value"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Setting keys
+const (
+	SettingChatProvider = "chat.provider" // provider used by new workspaces
+	SettingOllamaURL    = "ollama.url"
+	SettingOllamaModel  = "ollama.model"
+	SettingOpenAIURL    = "openai.url" // base URL including /v1, e.g. http://localhost:8080/v1
+	SettingOpenAIModel  = "openai.model"
+	SettingOpenAIAPIKey = "openai.apiKey"
+
+	SettingContextTokens = "chat.contextTokens" // token budget for the context of a chat request
+	SettingRetrievalTopK = "rag.topK"           // knowledge base chunks retrieved per chat question, 0 disables
+
+	SettingEmbeddingModel = "embedding.model" // Ollama model used to embed knowledge base chunks
+
+	SettingLiveNotesEnabled    = "liveNotes.enabled"    // whether sessions keep their meeting notes up to date
+	SettingLiveNotesInterval   = "liveNotes.interval"   // seconds between two live updates of the notes
+	SettingLiveNotesBatchLines = "liveNotes.batchLines" // new transcript lines that trigger an update early
+
+	SettingHotkeyRecap       = "hotkeys.recap" // global hotkey running /recap, e.g. "cmdorctrl+optionoralt+shift+r", empty for none
+	SettingHotkeyDefine      = "hotkeys.define"
+	SettingHotkeyReply       = "hotkeys.reply"
+	SettingHotkeyTranslate   = "hotkeys.translate"
+	SettingTranslateLanguage = "commands.translateLanguage" // language /translate uses when none is given
+)
+
+// defaultSettings holds the value used for every known setting that has not been saved yet
+var defaultSettings = map[string]string{
+	SettingChatProvider: ChatBackendPython,
+	SettingOllamaURL:    defaultOllamaURL,
+	SettingOllamaModel:  defaultChatModel,
+	SettingOpenAIURL:    "http://localhost:1234/v1",
+	SettingOpenAIModel:  "",
+	SettingOpenAIAPIKey: "",
+
+	SettingContextTokens: strconv.Itoa(defaultContextTokens),
+	SettingRetrievalTopK: strconv.Itoa(defaultRetrievalTopK),
+
+	SettingEmbeddingModel: defaultEmbeddingModel,
+
+	SettingLiveNotesEnabled:    "true",
+	SettingLiveNotesInterval:   strconv.Itoa(defaultLiveNotesInterval),
+	SettingLiveNotesBatchLines: strconv.Itoa(defaultLiveNotesBatchLines),
+
+	SettingHotkeyRecap:       "cmdorctrl+optionoralt+shift+r",
+	SettingHotkeyDefine:      "cmdorctrl+optionoralt+shift+d",
+	SettingHotkeyReply:       "cmdorctrl+optionoralt+shift+y",
+	SettingHotkeyTranslate:   "cmdorctrl+optionoralt+shift+t",
+	SettingTranslateLanguage: "English",
+}
+
+// GetSetting returns the stored value of a setting, or its default if it was never saved
+func GetSetting(key string) string {
+	var setting Setting
+	// Find instead of First: a missing setting is expected and should not be logged as an error
+	result := DB.Where("key = ?", key).Limit(1).Find(&setting)
+	if result.Error != nil {
+		log.Printf("Failed to get setting %s: %v", key, result.Error)
+		return defaultSettings[key]
+	}
+	if result.RowsAffected == 0 {
+		return defaultSettings[key]
+	}
+	return setting.Value
+}
+
+// SetSetting stores the value of a setting
+func SetSetting(key, value string) error {
+	if _, known := defaultSettings[key]; !known {
+		return fmt.Errorf("unknown setting: %s", key)
+	}
+
+	result := DB.Save(&Setting{Key: key, Value: value})
+	if result.Error != nil {
+		log.Printf("Failed to save setting %s: %v", key, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetAllSettings returns every known setting, with defaults filled in
+func GetAllSettings() (map[string]string, error) {
+	var stored []Setting
+	result := DB.Find(&stored)
+	if result.Error != nil {
+		log.Printf("Failed to get settings: %v", result.Error)
+		return nil, result.Error
+	}
+
+	settings := make(map[string]string, len(defaultSettings))
+	for key, value := range defaultSettings {
+		settings[key] = value
+	}
+	for _, setting := range stored {
+		settings[setting.Key] = setting.Value
+	}
+	return settings, nil
+}
+
+// Settings methods exposed to the frontend
+func (a *App) GetSettings() (map[string]string, error) {
+	return GetAllSettings()
+}
+
+// UpdateSettings saves several settings at once
+func (a *App) UpdateSettings(settings map[string]string) error {
+	for key, value := range settings {
+		if _, known := defaultSettings[key]; !known {
+			return fmt.Errorf("unknown setting: %s", key)
+		}
+		if key == SettingChatProvider {
+			if _, err := getChatProvider(value); err != nil {
+				return err
+			}
+		}
+		if err := validateHotkeySetting(key, value); err != nil {
+			return err
+		}
+	}
+
+	previousEmbeddingModel := GetSetting(SettingEmbeddingModel)
+	hotkeysChanged := false
+	for _, command := range assistantCommands {
+		if value, ok := settings[command.HotkeySetting]; ok && value != GetSetting(command.HotkeySetting) {
+			hotkeysChanged = true
+		}
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for key, value := range settings {
+			if err := tx.Save(&Setting{Key: key, Value: value}).Error; err != nil {
+				log.Printf("Failed to save setting %s: %v", key, err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Vectors from different models can't be compared, so re-embed the knowledge base
+	if GetSetting(SettingEmbeddingModel) != previousEmbeddingModel {
+		a.embedKnowledgeChunksAsync()
+	}
+	if hotkeysChanged {
+		a.refreshApplicationMenu()
+		a.registerGlobalHotkeys()
+	}
+	return nil
+}
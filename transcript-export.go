@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Transcript export formats
+const (
+	TranscriptFormatSRT      = "srt"
+	TranscriptFormatVTT      = "vtt"
+	TranscriptFormatText     = "txt"
+	TranscriptFormatMarkdown = "md"
+	TranscriptFormatJSON     = "json"
+)
+
+// Version of the JSON transcript schema, bumped on incompatible changes
+const transcriptJSONSchemaVersion = 1
+
+// Bounds of the estimated duration of a cue, which the transcript doesn't record
+const (
+	minCueDuration = 1500 * time.Millisecond
+	maxCueDuration = 10 * time.Second
+	wordsPerSecond = 2.5
+)
+
+// TranscriptExportOptions narrows down and shapes a transcript export
+type TranscriptExportOptions struct {
+	SessionID        *uint     `json:"sessionId"`        // only lines of this session, timed from its start
+	From             time.Time `json:"from"`             // only lines at or after this time, if set
+	To               time.Time `json:"to"`               // only lines at or before this time, if set
+	Speakers         []string  `json:"speakers"`         // only lines of these speakers, all when empty
+	MergeConsecutive bool      `json:"mergeConsecutive"` // join consecutive lines of the same speaker into one
+}
+
+// TranscriptSegment is one cue of an exported transcript
+type TranscriptSegment struct {
+	Index      int       `json:"index"`
+	StartMs    int64     `json:"startMs"` // offset from the start of the transcript
+	EndMs      int64     `json:"endMs"`
+	Timestamp  time.Time `json:"timestamp"`
+	Speaker    string    `json:"speaker"`
+	Text       string    `json:"text"`
+	MessageIDs []string  `json:"messageIds"`
+}
+
+// TranscriptDocument is the JSON export format
+type TranscriptDocument struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	WorkspaceID   uint                `json:"workspaceId"`
+	Workspace     string              `json:"workspace"`
+	SessionID     *uint               `json:"sessionId,omitempty"`
+	StartTime     time.Time           `json:"startTime"`
+	ExportedAt    time.Time           `json:"exportedAt"`
+	Speakers      []string            `json:"speakers"`
+	Segments      []TranscriptSegment `json:"segments"`
+}
+
+// estimatedCueDuration guesses how long it took to say text
+func estimatedCueDuration(text string) time.Duration {
+	duration := time.Duration(float64(len(strings.Fields(text))) / wordsPerSecond * float64(time.Second))
+	if duration < minCueDuration {
+		return minCueDuration
+	}
+	if duration > maxCueDuration {
+		return maxCueDuration
+	}
+	return duration
+}
+
+// buildTranscriptDocument loads, filters and times the transcript lines of a workspace
+func buildTranscriptDocument(workspaceID uint, options TranscriptExportOptions) (*TranscriptDocument, error) {
+	workspace, err := GetWorkspaceByID(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := DB.Where("workspace_id = ?", workspaceID)
+	if options.SessionID != nil {
+		query = query.Where("session_id = ?", *options.SessionID)
+	}
+	var records []TranscriptionRecord
+	if err := query.Order("timestamp ASC").Find(&records).Error; err != nil {
+		log.Printf("Failed to load transcript of workspace %d: %v", workspaceID, err)
+		return nil, err
+	}
+	// Timestamps are stored as text in the zone they were recorded in, so they are ordered
+	// and checked against the time range here rather than by comparing strings in SQL
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+
+	speakers := make(map[string]bool)
+	for _, speaker := range options.Speakers {
+		speakers[strings.ToLower(strings.TrimSpace(speaker))] = true
+	}
+
+	doc := &TranscriptDocument{
+		SchemaVersion: transcriptJSONSchemaVersion,
+		WorkspaceID:   workspace.ID,
+		Workspace:     workspace.Title,
+		SessionID:     options.SessionID,
+		ExportedAt:    time.Now(),
+		Speakers:      []string{},
+		Segments:      []TranscriptSegment{},
+	}
+
+	// Cues are timed from the start of the session, or of the first exported line
+	if options.SessionID != nil {
+		if session, err := GetSessionByID(*options.SessionID); err == nil {
+			doc.StartTime = session.StartTime
+		}
+	}
+
+	seenSpeakers := make(map[string]bool)
+	for _, record := range records {
+		if (!options.From.IsZero() && record.Timestamp.Before(options.From)) || (!options.To.IsZero() && record.Timestamp.After(options.To)) {
+			continue
+		}
+		text := strings.TrimSpace(record.Text)
+		if text == "" || (len(speakers) > 0 && !speakers[strings.ToLower(record.Speaker)]) {
+			continue
+		}
+		if doc.StartTime.IsZero() {
+			doc.StartTime = record.Timestamp
+		}
+		if !seenSpeakers[record.Speaker] {
+			seenSpeakers[record.Speaker] = true
+			doc.Speakers = append(doc.Speakers, record.Speaker)
+		}
+
+		if n := len(doc.Segments); options.MergeConsecutive && n > 0 && doc.Segments[n-1].Speaker == record.Speaker {
+			last := &doc.Segments[n-1]
+			last.Text += " " + text
+			last.MessageIDs = append(last.MessageIDs, record.MessageID)
+			last.EndMs = record.Timestamp.Sub(doc.StartTime).Milliseconds() + estimatedCueDuration(text).Milliseconds()
+			continue
+		}
+
+		start := record.Timestamp.Sub(doc.StartTime)
+		doc.Segments = append(doc.Segments, TranscriptSegment{
+			Index:      len(doc.Segments) + 1,
+			StartMs:    start.Milliseconds(),
+			EndMs:      (start + estimatedCueDuration(text)).Milliseconds(),
+			Timestamp:  record.Timestamp,
+			Speaker:    record.Speaker,
+			Text:       text,
+			MessageIDs: []string{record.MessageID},
+		})
+	}
+
+	// A cue ends when the next one starts, so subtitles never overlap
+	for i := 0; i+1 < len(doc.Segments); i++ {
+		if next := doc.Segments[i+1].StartMs; doc.Segments[i].EndMs > next {
+			doc.Segments[i].EndMs = next
+		}
+		if doc.Segments[i].EndMs <= doc.Segments[i].StartMs {
+			doc.Segments[i].EndMs = doc.Segments[i].StartMs + 1
+		}
+	}
+	return doc, nil
+}
+
+// formatCueTime formats an offset as HH:MM:SS followed by separator and milliseconds
+func formatCueTime(ms int64, separator string) string {
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
+}
+
+// escapeVTT escapes the characters WebVTT cue text treats as markup
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// renderTranscript writes a transcript document in the given format
+func renderTranscript(doc *TranscriptDocument, format string) (string, error) {
+	var b strings.Builder
+
+	switch strings.ToLower(format) {
+	case TranscriptFormatSRT:
+		for _, segment := range doc.Segments {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s: %s\n\n", segment.Index,
+				formatCueTime(segment.StartMs, ","), formatCueTime(segment.EndMs, ","),
+				segment.Speaker, segment.Text)
+		}
+
+	case TranscriptFormatVTT:
+		b.WriteString("WEBVTT\n\n")
+		for _, segment := range doc.Segments {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n<v %s>%s\n\n", segment.Index,
+				formatCueTime(segment.StartMs, "."), formatCueTime(segment.EndMs, "."),
+				escapeVTT(segment.Speaker), escapeVTT(segment.Text))
+		}
+
+	case TranscriptFormatText:
+		for _, segment := range doc.Segments {
+			fmt.Fprintf(&b, "[%s] %s: %s\n", segment.Timestamp.Local().Format("15:04:05"), segment.Speaker, segment.Text)
+		}
+
+	case TranscriptFormatMarkdown:
+		fmt.Fprintf(&b, "# %s — Transcript\n\n", doc.Workspace)
+		if !doc.StartTime.IsZero() {
+			fmt.Fprintf(&b, "_%s_\n\n", doc.StartTime.Local().Format("Monday, 2 January 2006 15:04"))
+		}
+		for _, segment := range doc.Segments {
+			fmt.Fprintf(&b, "**%s** (%s): %s\n\n", segment.Speaker, segment.Timestamp.Local().Format("15:04:05"), segment.Text)
+		}
+
+	case TranscriptFormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		b.Write(data)
+		b.WriteString("\n")
+
+	default:
+		return "", fmt.Errorf("unsupported transcript format %q", format)
+	}
+	return b.String(), nil
+}
+
+// ExportTranscript renders the transcript of a workspace as SRT, WebVTT, plain text, Markdown or JSON
+func ExportTranscript(workspaceID uint, format string, options TranscriptExportOptions) (string, error) {
+	doc, err := buildTranscriptDocument(workspaceID, options)
+	if err != nil {
+		return "", err
+	}
+	return renderTranscript(doc, format)
+}
+
+// ExportTranscript returns the transcript of a workspace in the given format
+// ("srt", "vtt", "txt", "md" or "json")
+func (a *App) ExportTranscript(workspaceID uint, format string, options TranscriptExportOptions) (string, error) {
+	return ExportTranscript(workspaceID, format, options)
+}
+
+// SaveTranscriptExport asks where to save the transcript of a workspace and writes it there.
+// It returns the path written, or an empty string if the dialog was cancelled.
+func (a *App) SaveTranscriptExport(workspaceID uint, format string, options TranscriptExportOptions) (string, error) {
+	content, err := ExportTranscript(workspaceID, format, options)
+	if err != nil {
+		return "", err
+	}
+	workspace, err := GetWorkspaceByID(workspaceID)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Transcript",
+		DefaultFilename: workspace.Title + "." + strings.ToLower(format),
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		log.Printf("Failed to write transcript to %s: %v", path, err)
+		return "", err
+	}
+	return path, nil
+}
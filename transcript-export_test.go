@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTranscriptSRT(t *testing.T) {
+	doc := &TranscriptDocument{Segments: []TranscriptSegment{
+		{Index: 1, StartMs: 0, EndMs: 1500, Speaker: "Alice", Text: "Hello"},
+		{Index: 2, StartMs: 3723004, EndMs: 3725504, Speaker: "Bob", Text: "An hour later"},
+	}}
+
+	got, err := renderTranscript(doc, "SRT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:00,000 --> 00:00:01,500\nAlice: Hello\n\n" +
+		"2\n01:02:03,004 --> 01:02:05,504\nBob: An hour later\n\n"
+	if got != want {
+		t.Errorf("SRT =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTranscriptVTT(t *testing.T) {
+	doc := &TranscriptDocument{Segments: []TranscriptSegment{
+		{Index: 1, StartMs: 250, EndMs: 2000, Speaker: "Q&A <host>", Text: "a < b & c"},
+	}}
+
+	got, err := renderTranscript(doc, TranscriptFormatVTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WEBVTT\n\n1\n00:00:00.250 --> 00:00:02.000\n<v Q&amp;A &lt;host&gt;>a &lt; b &amp; c\n\n"
+	if got != want {
+		t.Errorf("VTT =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := renderTranscript(doc, "docx"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestEstimatedCueDuration(t *testing.T) {
+	cases := []struct {
+		text string
+		want time.Duration
+	}{
+		{"Hi", minCueDuration},
+		{"one two three four five", 2 * time.Second},
+		{strings.Repeat("word ", 100), maxCueDuration},
+	}
+	for _, c := range cases {
+		if got := estimatedCueDuration(c.text); got != c.want {
+			t.Errorf("estimatedCueDuration(%d words) = %s, want %s", len(strings.Fields(c.text)), got, c.want)
+		}
+	}
+}
+
+// addCaption stores a transcript line of a workspace
+func addCaption(t *testing.T, workspaceID uint, id, speaker, text string, at time.Time) {
+	t.Helper()
+	if _, err := CreateTranscriptionMessage(id, workspaceID, text, speaker, "zoom", "caption", at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildTranscriptDocumentTiming(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Standup", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	addCaption(t, workspace.ID, "a", "Alice", "Morning everyone, let's start with the release status", start)
+	addCaption(t, workspace.ID, "b", "Bob", "Shipped", start.Add(2*time.Second))
+	addCaption(t, workspace.ID, "c", "Bob", "yesterday", start.Add(30*time.Second))
+	addCaption(t, workspace.ID, "d", "Alice", "Great", start.Add(time.Minute))
+
+	doc, err := buildTranscriptDocument(workspace.ID, TranscriptExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.StartTime.Equal(start) || strings.Join(doc.Speakers, ",") != "Alice,Bob" {
+		t.Errorf("start = %s, speakers = %v", doc.StartTime, doc.Speakers)
+	}
+	// Alice's first line would last 3.6s, it is cut where Bob starts
+	wantTimes := [][2]int64{{0, 2000}, {2000, 3500}, {30000, 31500}, {60000, 61500}}
+	if len(doc.Segments) != len(wantTimes) {
+		t.Fatalf("got %d segments, want %d", len(doc.Segments), len(wantTimes))
+	}
+	for i, want := range wantTimes {
+		if got := doc.Segments[i]; got.Index != i+1 || got.StartMs != want[0] || got.EndMs != want[1] {
+			t.Errorf("segment %d = #%d %d-%d, want %d-%d", i, got.Index, got.StartMs, got.EndMs, want[0], want[1])
+		}
+	}
+
+	merged, err := buildTranscriptDocument(workspace.ID, TranscriptExportOptions{MergeConsecutive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged.Segments) != 3 {
+		t.Fatalf("got %d merged segments, want 3", len(merged.Segments))
+	}
+	if bob := merged.Segments[1]; bob.Text != "Shipped yesterday" || bob.StartMs != 2000 || bob.EndMs != 31500 || len(bob.MessageIDs) != 2 {
+		t.Errorf("merged segment = %+v", bob)
+	}
+}
+
+func TestBuildTranscriptDocumentTimeRange(t *testing.T) {
+	useTestDatabase(t)
+	workspace, err := CreateWorkspace("Standup", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines recorded in different zones, as captions and the app store them
+	tokyo := time.FixedZone("JST", 9*60*60)
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	addCaption(t, workspace.ID, "a", "Alice", "before", start.Add(-time.Minute).In(tokyo))
+	addCaption(t, workspace.ID, "b", "Alice", "first", start)
+	addCaption(t, workspace.ID, "c", "Bob", "second", start.Add(time.Minute).In(tokyo))
+	addCaption(t, workspace.ID, "d", "Bob", "after", start.Add(3*time.Minute))
+
+	doc, err := buildTranscriptDocument(workspace.ID, TranscriptExportOptions{
+		From: start,
+		To:   start.Add(2 * time.Minute).In(time.FixedZone("PST", -8*60*60)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, segment := range doc.Segments {
+		texts = append(texts, segment.Text)
+	}
+	if strings.Join(texts, ",") != "first,second" {
+		t.Errorf("exported %v, want the lines between From and To in order", texts)
+	}
+	if len(doc.Segments) == 2 && doc.Segments[1].StartMs != 60000 {
+		t.Errorf("second line starts at %dms, want 60000", doc.Segments[1].StartMs)
+	}
+}
@@ -3,9 +3,10 @@ package main
 import (
 	"context"
 	_ "encoding/json"
-	_ "fmt"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 
 // TranscriptionMessage represents a message from the Chrome extension
 type TranscriptionMessage struct {
+	ID          string      `json:"id,omitempty"`          // stable message ID (assigned by the server if missing)
 	Type        string      `json:"type,omitempty"`        // "new_message", "message_update", or "keepalive"
 	Text        string      `json:"text"`                  // caption text content
 	Speaker     string      `json:"speaker"`               // speaker name or "System"
@@ -33,10 +35,70 @@ type TranscriptionServer struct {
 	app      *App
 	server   *http.Server // Add server reference for shutdown
 	shutdown chan bool    // Add shutdown channel
+
+	// Recently seen captions, used to resolve message_update events to a message ID
+	captions      []liveCaption
+	captionsMutex sync.Mutex
+}
+
+// liveCaption tracks a caption that can still be updated by the extension
+type liveCaption struct {
+	ID         string
+	Speaker    string
+	Text       string
+	ReceivedAt time.Time
+	Persisted  bool
 }
 
+// maxTrackedCaptions is how many recent captions are kept for update matching
+const maxTrackedCaptions = 50
+
 var transcriptionServer *TranscriptionServer
 
+// Workspace the transcript screen is showing (0 = none)
+var (
+	activeWorkspaceID    uint
+	activeWorkspaceMutex sync.RWMutex
+)
+
+// SetActiveWorkspace records the workspace the transcript screen is showing. Captions are
+// written to the workspace of the open session, not to this one.
+func (a *App) SetActiveWorkspace(workspaceID uint) error {
+	if workspaceID != 0 {
+		if _, err := GetWorkspaceByID(workspaceID); err != nil {
+			return fmt.Errorf("workspace %d not found: %v", workspaceID, err)
+		}
+	}
+
+	activeWorkspaceMutex.Lock()
+	activeWorkspaceID = workspaceID
+	activeWorkspaceMutex.Unlock()
+
+	log.Printf("Active workspace set to %d", workspaceID)
+	return nil
+}
+
+// GetActiveWorkspace returns the workspace the transcript screen is showing
+func (a *App) GetActiveWorkspace() uint {
+	return getActiveWorkspaceID()
+}
+
+// clearActiveWorkspace forgets workspaceID if the transcript screen was showing it
+func clearActiveWorkspace(workspaceID uint) {
+	activeWorkspaceMutex.Lock()
+	defer activeWorkspaceMutex.Unlock()
+	if activeWorkspaceID == workspaceID {
+		activeWorkspaceID = 0
+		log.Printf("Active workspace %d was deleted", workspaceID)
+	}
+}
+
+func getActiveWorkspaceID() uint {
+	activeWorkspaceMutex.RLock()
+	defer activeWorkspaceMutex.RUnlock()
+	return activeWorkspaceID
+}
+
 // InitializeTranscriptionServer starts the WebSocket server on port 8001
 func (a *App) InitializeTranscriptionServer() error {
 	transcriptionServer = &TranscriptionServer{
@@ -169,9 +231,172 @@ func (ts *TranscriptionServer) handleWebSocket(w http.ResponseWriter, r *http.Re
 
 		log.Printf("Received transcription message: %s from %s", message.Text, message.Speaker)
 
-		// Forward the transcription message to frontend
-		ts.forwardToFrontend(message)
+		// Persist the message and forward it to frontend
+		ts.processMessage(message)
+	}
+}
+
+// processMessage writes captions to the active workspace before forwarding them,
+// so the database stays the source of truth even if the frontend misses events
+func (ts *TranscriptionServer) processMessage(message TranscriptionMessage) {
+	switch message.Type {
+	case "new_message":
+		if !ts.recordNewCaption(&message) {
+			return
+		}
+	case "message_update":
+		if !ts.recordCaptionUpdate(&message) {
+			return
+		}
+	case "":
+		if message.Speaker != "System" && !ts.recordNewCaption(&message) {
+			return
+		}
+	}
+
+	ts.forwardToFrontend(message)
+}
+
+// recordNewCaption assigns a message ID and stores a new caption.
+// Returns false if the caption is a duplicate and should be dropped.
+func (ts *TranscriptionServer) recordNewCaption(message *TranscriptionMessage) bool {
+	ts.captionsMutex.Lock()
+	defer ts.captionsMutex.Unlock()
+
+	now := time.Now()
+
+	// The extension sometimes sends the same caption twice in quick succession
+	start := len(ts.captions) - 5
+	if start < 0 {
+		start = 0
+	}
+	for _, caption := range ts.captions[start:] {
+		if caption.Speaker == message.Speaker && caption.Text == message.Text && now.Sub(caption.ReceivedAt) < time.Second {
+			log.Printf("Dropping duplicate caption from %s: %s", message.Speaker, message.Text)
+			return false
+		}
+	}
+
+	if message.ID == "" {
+		message.ID = newMessageID("msg")
+	}
+
+	caption := liveCaption{
+		ID:         message.ID,
+		Speaker:    message.Speaker,
+		Text:       message.Text,
+		ReceivedAt: now,
+	}
+	caption.Persisted = persistCaption(*message)
+
+	ts.captions = append(ts.captions, caption)
+	if len(ts.captions) > maxTrackedCaptions {
+		ts.captions = ts.captions[len(ts.captions)-maxTrackedCaptions:]
+	}
+	return true
+}
+
+// recordCaptionUpdate resolves an update to the caption it modifies and applies it.
+// Updates that match no known caption are stored as new captions.
+func (ts *TranscriptionServer) recordCaptionUpdate(message *TranscriptionMessage) bool {
+	ts.captionsMutex.Lock()
+
+	index := -1
+	for i := len(ts.captions) - 1; i >= 0; i-- {
+		caption := ts.captions[i]
+		if message.ID != "" && caption.ID == message.ID {
+			index = i
+			break
+		}
+		if message.ID == "" && caption.Text == message.OldText && speakersMatch(caption.Speaker, message.Speaker) {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		ts.captionsMutex.Unlock()
+		log.Printf("No caption found for update of %q, storing as new message", message.OldText)
+		return ts.recordNewCaption(message)
+	}
+	defer ts.captionsMutex.Unlock()
+
+	caption := &ts.captions[index]
+	message.ID = caption.ID
+
+	// Never downgrade a known speaker to an unknown one
+	if isUnknownSpeaker(message.Speaker) {
+		message.Speaker = caption.Speaker
+	}
+
+	caption.Text = message.Text
+	caption.Speaker = message.Speaker
+
+	if caption.Persisted {
+		if _, err := UpdateTranscriptionMessage(caption.ID, message.Text, message.Speaker, parseCaptionTimestamp(message.Timestamp)); err != nil {
+			log.Printf("Failed to apply caption update %s: %v", caption.ID, err)
+		}
+	} else {
+		caption.Persisted = persistCaption(*message)
+	}
+	return true
+}
+
+// persistCaption writes a caption to the workspace of the session being recorded.
+// Returns true if it was stored.
+func persistCaption(message TranscriptionMessage) bool {
+	if isUnknownSpeaker(message.Speaker) {
+		return false
+	}
+
+	session, err := GetRecordingSession()
+	if err != nil {
+		return false
+	}
+	if session == nil {
+		log.Printf("No session is being recorded, caption %s not persisted", message.ID)
+		return false
+	}
+	// Captions received while the session is paused are not recorded
+	if session.Status == SessionStatusPaused {
+		return false
+	}
+	workspaceID := session.WorkspaceID
+
+	source := message.Source
+	if source == "" {
+		source = "google-meet"
+	}
+	messageType := message.MessageType
+	if messageType == "" {
+		messageType = "caption_update"
+	}
+
+	if _, err := CreateTranscriptionMessage(message.ID, workspaceID, message.Text, message.Speaker, source, messageType, parseCaptionTimestamp(message.Timestamp)); err != nil {
+		return false
+	}
+
+	if err := AddSessionParticipant(session.ID, message.Speaker); err != nil {
+		log.Printf("Failed to record participant %s for session %d: %v", message.Speaker, session.ID, err)
+	}
+	return true
+}
+
+func isUnknownSpeaker(speaker string) bool {
+	speaker = strings.TrimSpace(speaker)
+	return speaker == "" || speaker == "Unknown"
+}
+
+func speakersMatch(known, incoming string) bool {
+	return known == incoming || isUnknownSpeaker(known) || isUnknownSpeaker(incoming)
+}
+
+// parseCaptionTimestamp parses the extension's ISO 8601 timestamp, falling back to now
+func parseCaptionTimestamp(timestamp string) time.Time {
+	if parsed, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+		return parsed
 	}
+	return time.Now()
 }
 
 // forwardToFrontend sends transcription messages to the React frontend
@@ -180,6 +405,8 @@ func (ts *TranscriptionServer) forwardToFrontend(message TranscriptionMessage) {
 	case "new_message":
 		// Emit new transcription message to frontend
 		runtime.EventsEmit(ts.app.ctx, "transcriptionNewMessage", map[string]interface{}{
+			"id":          message.ID,
+			"workspaceId": recordingWorkspaceID(),
 			"text":        message.Text,
 			"speaker":     message.Speaker,
 			"timestamp":   message.Timestamp,
@@ -190,6 +417,8 @@ func (ts *TranscriptionServer) forwardToFrontend(message TranscriptionMessage) {
 	case "message_update":
 		// Emit updated transcription message to frontend
 		runtime.EventsEmit(ts.app.ctx, "transcriptionMessageUpdate", map[string]interface{}{
+			"id":          message.ID,
+			"workspaceId": recordingWorkspaceID(),
 			"text":        message.Text,
 			"oldText":     message.OldText,
 			"speaker":     message.Speaker,
@@ -215,6 +444,8 @@ func (ts *TranscriptionServer) forwardToFrontend(message TranscriptionMessage) {
 		} else {
 			// Treat as new message if no type is specified
 			runtime.EventsEmit(ts.app.ctx, "transcriptionNewMessage", map[string]interface{}{
+				"id":          message.ID,
+				"workspaceId": recordingWorkspaceID(),
 				"text":        message.Text,
 				"speaker":     message.Speaker,
 				"timestamp":   message.Timestamp,
@@ -268,7 +499,7 @@ func (a *App) SendTestTranscription(speaker, text string) {
 		MessageType: "caption_update",
 	}
 
-	transcriptionServer.forwardToFrontend(testMessage)
+	transcriptionServer.processMessage(testMessage)
 	log.Printf("Sent test transcription: %s from %s", text, speaker)
 }
 
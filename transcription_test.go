@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestPersistCaptionUsesRecordingWorkspace(t *testing.T) {
+	useTestDatabase(t)
+	recorded, err := CreateWorkspace("Standup", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewed, err := CreateWorkspace("Planning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{}
+	if err := app.SetActiveWorkspace(viewed.ID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { clearActiveWorkspace(viewed.ID) })
+
+	caption := TranscriptionMessage{ID: "c1", Speaker: "Alice", Text: "hello", Timestamp: "2026-10-17T09:00:00Z"}
+	if persistCaption(caption) {
+		t.Error("caption persisted with no session being recorded")
+	}
+
+	session, err := StartSession(recorded.ID, "", "google-meet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !persistCaption(caption) {
+		t.Fatal("caption not persisted during a session")
+	}
+	if messages, err := GetTranscriptionMessagesByWorkspace(recorded.ID); err != nil || len(messages) != 1 {
+		t.Errorf("recorded workspace has %d captions, %v; want 1", len(messages), err)
+	}
+	if messages, err := GetTranscriptionMessagesByWorkspace(viewed.ID); err != nil || len(messages) != 0 {
+		t.Errorf("viewed workspace has %d captions, %v; want 0", len(messages), err)
+	}
+
+	if _, err := PauseSession(session.ID); err != nil {
+		t.Fatal(err)
+	}
+	if persistCaption(TranscriptionMessage{ID: "c2", Speaker: "Alice", Text: "off the record"}) {
+		t.Error("caption persisted while the session is paused")
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// workspaceChildren are the soft-deletable models that belong to a workspace
+var workspaceChildren = []interface{}{
+	&TranscriptionRecord{},
+	&MeetingNotes{},
+	&AIChatMessage{},
+	&Session{},
+	&ActionItem{},
+	&Decision{},
+	&OpenQuestion{},
+	&MeetingSummary{},
+}
+
+// trashWorkspaceRows soft-deletes a workspace and everything that belongs to it. Its open
+// session is stopped first, so it isn't recording again once the workspace is restored.
+func trashWorkspaceRows(tx *gorm.DB, workspaceID uint) error {
+	var openSessions []uint
+	err := tx.Model(&Session{}).
+		Where("workspace_id = ? AND status IN ?", workspaceID, []string{SessionStatusActive, SessionStatusPaused}).
+		Pluck("id", &openSessions).Error
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, sessionID := range openSessions {
+		if err := closeOpenPause(tx, sessionID, now); err != nil {
+			return err
+		}
+		err := tx.Model(&Session{}).Where("id = ?", sessionID).Updates(map[string]interface{}{
+			"status":   SessionStatusStopped,
+			"end_time": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, model := range workspaceChildren {
+		if err := tx.Where("workspace_id = ?", workspaceID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Delete(&Workspace{}, workspaceID).Error
+}
+
+// requireWorkspace fails when a workspace doesn't exist or is in the trash, so nothing is
+// written to a trashed workspace
+func requireWorkspace(workspaceID uint) error {
+	var count int64
+	if err := DB.Model(&Workspace{}).Where("id = ?", workspaceID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("workspace %d not found or in the trash", workspaceID)
+	}
+	return nil
+}
+
+// ListTrash returns the workspaces in the trash, most recently deleted first
+func ListTrash() ([]Workspace, error) {
+	var workspaces []Workspace
+	result := DB.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&workspaces)
+	if result.Error != nil {
+		log.Printf("Failed to list trash: %v", result.Error)
+		return nil, result.Error
+	}
+	return workspaces, nil
+}
+
+// RestoreWorkspace takes a workspace and everything that belongs to it out of the trash
+func RestoreWorkspace(id uint) (*Workspace, error) {
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&Workspace{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("workspace %d is not in the trash", id)
+		}
+
+		for _, model := range workspaceChildren {
+			err := tx.Unscoped().Model(model).
+				Where("workspace_id = ? AND deleted_at IS NOT NULL", id).
+				Update("deleted_at", nil).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to restore workspace %d: %v", id, err)
+		return nil, err
+	}
+
+	log.Printf("Restored workspace %d from the trash", id)
+	return GetWorkspaceByID(id)
+}
+
+// PurgeTrash permanently deletes the workspaces that have been in the trash for more than
+// olderThan, or all of them when olderThan is zero. It returns how many were deleted.
+func PurgeTrash(olderThan time.Duration) (int, error) {
+	var ids []uint
+	err := DB.Unscoped().Model(&Workspace{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", time.Now().Add(-olderThan)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		log.Printf("Failed to find workspaces to purge: %v", err)
+		return 0, err
+	}
+
+	for i, id := range ids {
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			return deleteWorkspaceRows(tx, id)
+		})
+		if err != nil {
+			log.Printf("Failed to purge workspace %d: %v", id, err)
+			return i, err
+		}
+	}
+
+	if len(ids) > 0 {
+		log.Printf("Purged %d workspaces from the trash", len(ids))
+	}
+	return len(ids), nil
+}
+
+// setWorkspaceArchived archives or unarchives a workspace
+func setWorkspaceArchived(id uint, archived bool) (*Workspace, error) {
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+
+	result := DB.Model(&Workspace{}).Where("id = ?", id).Update("archived_at", archivedAt)
+	if result.Error != nil {
+		log.Printf("Failed to update archived state of workspace %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("workspace %d not found", id)
+	}
+	return GetWorkspaceByID(id)
+}
+
+// GetArchivedWorkspaces retrieves the archived workspaces, most recently archived first
+func GetArchivedWorkspaces() ([]Workspace, error) {
+	var workspaces []Workspace
+	result := DB.Where("archived_at IS NOT NULL").Order("archived_at DESC").Find(&workspaces)
+	if result.Error != nil {
+		log.Printf("Failed to get archived workspaces: %v", result.Error)
+		return nil, result.Error
+	}
+	return workspaces, nil
+}
+
+// ListTrash returns the workspaces in the trash
+func (a *App) ListTrash() ([]Workspace, error) {
+	return ListTrash()
+}
+
+// RestoreWorkspace takes a workspace out of the trash
+func (a *App) RestoreWorkspace(id uint) (*Workspace, error) {
+	return RestoreWorkspace(id)
+}
+
+// PurgeTrash permanently deletes workspaces that have been in the trash for more than
+// olderThanDays days, or all of them for 0
+func (a *App) PurgeTrash(olderThanDays int) (int, error) {
+	return PurgeTrash(time.Duration(olderThanDays) * 24 * time.Hour)
+}
+
+// ArchiveWorkspace hides a workspace from the workspace list without deleting it
+func (a *App) ArchiveWorkspace(id uint) (*Workspace, error) {
+	return setWorkspaceArchived(id, true)
+}
+
+// UnarchiveWorkspace brings an archived workspace back to the workspace list
+func (a *App) UnarchiveWorkspace(id uint) (*Workspace, error) {
+	return setWorkspaceArchived(id, false)
+}
+
+// GetArchivedWorkspaces retrieves the archived workspaces
+func (a *App) GetArchivedWorkspaces() ([]Workspace, error) {
+	return GetArchivedWorkspaces()
+}
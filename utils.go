@@ -1,8 +1,12 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 )
 
 // HealthCheckResult represents the response from the Python backend health check
@@ -35,3 +39,12 @@ func (a *App) HealthCheckForFrontend() (string, string, error) {
 	}
 	return status, message, nil
 }
+
+// newMessageID returns a unique, stable identifier such as "msg_1719570318000_9f3a2c1b"
+func newMessageID(prefix string) string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%s_%d", prefix, time.Now().UnixNano())
+	}
+	return fmt.Sprintf("%s_%d_%s", prefix, time.Now().UnixMilli(), hex.EncodeToString(buf))
+}